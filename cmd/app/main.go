@@ -1,11 +1,24 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"os"
-	"time"
-
+	// часовые пояса точек продаж не должны зависеть от образа, в котором запущено приложение
+	_ "time/tzdata"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/analytics"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/category"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/export"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/image"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/importjob"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/inventory"
 	"github.com/zuzaaa-dev/stawberry/internal/domain/service/notification"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/pricehistory"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/staff"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/store"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/token"
 	"github.com/zuzaaa-dev/stawberry/internal/domain/service/user"
 
 	"github.com/zuzaaa-dev/stawberry/internal/repository"
@@ -17,7 +30,8 @@ import (
 	"github.com/zuzaaa-dev/stawberry/internal/domain/service/offer"
 	"github.com/zuzaaa-dev/stawberry/internal/domain/service/product"
 	"github.com/zuzaaa-dev/stawberry/internal/handler"
-	objectstorage "github.com/zuzaaa-dev/stawberry/pkg/s3"
+	"github.com/zuzaaa-dev/stawberry/pkg/mailer"
+	"github.com/zuzaaa-dev/stawberry/pkg/objectstorage"
 )
 
 // Global variables for application state
@@ -59,23 +73,116 @@ func initializeApp() error {
 	// Run migrations
 	migrator.RunMigrations(db, "migrations")
 
+	// Refuse to start if models drifted from the database schema
+	if err := repository.VerifySchema(db); err != nil {
+		return fmt.Errorf("database schema does not match models:\n%w", err)
+	}
+
 	productRepository := repository.NewProductRepository(db)
+	variantRepository := repository.NewVariantRepository(db)
 	offerRepository := repository.NewOfferRepository(db)
 	userRepository := repository.NewUserRepository(db)
 	notificationRepository := repository.NewNotificationRepository(db)
+	tokenRepository := repository.NewTokenRepository(db)
+	categoryRepository := repository.NewCategoryRepository(db)
+	imageRepository := repository.NewImageRepository(db)
+	storeRepository := repository.NewStoreRepository(db)
+	importRepository := repository.NewImportRepository(db)
+	exportRepository := repository.NewExportRepository(db)
+	priceHistoryRepository := repository.NewPriceHistoryRepository(db)
+	inventoryRepository := repository.NewInventoryRepository(db)
+	analyticsRepository := repository.NewAnalyticsRepository(db)
+
+	storage, err := objectstorage.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize object storage: %w", err)
+	}
 
-	productService := product.NewProductService(productRepository)
-	offerService := offer.NewOfferService(offerRepository)
-	userService := user.NewUserService(userRepository)
+	categoryService := category.NewCategoryService(categoryRepository)
+	imageService := image.NewImageService(
+		imageRepository,
+		productRepository,
+		storeRepository,
+		storage,
+		cfg.ImageMaxSize,
+		cfg.ImageUploadTTL,
+	)
+	go func() {
+		if err := imageService.RegenerateStaleVariants(context.Background()); err != nil {
+			log.Printf("Failed to regenerate image variants: %v", err)
+		}
+	}()
+
+	productService := product.NewProductService(
+		productRepository,
+		variantRepository,
+		categoryService,
+		imageService,
+		storeRepository,
+		inventoryRepository,
+	)
+	offerService := offer.NewOfferService(offerRepository, inventoryRepository, storeRepository, userRepository)
+	tokenService := token.NewTokenService(tokenRepository, cfg.JWTSecret, cfg.RefreshTTL, cfg.AccessTTL)
+	mailSender := mailer.New(cfg)
+	userService := user.NewUserService(userRepository, tokenService, mailSender, cfg.AppURL)
 	notificationService := notification.NewNotificationService(notificationRepository)
+	importService := importjob.NewImportService(importRepository, storage, categoryService, storeRepository)
+	go importService.Run(context.Background())
+	exportService := export.NewExportService(exportRepository, storage)
+	priceHistoryService := pricehistory.NewPriceHistoryService(priceHistoryRepository)
+	go priceHistoryService.Run(context.Background())
+	inventoryService := inventory.NewInventoryService(inventoryRepository)
+	go inventoryService.Run(context.Background())
+	storeService := store.NewStoreService(
+		storeRepository,
+		inventoryRepository,
+		storage,
+		cfg.DocumentMaxSize,
+		cfg.DocumentURLTTL,
+	)
+	go storeService.Run(context.Background())
+	staffService := staff.NewStaffService(storeRepository, mailSender, cfg.AppURL)
+	analyticsService := analytics.NewAnalyticsService(analyticsRepository, storeRepository)
 
 	productHandler := handler.NewProductHandler(productService)
 	offerHandler := handler.NewOfferHandler(offerService)
-	userHandler := handler.NewUserHandler(userService, time.Hour, "api/v1", "")
+	userHandler := handler.NewUserHandler(userService, cfg.RefreshTTL, "api/v1", "")
 	notificationHandler := handler.NewNotificationHandler(notificationService)
-	s3 := objectstorage.ObjectStorageConn(cfg)
-
-	router = handler.SetupRouter(productHandler, offerHandler, userHandler, notificationHandler, s3, "api/v1")
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	imageHandler := handler.NewImageHandler(imageService)
+	importHandler := handler.NewImportHandler(importService, cfg.ImportMaxSize)
+	exportHandler := handler.NewExportHandler(exportService)
+	priceHistoryHandler := handler.NewPriceHistoryHandler(priceHistoryService)
+	inventoryHandler := handler.NewInventoryHandler(inventoryService)
+	storeHandler := handler.NewStoreHandler(storeService)
+	staffHandler := handler.NewStaffHandler(staffService)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
+
+	var signedStorage handler.SignedObjectStorage
+	if local, ok := storage.(*objectstorage.LocalStorage); ok {
+		signedStorage = local
+	}
+	storageHandler := handler.NewStorageHandler(signedStorage)
+
+	router = handler.SetupRouter(
+		productHandler,
+		offerHandler,
+		userHandler,
+		notificationHandler,
+		categoryHandler,
+		imageHandler,
+		importHandler,
+		exportHandler,
+		priceHistoryHandler,
+		inventoryHandler,
+		storeHandler,
+		staffHandler,
+		analyticsHandler,
+		userService,
+		tokenService,
+		storageHandler,
+		"api/v1",
+	)
 
 	return nil
 }
@@ -3,22 +3,45 @@ package config
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	DBHost        string
-	DBUser        string
-	DBPassword    string
-	DBName        string
-	DBPort        string
-	ServerPort    string
-	AccessKey     string
-	SecretKey     string
-	BucketName    string
-	URL           string
-	SigningRegion string
+	DBHost         string
+	DBUser         string
+	DBPassword     string
+	DBName         string
+	DBPort         string
+	ServerPort     string
+	AccessKey      string
+	SecretKey      string
+	BucketName     string
+	URL            string
+	SigningRegion  string
+	JWTSecret      string
+	AccessTTL      time.Duration
+	RefreshTTL     time.Duration
+	ImageMaxSize   int64
+	ImageUploadTTL time.Duration
+	ImportMaxSize  int64
+	// DocumentMaxSize и DocumentURLTTL ограничивают документы заявок на проверку магазинов
+	DocumentMaxSize int64
+	DocumentURLTTL  time.Duration
+	// SMTPHost сервер исходящей почты, без него письма пишутся в лог
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	MailFrom     string
+	// AppURL адрес клиентского приложения для ссылок в письмах
+	AppURL string
+	// StorageDriver выбирает объектное хранилище: s3 или local
+	StorageDriver        string
+	StorageLocalDir      string
+	StorageLocalBaseURL  string
+	StorageSigningSecret string
 }
 
 func LoadConfig() *Config {
@@ -27,22 +50,56 @@ func LoadConfig() *Config {
 	viper.SetConfigType("env")
 	viper.AutomaticEnv()
 
+	viper.SetDefault("ACCESS_TOKEN_TTL", 15*time.Minute)
+	viper.SetDefault("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+	viper.SetDefault("IMAGE_MAX_SIZE", 10<<20)
+	viper.SetDefault("IMAGE_UPLOAD_TTL", 10*time.Minute)
+	viper.SetDefault("IMPORT_MAX_SIZE", 50<<20)
+	viper.SetDefault("DOCUMENT_MAX_SIZE", 20<<20)
+	viper.SetDefault("DOCUMENT_URL_TTL", 10*time.Minute)
+	viper.SetDefault("SMTP_PORT", 587)
+	viper.SetDefault("MAIL_FROM", "no-reply@stawberry.local")
+	viper.SetDefault("APP_URL", "http://localhost:3000")
+	viper.SetDefault("STORAGE_DRIVER", "s3")
+	viper.SetDefault("STORAGE_LOCAL_DIR", "./storage")
+	viper.SetDefault("STORAGE_LOCAL_BASE_URL", "http://localhost:8080")
+
 	if err := viper.ReadInConfig(); err != nil {
 		log.Printf("Config reading failed: %v", err)
 	}
 
 	config := &Config{
-		DBHost:        viper.GetString("DB_HOST"),
-		DBUser:        viper.GetString("DB_USER"),
-		DBPassword:    viper.GetString("DB_PASSWORD"),
-		DBName:        viper.GetString("DB_NAME"),
-		DBPort:        viper.GetString("DB_PORT"),
-		ServerPort:    viper.GetString("SERVER_PORT"),
-		AccessKey:     viper.GetString("ACCESS_KEY"),
-		SecretKey:     viper.GetString("SECRET_KEY"),
-		BucketName:    viper.GetString("BUCKET_NAME"),
-		URL:           viper.GetString("URL"),
-		SigningRegion: viper.GetString("SIGNING_REGION"),
+		DBHost:          viper.GetString("DB_HOST"),
+		DBUser:          viper.GetString("DB_USER"),
+		DBPassword:      viper.GetString("DB_PASSWORD"),
+		DBName:          viper.GetString("DB_NAME"),
+		DBPort:          viper.GetString("DB_PORT"),
+		ServerPort:      viper.GetString("SERVER_PORT"),
+		AccessKey:       viper.GetString("ACCESS_KEY"),
+		SecretKey:       viper.GetString("SECRET_KEY"),
+		BucketName:      viper.GetString("BUCKET_NAME"),
+		URL:             viper.GetString("URL"),
+		SigningRegion:   viper.GetString("SIGNING_REGION"),
+		JWTSecret:       viper.GetString("JWT_SECRET"),
+		AccessTTL:       viper.GetDuration("ACCESS_TOKEN_TTL"),
+		RefreshTTL:      viper.GetDuration("REFRESH_TOKEN_TTL"),
+		ImageMaxSize:    viper.GetInt64("IMAGE_MAX_SIZE"),
+		ImageUploadTTL:  viper.GetDuration("IMAGE_UPLOAD_TTL"),
+		ImportMaxSize:   viper.GetInt64("IMPORT_MAX_SIZE"),
+		DocumentMaxSize: viper.GetInt64("DOCUMENT_MAX_SIZE"),
+		DocumentURLTTL:  viper.GetDuration("DOCUMENT_URL_TTL"),
+
+		SMTPHost:     viper.GetString("SMTP_HOST"),
+		SMTPPort:     viper.GetInt("SMTP_PORT"),
+		SMTPUsername: viper.GetString("SMTP_USERNAME"),
+		SMTPPassword: viper.GetString("SMTP_PASSWORD"),
+		MailFrom:     viper.GetString("MAIL_FROM"),
+		AppURL:       viper.GetString("APP_URL"),
+
+		StorageDriver:        viper.GetString("STORAGE_DRIVER"),
+		StorageLocalDir:      viper.GetString("STORAGE_LOCAL_DIR"),
+		StorageLocalBaseURL:  viper.GetString("STORAGE_LOCAL_BASE_URL"),
+		StorageSigningSecret: viper.GetString("STORAGE_SIGNING_SECRET"),
 	}
 
 	return config
@@ -5,18 +5,30 @@ import (
 )
 
 const (
-	NotFound       = "NOT_FOUND"
-	DatabaseError  = "DATABASE_ERROR"
-	InternalError  = "INTERNAL_ERROR"
-	DuplicateError = "DUPLICATE_ERROR"
-	BadRequest     = "BAD_REQUEST"
-	Unauthorized   = "UNAUTHORIZED"
-	InvalidToken   = "INVALID_TOKEN"
+	NotFound         = "NOT_FOUND"
+	DatabaseError    = "DATABASE_ERROR"
+	InternalError    = "INTERNAL_ERROR"
+	DuplicateError   = "DUPLICATE_ERROR"
+	BadRequest       = "BAD_REQUEST"
+	Unauthorized     = "UNAUTHORIZED"
+	InvalidToken     = "INVALID_TOKEN"
+	Forbidden        = "FORBIDDEN"
+	Validation       = "VALIDATION_ERROR"
+	UnsupportedMedia = "UNSUPPORTED_MEDIA_TYPE"
+	TooLarge         = "PAYLOAD_TOO_LARGE"
+	Conflict         = "CONFLICT"
 )
 
+// FieldError описывает ошибку валидации конкретного поля запроса.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
 type ProductError struct {
 	Code    string
 	Message string
+	Fields  []FieldError
 	Err     error
 }
 
@@ -32,9 +44,13 @@ var (
 		Code:    NotFound,
 		Message: "product not found",
 	}
-	ErrStoreNotFound = &ProductError{
+	ErrVariantNotFound = &ProductError{
 		Code:    NotFound,
-		Message: "store not found",
+		Message: "product variant not found",
+	}
+	ErrProductForbidden = &ProductError{
+		Code:    Forbidden,
+		Message: "only the product's store may change it",
 	}
 )
 
@@ -56,6 +72,26 @@ var ErrOfferNotFound = &OfferError{
 	Message: "product not found",
 }
 
+var ErrOfferStoreNotVerified = &OfferError{
+	Code:    Conflict,
+	Message: "store is not verified and cannot accept offers",
+}
+
+var ErrOfferForbidden = &OfferError{
+	Code:    Forbidden,
+	Message: "your store role does not allow handling offers",
+}
+
+var ErrOfferStoreClosed = &OfferError{
+	Code:    Conflict,
+	Message: "store is not accepting offers",
+}
+
+var ErrOfferEmailNotVerified = &OfferError{
+	Code:    Forbidden,
+	Message: "confirm your email before making offers",
+}
+
 type UserError struct {
 	Code    string
 	Message string
@@ -82,6 +118,18 @@ var (
 		Code:    InternalError,
 		Message: "failed to generate password",
 	}
+	ErrUserWrongPassword = &UserError{
+		Code:    Forbidden,
+		Message: "current password is incorrect",
+	}
+	ErrEmailVerificationNotFound = &UserError{
+		Code:    NotFound,
+		Message: "verification link is invalid or expired",
+	}
+	ErrEmailAlreadyVerified = &UserError{
+		Code:    Conflict,
+		Message: "email is already verified",
+	}
 )
 
 type TokenError struct {
@@ -127,3 +175,167 @@ var (
 		Message: "notification not found",
 	}
 )
+
+type CategoryError struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *CategoryError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+var (
+	ErrCategoryNotFound = &CategoryError{
+		Code:    NotFound,
+		Message: "category not found",
+	}
+	ErrCategoryAttributeNotFound = &CategoryError{
+		Code:    NotFound,
+		Message: "category attribute not found",
+	}
+)
+
+type ImageError struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *ImageError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+var (
+	ErrImageNotFound = &ImageError{
+		Code:    NotFound,
+		Message: "image not found",
+	}
+	ErrImageProductNotFound = &ImageError{
+		Code:    NotFound,
+		Message: "product not found",
+	}
+	ErrImageForbidden = &ImageError{
+		Code:    Forbidden,
+		Message: "only the product's store may change its images",
+	}
+)
+
+type ImportError struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *ImportError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+var (
+	ErrImportJobNotFound = &ImportError{
+		Code:    NotFound,
+		Message: "import job not found",
+	}
+	ErrImportForbidden = &ImportError{
+		Code:    Forbidden,
+		Message: "your store role does not allow importing products",
+	}
+)
+
+type StoreError struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *StoreError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+var (
+	ErrStoreNotFound = &StoreError{
+		Code:    NotFound,
+		Message: "store not found",
+	}
+	ErrStoreShopPointNotFound = &StoreError{
+		Code:    NotFound,
+		Message: "shop point not found",
+	}
+	ErrStoreForbidden = &StoreError{
+		Code:    Forbidden,
+		Message: "your store role does not allow this action",
+	}
+	ErrStoreAccountRequired = &StoreError{
+		Code:    Forbidden,
+		Message: "only store accounts may create stores",
+	}
+	ErrStoreEmailNotVerified = &StoreError{
+		Code:    Forbidden,
+		Message: "confirm your email before creating a store",
+	}
+	ErrStoreDocumentNotFound = &StoreError{
+		Code:    NotFound,
+		Message: "document not found",
+	}
+	ErrStoreMemberNotFound = &StoreError{
+		Code:    NotFound,
+		Message: "store member not found",
+	}
+	ErrStoreInvitationNotFound = &StoreError{
+		Code:    NotFound,
+		Message: "invitation not found or expired",
+	}
+)
+
+type InventoryError struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *InventoryError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+var (
+	ErrShopPointNotFound = &InventoryError{
+		Code:    NotFound,
+		Message: "shop point not found",
+	}
+	ErrInventoryItemNotFound = &InventoryError{
+		Code:    NotFound,
+		Message: "product is not stocked at this shop point",
+	}
+	ErrInventoryForbidden = &InventoryError{
+		Code:    Forbidden,
+		Message: "your store role does not allow this inventory action",
+	}
+	ErrThresholdNotFound = &InventoryError{
+		Code:    NotFound,
+		Message: "low stock threshold not found",
+	}
+	ErrInventoryStoreNotFound = &InventoryError{
+		Code:    NotFound,
+		Message: "store not found",
+	}
+	ErrScheduleExceptionNotFound = &InventoryError{
+		Code:    NotFound,
+		Message: "no special hours for this date",
+	}
+)
@@ -0,0 +1,67 @@
+package entity
+
+import "time"
+
+// Шаг, с которым аналитика магазина разбивается по времени
+const (
+	AnalyticsBucketDay  = "day"
+	AnalyticsBucketWeek = "week"
+)
+
+// Разделы аналитики, которые выгружаются в CSV по отдельности
+const (
+	AnalyticsSectionSeries   = "series"
+	AnalyticsSectionProducts = "products"
+	AnalyticsSectionPoints   = "points"
+)
+
+// AnalyticsTotals итоги по предложениям магазина за период
+type AnalyticsTotals struct {
+	Received  int     `json:"received"`
+	Accepted  int     `json:"accepted"`
+	Rejected  int     `json:"rejected"`
+	Completed int     `json:"completed"`
+	Revenue   float64 `json:"revenue"`
+	// AvgDiscount средняя скидка в принятых предложениях от цены магазина, доля от 0 до 1
+	AvgDiscount *float64 `json:"avg_discount"`
+}
+
+// AnalyticsBucket показатели за день или неделю. Полученные, принятые и отклоненные
+// предложения считаются по дате создания, выданные и выручка — по дате выдачи товара.
+type AnalyticsBucket struct {
+	Start     time.Time `json:"start"`
+	Received  int       `json:"received"`
+	Accepted  int       `json:"accepted"`
+	Rejected  int       `json:"rejected"`
+	Completed int       `json:"completed"`
+	Revenue   float64   `json:"revenue"`
+}
+
+// ProductAnalytics товар магазина по числу предложений за период
+type ProductAnalytics struct {
+	ProductID uint   `json:"product_id"`
+	Name      string `json:"name"`
+	Offers    int    `json:"offers"`
+	Accepted  int    `json:"accepted"`
+}
+
+// PointAnalytics конверсия предложений, в которых покупатель выбрал точку продаж, в выдачу товара
+type PointAnalytics struct {
+	ShopPointID uint     `json:"shop_point_id"`
+	Address     string   `json:"address"`
+	Offers      int      `json:"offers"`
+	Completed   int      `json:"completed"`
+	Conversion  *float64 `json:"conversion"`
+}
+
+// StoreAnalytics аналитика магазина за период [From, To)
+type StoreAnalytics struct {
+	StoreID     uint               `json:"store_id"`
+	From        time.Time          `json:"from"`
+	To          time.Time          `json:"to"`
+	Bucket      string             `json:"bucket"`
+	Totals      AnalyticsTotals    `json:"totals"`
+	Series      []AnalyticsBucket  `json:"series"`
+	TopProducts []ProductAnalytics `json:"top_products"`
+	Points      []PointAnalytics   `json:"points"`
+}
@@ -0,0 +1,24 @@
+package entity
+
+// ShopAvailability наличие товара в магазине. Товар в наличии, если магазин
+// не снял его с продажи (IsAvailable) и хотя бы на одной точке есть остаток.
+type ShopAvailability struct {
+	ShopID      uint                `json:"shop_id"`
+	Name        string              `json:"name"`
+	IsAvailable bool                `json:"is_available"`
+	InStock     bool                `json:"in_stock"`
+	Quantity    int                 `json:"quantity"`
+	Points      []PointAvailability `json:"points"`
+}
+
+// PointAvailability остаток товара на точке продаж по всем вариантам
+// и разброс цен тех вариантов, что есть в наличии. Состояние работы
+// заполняется только у точек с расписанием.
+type PointAvailability struct {
+	ShopPointID uint     `json:"shop_point_id"`
+	Address     string   `json:"address"`
+	Quantity    int      `json:"quantity"`
+	MinPrice    *float64 `json:"min_price"`
+	MaxPrice    *float64 `json:"max_price"`
+	*OpeningStatus
+}
@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+const (
+	FeedFormatCSV = "csv"
+	FeedFormatYML = "yml"
+)
+
+// Catalog сведения об ассортименте магазина для выгрузки.
+// UpdatedAt меняется при любом изменении товаров, цен, остатков и изображений магазина,
+// а также его настроек, потому что от них зависит валюта цен.
+type Catalog struct {
+	ShopID    uint
+	ShopName  string
+	Currency  string
+	UpdatedAt time.Time
+}
+
+type CatalogCategory struct {
+	ID       uint
+	Name     string
+	ParentID *uint
+}
+
+// CatalogProduct товар магазина со всеми позициями его вариантов на точках продаж.
+type CatalogProduct struct {
+	ID          uint
+	Name        string
+	Description string
+	CategoryID  *uint
+	Attributes  map[string]any
+	ImageKeys   []string
+	Available   bool
+	Stock       []CatalogStock
+}
+
+type CatalogStock struct {
+	VariantID   uint
+	SKU         string
+	Barcode     string
+	Options     map[string]string
+	ShopPointID uint
+	Address     string
+	Price       float64
+	Quantity    int
+}
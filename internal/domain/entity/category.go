@@ -0,0 +1,21 @@
+package entity
+
+const (
+	AttributeTypeEnum   = "enum"
+	AttributeTypeNumber = "number"
+	AttributeTypeBool   = "bool"
+	AttributeTypeText   = "text"
+)
+
+// CategoryAttribute описывает один атрибут из схемы категории.
+// CategoryID указывает на категорию, в которой атрибут объявлен,
+// для унаследованных атрибутов это один из предков.
+type CategoryAttribute struct {
+	ID            uint     `json:"id"`
+	CategoryID    uint     `json:"category_id"`
+	Name          string   `json:"name"`
+	Type          string   `json:"type"`
+	Unit          string   `json:"unit,omitempty"`
+	Required      bool     `json:"required"`
+	AllowedValues []string `json:"allowed_values,omitempty"`
+}
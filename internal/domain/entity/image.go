@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+type ProductImage struct {
+	ID          uint   `json:"id"`
+	ProductID   uint   `json:"product_id"`
+	Key         string `json:"key"`
+	URL         string `json:"url"`
+	Position    int    `json:"position"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// Srcset ссылки на уменьшенные копии по имени варианта,
+	// заполняется только когда копии соответствуют текущему оригиналу
+	Srcset       map[string]string `json:"srcset,omitempty"`
+	ETag         string            `json:"-"`
+	VariantsETag string            `json:"-"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// ImageUpload описывает подписанный запрос, которым клиент загружает
+// изображение напрямую в хранилище.
+type ImageUpload struct {
+	Key       string            `json:"key"`
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+const (
+	ImportStatusPending    = "pending"
+	ImportStatusProcessing = "processing"
+	ImportStatusCompleted  = "completed"
+	ImportStatusFailed     = "failed"
+
+	ImportFormatCSV   = "csv"
+	ImportFormatJSONL = "jsonl"
+)
+
+type ImportJob struct {
+	ID           uint              `json:"id"`
+	ShopID       uint              `json:"shop_id"`
+	UserID       uint              `json:"user_id"`
+	Format       string            `json:"format"`
+	Status       string            `json:"status"`
+	FileKey      string            `json:"-"`
+	Mapping      map[string]string `json:"mapping"`
+	TotalRows    int               `json:"total_rows"`
+	ImportedRows int               `json:"imported_rows"`
+	FailedRows   int               `json:"failed_rows"`
+	Error        string            `json:"error,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+	StartedAt    *time.Time        `json:"started_at"`
+	FinishedAt   *time.Time        `json:"finished_at"`
+}
+
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,83 @@
+package entity
+
+import "time"
+
+// InventoryItem позиция варианта товара на точке продаж
+type InventoryItem struct {
+	ShopPointID uint      `json:"shop_point_id"`
+	ProductID   uint      `json:"product_id"`
+	VariantID   uint      `json:"variant_id"`
+	ProductName string    `json:"product_name"`
+	SKU         string    `json:"sku"`
+	Price       float64   `json:"price"`
+	Quantity    int       `json:"quantity"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// InventoryMovement запись журнала движения остатков. Delta — изменение остатка,
+// Quantity и Price — остаток и цена после изменения.
+type InventoryMovement struct {
+	ID          uint      `json:"id"`
+	ShopPointID uint      `json:"shop_point_id"`
+	ProductID   uint      `json:"product_id"`
+	VariantID   uint      `json:"variant_id"`
+	Delta       int       `json:"delta"`
+	Quantity    int       `json:"quantity"`
+	Price       float64   `json:"price"`
+	Reason      string    `json:"reason"`
+	Note        string    `json:"note"`
+	UserID      *uint     `json:"user_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// LowStockThreshold порог остатка магазина для товара или для категории с подкатегориями.
+// Порог товара важнее порога категории, порог вложенной категории важнее родительской.
+type LowStockThreshold struct {
+	ID         uint      `json:"id"`
+	ShopID     uint      `json:"shop_id"`
+	ProductID  *uint     `json:"product_id"`
+	CategoryID *uint     `json:"category_id"`
+	Threshold  int       `json:"threshold"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// LowStockItem позиция, остаток которой не больше порога
+type LowStockItem struct {
+	ProductID   uint   `json:"product_id"`
+	ProductName string `json:"product_name"`
+	VariantID   uint   `json:"variant_id"`
+	SKU         string `json:"sku"`
+	Quantity    int    `json:"quantity"`
+	Threshold   int    `json:"threshold"`
+}
+
+// LowStockPoint заканчивающиеся позиции одной точки продаж
+type LowStockPoint struct {
+	ShopPointID uint           `json:"shop_point_id"`
+	Address     string         `json:"address"`
+	Items       []LowStockItem `json:"items"`
+}
+
+type LowStockReport struct {
+	ShopID      uint            `json:"shop_id"`
+	GeneratedAt time.Time       `json:"generated_at"`
+	Points      []LowStockPoint `json:"points"`
+}
+
+// LowStockAlert уменьшение остатка позиции вместе с действующим для нее порогом
+type LowStockAlert struct {
+	MovementID       uint
+	OwnerID          uint
+	ShopPointID      uint
+	Address          string
+	ProductName      string
+	SKU              string
+	Quantity         int
+	PreviousQuantity int
+	Threshold        *int
+}
+
+// Crossed сообщает, что остаток этим изменением опустился до порога или ниже
+func (a LowStockAlert) Crossed() bool {
+	return a.Threshold != nil && a.PreviousQuantity > *a.Threshold && a.Quantity <= *a.Threshold
+}
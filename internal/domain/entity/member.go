@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// Роли сотрудников магазина. Владелец у магазина один — тот, кто его создал.
+const (
+	StoreRoleOwner   = "owner"
+	StoreRoleManager = "manager"
+	StoreRoleCashier = "cashier"
+)
+
+// Действия, на которые роли дают право
+const (
+	// PermissionManageStore профиль магазина, точки продаж, проверка и сотрудники
+	PermissionManageStore = "manage_store"
+	// PermissionManageCatalog товары, цены, остатки, расписание точек, пороги и импорт
+	PermissionManageCatalog = "manage_catalog"
+	// PermissionHandleOffers предложения покупателей и выдача товара: продажа и возврат на точке
+	PermissionHandleOffers = "handle_offers"
+	// PermissionViewInventory просмотр остатков, журнала движений и отчета о заканчивающихся позициях
+	PermissionViewInventory = "view_inventory"
+	// PermissionViewAnalytics аналитика магазина по предложениям и выручке
+	PermissionViewAnalytics = "view_analytics"
+)
+
+var rolePermissions = map[string][]string{
+	StoreRoleOwner: {
+		PermissionManageStore,
+		PermissionManageCatalog,
+		PermissionHandleOffers,
+		PermissionViewInventory,
+		PermissionViewAnalytics,
+	},
+	StoreRoleManager: {
+		PermissionManageCatalog,
+		PermissionHandleOffers,
+		PermissionViewInventory,
+		PermissionViewAnalytics,
+	},
+	StoreRoleCashier: {PermissionHandleOffers, PermissionViewInventory},
+}
+
+// RoleAllows сообщает, дает ли роль право на действие. Пустая роль означает, что пользователь
+// не состоит в магазине.
+func RoleAllows(role, permission string) bool {
+	return slices.Contains(rolePermissions[role], permission)
+}
+
+// StoreMember сотрудник магазина
+type StoreMember struct {
+	StoreID   uint      `json:"store_id"`
+	UserID    uint      `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StoreInvitation приглашение в сотрудники магазина, отправленное на почту.
+// Токен из письма хранится только в виде хеша.
+type StoreInvitation struct {
+	ID         uint       `json:"id"`
+	StoreID    uint       `json:"store_id"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	InvitedBy  uint       `json:"invited_by"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
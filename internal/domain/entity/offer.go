@@ -2,14 +2,48 @@ package entity
 
 import "time"
 
+const (
+	OfferStatusPending  = "pending"
+	OfferStatusAccepted = "accepted"
+	OfferStatusRejected = "rejected"
+	// OfferStatusCompleted покупатель забрал товар по принятому предложению
+	OfferStatusCompleted = "completed"
+)
+
+// offerTransitions допустимые переходы статуса предложения
+var offerTransitions = map[string][]string{
+	OfferStatusPending:  {OfferStatusAccepted, OfferStatusRejected},
+	OfferStatusAccepted: {OfferStatusCompleted},
+}
+
+// CanTransitionOffer проверяет, что предложение можно перевести из статуса from в статус to
+func CanTransitionOffer(from, to string) bool {
+	for _, next := range offerTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
 type Offer struct {
-	ID        uint      `json:"id"`
-	UserID    uint      `json:"user_id"`
-	ProductID uint      `json:"product_id"`
-	StoreID   uint      `json:"store_id"`
-	Price     float64   `json:"price"`
+	ID        uint `json:"id"`
+	UserID    uint `json:"user_id"`
+	ProductID uint `json:"product_id"`
+	VariantID uint `json:"variant_id"`
+	StoreID   uint `json:"store_id"`
+	// ShopPointID точка, где покупатель хочет забрать товар
+	ShopPointID *uint   `json:"shop_point_id"`
+	Price       float64 `json:"price"`
+	// ListPrice цена магазина на момент предложения
+	ListPrice *float64  `json:"list_price"`
 	Status    string    `json:"status"`
 	ExpiresAt time.Time `json:"expires_at"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	// RespondedAt когда магазин впервые ответил на предложение
+	RespondedAt *time.Time `json:"responded_at"`
+	// Rating оценка магазина покупателем после принятого предложения, от 1 до 5
+	Rating      *int       `json:"rating"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// PriceStats цены товара за последние Days дней. Учитываются цены, действовавшие
+// в начале окна, и все изменения внутри него. Пустые значения — цен за окно нет.
+type PriceStats struct {
+	Days    int      `json:"days"`
+	Min     *float64 `json:"min"`
+	Max     *float64 `json:"max"`
+	Avg     *float64 `json:"avg"`
+	Changes int      `json:"changes"`
+}
+
+// PricePoint одно изменение цены. Без варианта и точки продаж это цена товара по умолчанию.
+type PricePoint struct {
+	VariantID     *uint     `json:"variant_id"`
+	ShopPointID   *uint     `json:"shop_point_id"`
+	Price         float64   `json:"price"`
+	PreviousPrice *float64  `json:"previous_price"`
+	ChangedAt     time.Time `json:"changed_at"`
+}
+
+type PriceHistory struct {
+	ProductID    uint         `json:"product_id"`
+	CurrentPrice *float64     `json:"current_price"`
+	Windows      []PriceStats `json:"windows"`
+	History      []PricePoint `json:"history"`
+}
+
+// PriceDrop снижение цены, о котором еще не разосланы уведомления.
+type PriceDrop struct {
+	ID            uint
+	ProductID     uint
+	ProductName   string
+	VariantID     *uint
+	ShopPointID   *uint
+	Address       string
+	Price         float64
+	PreviousPrice float64
+	// LowestPrice минимальная цена той же позиции за окно до этого снижения
+	LowestPrice *float64
+}
@@ -3,13 +3,21 @@ package entity
 import "time"
 
 type Product struct {
-	ID          uint      `json:"id"`
-	StoreID     uint      `json:"store_id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	Category    string    `json:"category"`
-	InStock     bool      `json:"in_stock"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint           `json:"id"`
+	StoreID     uint           `json:"store_id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Price       float64        `json:"price"`
+	Category    string         `json:"category"`
+	CategoryID  *uint          `json:"category_id"`
+	Attributes  map[string]any `json:"attributes,omitempty"`
+	// InStock, MinPrice и MaxPrice считаются по остаткам на точках продаж
+	InStock      bool               `json:"in_stock"`
+	MinPrice     *float64           `json:"min_price"`
+	MaxPrice     *float64           `json:"max_price"`
+	Availability []ShopAvailability `json:"availability"`
+	Images       []ProductImage     `json:"images"`
+	Variants     []ProductVariant   `json:"variants"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
 }
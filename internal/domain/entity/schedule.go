@@ -0,0 +1,190 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// scheduleHorizonDays насколько вперед ищется ближайшее открытие точки
+const scheduleHorizonDays = 14
+
+// Clock время суток в минутах от полуночи, в JSON — строка вида "09:30"
+type Clock int
+
+// ParseClock разбирает время суток вида "09:30" или "09:30:00"
+func ParseClock(s string) (Clock, error) {
+	for _, layout := range []string{"15:04", time.TimeOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return Clock(t.Hour()*60 + t.Minute()), nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid time of day %q", s)
+}
+
+func (c Clock) Hour() int {
+	return int(c) / 60
+}
+
+func (c Clock) Minute() int {
+	return int(c) % 60
+}
+
+func (c Clock) String() string {
+	return fmt.Sprintf("%02d:%02d", c.Hour(), c.Minute())
+}
+
+func (c Clock) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *Clock) UnmarshalText(text []byte) error {
+	parsed, err := ParseClock(string(text))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// OpeningHours период работы точки в день недели. Если точка закрывается
+// не позже, чем открывается, период продолжается за полночь.
+type OpeningHours struct {
+	Weekday  time.Weekday `json:"weekday"`
+	OpensAt  Clock        `json:"opens_at"`
+	ClosesAt Clock        `json:"closes_at"`
+}
+
+// ScheduleException особый день точки, заменяющий недельное расписание.
+// Без часов работы точка в этот день закрыта.
+type ScheduleException struct {
+	Date     string `json:"date"`
+	OpensAt  *Clock `json:"opens_at"`
+	ClosesAt *Clock `json:"closes_at"`
+	Note     string `json:"note"`
+}
+
+// OpeningStatus работает ли точка сейчас, когда закроется и когда откроется в следующий раз.
+// Время указывается в часовом поясе точки.
+type OpeningStatus struct {
+	OpenNow       bool       `json:"open_now"`
+	ClosesAt      *time.Time `json:"closes_at,omitempty"`
+	NextOpeningAt *time.Time `json:"next_opening_at,omitempty"`
+}
+
+// Schedule расписание точки продаж. Exceptions содержит только предстоящие особые дни.
+type Schedule struct {
+	ShopPointID uint                `json:"shop_point_id"`
+	Timezone    string              `json:"timezone"`
+	Hours       []OpeningHours      `json:"hours"`
+	Exceptions  []ScheduleException `json:"exceptions"`
+	*OpeningStatus
+}
+
+// Known сообщает, что у точки задано расписание
+func (s Schedule) Known() bool {
+	return len(s.Hours) > 0 || len(s.Exceptions) > 0
+}
+
+// Status вычисляет состояние точки на момент now. Для точки без расписания возвращает nil.
+func (s Schedule) Status(now time.Time) *OpeningStatus {
+	if !s.Known() {
+		return nil
+	}
+
+	status := &OpeningStatus{}
+	for _, period := range s.openPeriods(now) {
+		if period.start.After(now) {
+			start := period.start
+			status.NextOpeningAt = &start
+			break
+		}
+		if period.end.After(now) {
+			end := period.end
+			status.OpenNow = true
+			status.ClosesAt = &end
+		}
+	}
+
+	return status
+}
+
+// PickupDeadline срок, до которого можно забрать товар, если забрать его нужно не раньше earliest:
+// конец периода работы, идущего в этот момент, или следующего за ним.
+// Возвращает false, если в ближайшие две недели точка не работает.
+func (s Schedule) PickupDeadline(earliest time.Time) (time.Time, bool) {
+	for _, period := range s.openPeriods(earliest) {
+		if period.end.After(earliest) {
+			return period.end, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+type openPeriod struct {
+	start time.Time
+	end   time.Time
+}
+
+// openPeriods периоды работы с дня накануне from на scheduleHorizonDays вперед
+// по порядку, смежные и пересекающиеся периоды слиты
+func (s Schedule) openPeriods(from time.Time) []openPeriod {
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	local := from.In(location)
+
+	exceptions := make(map[string]ScheduleException, len(s.Exceptions))
+	for _, exception := range s.Exceptions {
+		exceptions[exception.Date] = exception
+	}
+
+	var periods []openPeriod
+	for offset := -1; offset <= scheduleHorizonDays; offset++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, location)
+		if exception, ok := exceptions[day.Format(time.DateOnly)]; ok {
+			if exception.OpensAt != nil && exception.ClosesAt != nil {
+				periods = append(periods, newOpenPeriod(day, *exception.OpensAt, *exception.ClosesAt))
+			}
+			continue
+		}
+
+		for _, hours := range s.Hours {
+			if hours.Weekday == day.Weekday() {
+				periods = append(periods, newOpenPeriod(day, hours.OpensAt, hours.ClosesAt))
+			}
+		}
+	}
+
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i].start.Before(periods[j].start)
+	})
+
+	merged := periods[:0]
+	for _, period := range periods {
+		if last := len(merged) - 1; last >= 0 && !period.start.After(merged[last].end) {
+			if period.end.After(merged[last].end) {
+				merged[last].end = period.end
+			}
+			continue
+		}
+		merged = append(merged, period)
+	}
+
+	return merged
+}
+
+func newOpenPeriod(day time.Time, opens, closes Clock) openPeriod {
+	closeDay := day.Day()
+	if closes <= opens {
+		closeDay++
+	}
+
+	return openPeriod{
+		start: time.Date(day.Year(), day.Month(), day.Day(), opens.Hour(), opens.Minute(), 0, 0, day.Location()),
+		end:   time.Date(day.Year(), day.Month(), closeDay, closes.Hour(), closes.Minute(), 0, 0, day.Location()),
+	}
+}
@@ -0,0 +1,23 @@
+package entity
+
+// ShopPoint точка продаж магазина. Координаты заданы не у всех точек,
+// состояние работы заполняется только у точек с расписанием.
+type ShopPoint struct {
+	ID        uint     `json:"id"`
+	ShopID    uint     `json:"shop_id"`
+	Address   string   `json:"address"`
+	Phone     string   `json:"phone"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	Timezone  string   `json:"timezone"`
+	*OpeningStatus
+}
+
+// NearbyShopPoint точка продаж с расстоянием до места поиска. Quantity — остаток
+// искомого товара на точке, заполняется только при поиске по товару.
+type NearbyShopPoint struct {
+	ShopPoint
+	ShopName   string  `json:"shop_name"`
+	DistanceKm float64 `json:"distance_km"`
+	Quantity   *int    `json:"quantity,omitempty"`
+}
@@ -1,13 +1,81 @@
 package entity
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
-// тут тоже надо поправить, когда store будут реализовывать
+// Store магазин. Points заполняется только при получении одного магазина.
 type Store struct {
-	ID          uint
-	Name        string
-	Description string
-	Products    []Product
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID          uint   `json:"id"`
+	UserID      uint   `json:"user_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// VerificationStatus этап проверки магазина, в каталоге видны только проверенные магазины
+	VerificationStatus string      `json:"verification_status"`
+	Points             []ShopPoint `json:"points,omitempty"`
+	CatalogUpdatedAt   time.Time   `json:"catalog_updated_at"`
+	CreatedAt          time.Time   `json:"created_at"`
+	UpdatedAt          time.Time   `json:"updated_at"`
+}
+
+// StoreStats статистика магазина по предложениям на момент RefreshedAt.
+// Средние пустые, пока их не из чего посчитать.
+type StoreStats struct {
+	OfferCount int `json:"offer_count"`
+	// AvgResponseSeconds среднее время от предложения до первого ответа магазина
+	AvgResponseSeconds *float64 `json:"avg_response_seconds"`
+	// AcceptanceRate доля принятых среди предложений, на которые магазин ответил или которые истекли
+	AcceptanceRate *float64   `json:"acceptance_rate"`
+	AvgRating      *float64   `json:"avg_rating"`
+	RatingCount    int        `json:"rating_count"`
+	RefreshedAt    *time.Time `json:"refreshed_at"`
+}
+
+// ProfilePoint точка продаж в профиле магазина вместе с расписанием
+type ProfilePoint struct {
+	ShopPoint
+	Hours      []OpeningHours      `json:"hours"`
+	Exceptions []ScheduleException `json:"exceptions"`
+}
+
+// StoreProfile публичный профиль проверенного магазина для покупателей
+type StoreProfile struct {
+	ID           uint           `json:"id"`
+	Name         string         `json:"name"`
+	Description  string         `json:"description"`
+	Points       []ProfilePoint `json:"points"`
+	ProductCount int            `json:"product_count"`
+	Stats        StoreStats     `json:"stats"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+// StoreSettings правила, по которым магазин торгуется с покупателями
+type StoreSettings struct {
+	StoreID uint `json:"store_id"`
+	// OfferTTLMinutes сколько как минимум действует предложение покупателя
+	OfferTTLMinutes int `json:"offer_ttl_minutes"`
+	// MaxRounds сколько предложений подряд покупатель может сделать на один товар,
+	// пока магазин не примет одно из них. Пустое значение снимает ограничение.
+	MaxRounds *int `json:"max_rounds"`
+	// MaxDiscount наибольшая скидка от цены магазина, доля от 0 до 1. Предложения дешевле
+	// не принимаются. Пустое значение снимает ограничение.
+	MaxDiscount   *float64  `json:"max_discount"`
+	AcceptsOffers bool      `json:"accepts_offers"`
+	Currency      string    `json:"currency"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (s StoreSettings) OfferTTL() time.Duration {
+	return time.Duration(s.OfferTTLMinutes) * time.Minute
+}
+
+// MinOfferPrice наименьшая цена предложения, которую допускает скидка maxDiscount,
+// округленная до копеек. Без ограничения скидки возвращает 0.
+func MinOfferPrice(listPrice float64, maxDiscount *float64) float64 {
+	if maxDiscount == nil {
+		return 0
+	}
+
+	return math.Round(listPrice*(1-*maxDiscount)*100) / 100
 }
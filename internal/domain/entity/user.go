@@ -1,10 +1,25 @@
 package entity
 
+import "time"
+
 type User struct {
 	ID       uint   `json:"id"`
 	Name     string `json:"name"`
-	Password string `json:"password"`
+	Password string `json:"-"`
 	Email    string `json:"email"`
 	Phone    string `json:"phone"`
 	IsStore  bool   `json:"is_store"`
+	IsAdmin  bool   `json:"is_admin"`
+	// EmailVerifiedAt пустое, пока пользователь не подтвердил текущую почту
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+}
+
+// EmailVerification ссылка подтверждения почты, отправленная пользователю
+type EmailVerification struct {
+	ID        uint       `json:"id"`
+	UserID    uint       `json:"user_id"`
+	Email     string     `json:"email"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
 }
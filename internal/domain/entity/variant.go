@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// ProductVariant вариант товара, например фасовка 250 г или 1 кг.
+// Цена и остаток на точках продаж ведутся по вариантам, у каждого товара есть хотя бы один.
+type ProductVariant struct {
+	ID        uint              `json:"id"`
+	ProductID uint              `json:"product_id"`
+	SKU       string            `json:"sku"`
+	Barcode   string            `json:"barcode"`
+	Options   map[string]string `json:"options"`
+	Position  int               `json:"position"`
+	CreatedAt time.Time         `json:"created_at"`
+}
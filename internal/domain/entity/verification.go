@@ -0,0 +1,56 @@
+package entity
+
+import "time"
+
+// Этапы проверки магазина. Новый магазин заполняет заявку в draft, после отправки
+// ждет решения в submitted. Отклоненную заявку можно исправить и отправить снова,
+// проверенный магазин администратор может приостановить и затем вернуть.
+const (
+	VerificationDraft     = "draft"
+	VerificationSubmitted = "submitted"
+	VerificationVerified  = "verified"
+	VerificationRejected  = "rejected"
+	VerificationSuspended = "suspended"
+)
+
+// StoreVerification заявка на проверку магазина: юридические данные, документы
+// и решение администратора. Comment объясняет отказ или приостановку.
+type StoreVerification struct {
+	StoreID      uint            `json:"store_id"`
+	OwnerID      uint            `json:"owner_id"`
+	Status       string          `json:"status"`
+	LegalName    string          `json:"legal_name"`
+	TaxID        string          `json:"tax_id"`
+	LegalAddress string          `json:"legal_address"`
+	Comment      string          `json:"comment"`
+	SubmittedAt  *time.Time      `json:"submitted_at"`
+	ReviewedAt   *time.Time      `json:"reviewed_at"`
+	Documents    []StoreDocument `json:"documents"`
+}
+
+// Editable сообщает, что владелец может менять данные заявки
+func (v StoreVerification) Editable() bool {
+	return v.Status == VerificationDraft || v.Status == VerificationRejected
+}
+
+// StoreDocument документ заявки в хранилище. URL — временная ссылка на чтение.
+type StoreDocument struct {
+	ID          uint      `json:"id"`
+	StoreID     uint      `json:"store_id"`
+	Kind        string    `json:"kind"`
+	Key         string    `json:"key"`
+	URL         string    `json:"url,omitempty"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// DocumentUpload описывает подписанный запрос, которым владелец загружает
+// документ напрямую в хранилище.
+type DocumentUpload struct {
+	Key       string            `json:"key"`
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
@@ -0,0 +1,163 @@
+package analytics
+
+import (
+	"context"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+const (
+	// maxRange самый длинный период, за который строится аналитика
+	maxRange = 366 * 24 * time.Hour
+	// topProductsLimit сколько товаров попадает в список самых востребованных
+	topProductsLimit = 10
+)
+
+type Repository interface {
+	// SelectSeries возвращает показатели по дням или неделям, пустые промежутки не возвращаются
+	SelectSeries(ctx context.Context, filter Filter) ([]entity.AnalyticsBucket, error)
+	// GetAvgDiscount возвращает среднюю скидку в принятых предложениях, nil если их нет
+	GetAvgDiscount(ctx context.Context, filter Filter) (*float64, error)
+	SelectTopProducts(ctx context.Context, filter Filter, limit int) ([]entity.ProductAnalytics, error)
+	// SelectPoints возвращает все точки магазина с числом предложений и выдач за период
+	SelectPoints(ctx context.Context, filter Filter) ([]entity.PointAnalytics, error)
+}
+
+type RoleChecker interface {
+	GetStoreByID(ctx context.Context, storeID uint) (entity.Store, error)
+	// GetMemberRole возвращает роль пользователя в магазине, пустую строку если он не сотрудник
+	GetMemberRole(ctx context.Context, shopID, userID uint) (string, error)
+}
+
+type analyticsService struct {
+	analyticsRepository Repository
+	roleChecker         RoleChecker
+}
+
+func NewAnalyticsService(analyticsRepository Repository, roleChecker RoleChecker) *analyticsService {
+	return &analyticsService{
+		analyticsRepository: analyticsRepository,
+		roleChecker:         roleChecker,
+	}
+}
+
+// GetStoreAnalytics строит аналитику магазина за период. Ряд по времени непрерывный:
+// дни или недели без предложений и выдач попадают в него с нулями.
+func (s *analyticsService) GetStoreAnalytics(
+	ctx context.Context,
+	userID uint,
+	filter Filter,
+) (entity.StoreAnalytics, error) {
+	if filter.Bucket != entity.AnalyticsBucketDay && filter.Bucket != entity.AnalyticsBucketWeek {
+		return entity.StoreAnalytics{}, badRequest("bucket must be day or week")
+	}
+	if !filter.From.Before(filter.To) {
+		return entity.StoreAnalytics{}, badRequest("from must be before to")
+	}
+	if filter.To.Sub(filter.From) > maxRange {
+		return entity.StoreAnalytics{}, badRequest("period must not be longer than a year")
+	}
+
+	if err := s.checkPermission(ctx, userID, filter.StoreID); err != nil {
+		return entity.StoreAnalytics{}, err
+	}
+
+	buckets, err := s.analyticsRepository.SelectSeries(ctx, filter)
+	if err != nil {
+		return entity.StoreAnalytics{}, err
+	}
+
+	avgDiscount, err := s.analyticsRepository.GetAvgDiscount(ctx, filter)
+	if err != nil {
+		return entity.StoreAnalytics{}, err
+	}
+
+	products, err := s.analyticsRepository.SelectTopProducts(ctx, filter, topProductsLimit)
+	if err != nil {
+		return entity.StoreAnalytics{}, err
+	}
+
+	points, err := s.analyticsRepository.SelectPoints(ctx, filter)
+	if err != nil {
+		return entity.StoreAnalytics{}, err
+	}
+	for i := range points {
+		if points[i].Offers > 0 {
+			conversion := float64(points[i].Completed) / float64(points[i].Offers)
+			points[i].Conversion = &conversion
+		}
+	}
+
+	series := fillSeries(buckets, filter)
+	totals := entity.AnalyticsTotals{AvgDiscount: avgDiscount}
+	for _, bucket := range series {
+		totals.Received += bucket.Received
+		totals.Accepted += bucket.Accepted
+		totals.Rejected += bucket.Rejected
+		totals.Completed += bucket.Completed
+		totals.Revenue += bucket.Revenue
+	}
+
+	return entity.StoreAnalytics{
+		StoreID:     filter.StoreID,
+		From:        filter.From,
+		To:          filter.To,
+		Bucket:      filter.Bucket,
+		Totals:      totals,
+		Series:      series,
+		TopProducts: products,
+		Points:      points,
+	}, nil
+}
+
+// fillSeries дополняет ряд пустыми днями или неделями от начала периода до конца.
+// Недели начинаются с понедельника, как в date_trunc, поэтому первая может начаться раньше From.
+func fillSeries(buckets []entity.AnalyticsBucket, filter Filter) []entity.AnalyticsBucket {
+	step := 24 * time.Hour
+	start := filter.From.Truncate(24 * time.Hour)
+	if filter.Bucket == entity.AnalyticsBucketWeek {
+		step *= 7
+		start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+	}
+
+	byStart := make(map[int64]entity.AnalyticsBucket, len(buckets))
+	for _, bucket := range buckets {
+		byStart[bucket.Start.Unix()] = bucket
+	}
+
+	var series []entity.AnalyticsBucket
+	for t := start; t.Before(filter.To); t = t.Add(step) {
+		bucket, ok := byStart[t.Unix()]
+		if !ok {
+			bucket = entity.AnalyticsBucket{Start: t}
+		}
+		series = append(series, bucket)
+	}
+
+	return series
+}
+
+func (s *analyticsService) checkPermission(ctx context.Context, userID, storeID uint) error {
+	if _, err := s.roleChecker.GetStoreByID(ctx, storeID); err != nil {
+		return err
+	}
+
+	role, err := s.roleChecker.GetMemberRole(ctx, storeID, userID)
+	if err != nil {
+		return err
+	}
+	if !entity.RoleAllows(role, entity.PermissionViewAnalytics) {
+		return apperror.ErrStoreForbidden
+	}
+
+	return nil
+}
+
+func badRequest(message string) error {
+	return &apperror.StoreError{
+		Code:    apperror.BadRequest,
+		Message: message,
+	}
+}
@@ -0,0 +1,62 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+// WriteCSV выгружает один раздел аналитики в CSV
+func (s *analyticsService) WriteCSV(storeAnalytics entity.StoreAnalytics, section string, w io.Writer) error {
+	var records [][]string
+	switch section {
+	case entity.AnalyticsSectionProducts:
+		records = append(records, []string{"product_id", "name", "offers", "accepted"})
+		for _, p := range storeAnalytics.TopProducts {
+			records = append(records, []string{
+				strconv.FormatUint(uint64(p.ProductID), 10),
+				p.Name,
+				strconv.Itoa(p.Offers),
+				strconv.Itoa(p.Accepted),
+			})
+		}
+	case entity.AnalyticsSectionPoints:
+		records = append(records, []string{"shop_point_id", "address", "offers", "completed", "conversion"})
+		for _, p := range storeAnalytics.Points {
+			records = append(records, []string{
+				strconv.FormatUint(uint64(p.ShopPointID), 10),
+				p.Address,
+				strconv.Itoa(p.Offers),
+				strconv.Itoa(p.Completed),
+				formatOptional(p.Conversion),
+			})
+		}
+	case entity.AnalyticsSectionSeries:
+		records = append(records, []string{"start", "received", "accepted", "rejected", "completed", "revenue"})
+		for _, b := range storeAnalytics.Series {
+			records = append(records, []string{
+				b.Start.Format(time.DateOnly),
+				strconv.Itoa(b.Received),
+				strconv.Itoa(b.Accepted),
+				strconv.Itoa(b.Rejected),
+				strconv.Itoa(b.Completed),
+				strconv.FormatFloat(b.Revenue, 'f', 2, 64),
+			})
+		}
+	default:
+		return badRequest("section must be one of series, products, points")
+	}
+
+	return csv.NewWriter(w).WriteAll(records)
+}
+
+func formatOptional(value *float64) string {
+	if value == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*value, 'f', 4, 64)
+}
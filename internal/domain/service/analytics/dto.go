@@ -0,0 +1,11 @@
+package analytics
+
+import "time"
+
+// Filter период [From, To) и шаг разбиения аналитики магазина
+type Filter struct {
+	StoreID uint      `json:"store_id"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Bucket  string    `json:"bucket"`
+}
@@ -0,0 +1,200 @@
+package category
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+type Repository interface {
+	CategoryExists(ctx context.Context, categoryID uint) (bool, error)
+	SelectCategorySchema(ctx context.Context, categoryID uint) ([]entity.CategoryAttribute, error)
+	UpsertCategoryAttribute(ctx context.Context, attribute Attribute) (entity.CategoryAttribute, error)
+	DeleteCategoryAttribute(ctx context.Context, categoryID uint, name string) error
+}
+
+type categoryService struct {
+	categoryRepository Repository
+}
+
+func NewCategoryService(categoryRepo Repository) *categoryService {
+	return &categoryService{categoryRepository: categoryRepo}
+}
+
+// GetCategorySchema возвращает схему атрибутов категории вместе с атрибутами,
+// унаследованными от родительских категорий.
+func (cs *categoryService) GetCategorySchema(
+	ctx context.Context,
+	categoryID uint,
+) ([]entity.CategoryAttribute, error) {
+	exists, err := cs.categoryRepository.CategoryExists(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, apperror.ErrCategoryNotFound
+	}
+
+	return cs.categoryRepository.SelectCategorySchema(ctx, categoryID)
+}
+
+// SetCategoryAttribute создает или заменяет атрибут в схеме категории.
+func (cs *categoryService) SetCategoryAttribute(
+	ctx context.Context,
+	attribute Attribute,
+) (entity.CategoryAttribute, error) {
+	attribute.Name = strings.TrimSpace(attribute.Name)
+	if err := validateAttributeDefinition(attribute); err != nil {
+		return entity.CategoryAttribute{}, err
+	}
+
+	exists, err := cs.categoryRepository.CategoryExists(ctx, attribute.CategoryID)
+	if err != nil {
+		return entity.CategoryAttribute{}, err
+	}
+	if !exists {
+		return entity.CategoryAttribute{}, apperror.ErrCategoryNotFound
+	}
+
+	return cs.categoryRepository.UpsertCategoryAttribute(ctx, attribute)
+}
+
+func (cs *categoryService) DeleteCategoryAttribute(
+	ctx context.Context,
+	categoryID uint,
+	name string,
+) error {
+	return cs.categoryRepository.DeleteCategoryAttribute(ctx, categoryID, name)
+}
+
+// ValidateAttributes проверяет атрибуты товара по схеме категории.
+// Если у категории нет схемы, атрибуты принимаются как есть.
+func (cs *categoryService) ValidateAttributes(
+	ctx context.Context,
+	categoryID uint,
+	attributes map[string]any,
+) ([]apperror.FieldError, error) {
+	schema, err := cs.GetCategorySchema(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ValidateAttributes(schema, attributes), nil
+}
+
+// ValidateAttributes сверяет значения атрибутов со схемой и возвращает
+// ошибки по каждому полю. Пустая схема ничего не ограничивает.
+func ValidateAttributes(schema []entity.CategoryAttribute, attributes map[string]any) []apperror.FieldError {
+	if len(schema) == 0 {
+		return nil
+	}
+
+	var fieldErrors []apperror.FieldError
+	known := make(map[string]struct{}, len(schema))
+	for _, attr := range schema {
+		known[attr.Name] = struct{}{}
+
+		value, ok := attributes[attr.Name]
+		if !ok || value == nil {
+			if attr.Required {
+				fieldErrors = append(fieldErrors, fieldError(attr.Name, "attribute is required"))
+			}
+			continue
+		}
+
+		if msg := checkAttributeValue(attr, value); msg != "" {
+			fieldErrors = append(fieldErrors, fieldError(attr.Name, msg))
+		}
+	}
+
+	unknown := make([]string, 0)
+	for name := range attributes {
+		if _, ok := known[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		fieldErrors = append(fieldErrors, fieldError(name, "attribute is not defined for this category"))
+	}
+
+	return fieldErrors
+}
+
+func checkAttributeValue(attr entity.CategoryAttribute, value any) string {
+	switch attr.Type {
+	case entity.AttributeTypeNumber:
+		if _, ok := value.(float64); !ok {
+			return "must be a number"
+		}
+	case entity.AttributeTypeBool:
+		if _, ok := value.(bool); !ok {
+			return "must be a boolean"
+		}
+	case entity.AttributeTypeText:
+		s, ok := value.(string)
+		if !ok {
+			return "must be a string"
+		}
+		if strings.TrimSpace(s) == "" && attr.Required {
+			return "must not be empty"
+		}
+	case entity.AttributeTypeEnum:
+		s, ok := value.(string)
+		if !ok {
+			return "must be a string"
+		}
+		for _, allowed := range attr.AllowedValues {
+			if s == allowed {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of: %s", strings.Join(attr.AllowedValues, ", "))
+	}
+
+	return ""
+}
+
+func validateAttributeDefinition(attribute Attribute) error {
+	if attribute.Name == "" {
+		return &apperror.CategoryError{
+			Code:    apperror.BadRequest,
+			Message: "attribute name is required",
+		}
+	}
+
+	switch attribute.Type {
+	case entity.AttributeTypeEnum:
+		if len(attribute.AllowedValues) == 0 {
+			return &apperror.CategoryError{
+				Code:    apperror.BadRequest,
+				Message: "enum attribute requires allowed values",
+			}
+		}
+	case entity.AttributeTypeNumber, entity.AttributeTypeBool, entity.AttributeTypeText:
+		if len(attribute.AllowedValues) > 0 {
+			return &apperror.CategoryError{
+				Code:    apperror.BadRequest,
+				Message: "allowed values are supported only for enum attributes",
+			}
+		}
+	default:
+		return &apperror.CategoryError{
+			Code:    apperror.BadRequest,
+			Message: "unknown attribute type",
+		}
+	}
+
+	return nil
+}
+
+func fieldError(name, msg string) apperror.FieldError {
+	return apperror.FieldError{
+		Field:   "attributes." + name,
+		Message: msg,
+	}
+}
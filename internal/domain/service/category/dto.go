@@ -0,0 +1,10 @@
+package category
+
+type Attribute struct {
+	CategoryID    uint     `json:"category_id"`
+	Name          string   `json:"name"`
+	Type          string   `json:"type"`
+	Unit          string   `json:"unit"`
+	Required      bool     `json:"required"`
+	AllowedValues []string `json:"allowed_values"`
+}
@@ -0,0 +1,133 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+// csvHeader совпадает с полями импорта, поэтому выгрузку можно загрузить обратно.
+// Товар без позиций на точках продаж выгружается одной строкой без цены.
+var csvHeader = []string{
+	"product_id",
+	"name",
+	"description",
+	"category_path",
+	"attributes",
+	"sku",
+	"barcode",
+	"options",
+	"shop_point_id",
+	"address",
+	"price",
+	"quantity",
+	"available",
+	"images",
+}
+
+func (s *exportService) writeCSV(
+	ctx context.Context,
+	catalog entity.Catalog,
+	categories []entity.CatalogCategory,
+	w io.Writer,
+) error {
+	paths := categoryPaths(categories)
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	err := s.exportRepository.StreamCatalogProducts(ctx, catalog.ShopID, func(p entity.CatalogProduct) error {
+		var category string
+		if p.CategoryID != nil {
+			category = paths[*p.CategoryID]
+		}
+
+		var attributes string
+		if len(p.Attributes) > 0 {
+			raw, err := json.Marshal(p.Attributes)
+			if err != nil {
+				return err
+			}
+			attributes = string(raw)
+		}
+
+		images := strings.Join(s.imageURLs(p.ImageKeys), " ")
+		record := []string{
+			strconv.FormatUint(uint64(p.ID), 10),
+			p.Name,
+			p.Description,
+			category,
+			attributes,
+		}
+
+		if len(p.Stock) == 0 {
+			return writer.Write(append(record, "", "", "", "", "", "", "", strconv.FormatBool(false), images))
+		}
+
+		for _, stock := range p.Stock {
+			var options string
+			if len(stock.Options) > 0 {
+				raw, err := json.Marshal(stock.Options)
+				if err != nil {
+					return err
+				}
+				options = string(raw)
+			}
+
+			row := append(record[:len(record):len(record)],
+				stock.SKU,
+				stock.Barcode,
+				options,
+				strconv.FormatUint(uint64(stock.ShopPointID), 10),
+				stock.Address,
+				strconv.FormatFloat(stock.Price, 'f', 2, 64),
+				strconv.Itoa(stock.Quantity),
+				strconv.FormatBool(p.Available && stock.Quantity > 0),
+				images,
+			)
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+
+		return writer.Error()
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// categoryPaths строит пути категорий вида "Ягоды/Клубника" от корня.
+func categoryPaths(categories []entity.CatalogCategory) map[uint]string {
+	byID := make(map[uint]entity.CatalogCategory, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+
+	paths := make(map[uint]string, len(categories))
+	for _, c := range categories {
+		names := []string{c.Name}
+		seen := map[uint]struct{}{c.ID: {}}
+		for parent := c.ParentID; parent != nil; {
+			p, ok := byID[*parent]
+			if _, loop := seen[*parent]; !ok || loop {
+				break
+			}
+			seen[p.ID] = struct{}{}
+			names = append([]string{p.Name}, names...)
+			parent = p.ParentID
+		}
+		paths[c.ID] = strings.Join(names, "/")
+	}
+
+	return paths
+}
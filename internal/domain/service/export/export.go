@@ -0,0 +1,73 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+type Repository interface {
+	GetCatalog(ctx context.Context, shopID uint) (entity.Catalog, error)
+	SelectCatalogCategories(ctx context.Context, shopID uint) ([]entity.CatalogCategory, error)
+	// StreamCatalogProducts вызывает fn для каждого товара магазина по порядку айди,
+	// не загружая весь ассортимент в память
+	StreamCatalogProducts(ctx context.Context, shopID uint, fn func(entity.CatalogProduct) error) error
+}
+
+type ObjectStorage interface {
+	ObjectURL(objectKey string) string
+}
+
+type exportService struct {
+	exportRepository Repository
+	storage          ObjectStorage
+}
+
+func NewExportService(exportRepo Repository, storage ObjectStorage) *exportService {
+	return &exportService{
+		exportRepository: exportRepo,
+		storage:          storage,
+	}
+}
+
+// GetCatalog возвращает сведения о каталоге магазина, в том числе время его изменения.
+func (s *exportService) GetCatalog(ctx context.Context, shopID uint) (entity.Catalog, error) {
+	return s.exportRepository.GetCatalog(ctx, shopID)
+}
+
+// WriteFeed пишет выгрузку каталога в w по мере чтения товаров из базы.
+func (s *exportService) WriteFeed(
+	ctx context.Context,
+	catalog entity.Catalog,
+	format string,
+	w io.Writer,
+) error {
+	categories, err := s.exportRepository.SelectCatalogCategories(ctx, catalog.ShopID)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case entity.FeedFormatCSV:
+		return s.writeCSV(ctx, catalog, categories, w)
+	case entity.FeedFormatYML:
+		return s.writeYML(ctx, catalog, categories, w)
+	default:
+		return &apperror.StoreError{
+			Code:    apperror.BadRequest,
+			Message: fmt.Sprintf("unsupported feed format %q", format),
+		}
+	}
+}
+
+func (s *exportService) imageURLs(keys []string) []string {
+	urls := make([]string, 0, len(keys))
+	for _, key := range keys {
+		urls = append(urls, s.storage.ObjectURL(key))
+	}
+
+	return urls
+}
@@ -0,0 +1,206 @@
+package export
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+// ymlDateFormat формат атрибута date корневого элемента YML
+const ymlDateFormat = "2006-01-02T15:04-07:00"
+
+type ymlCategory struct {
+	XMLName  xml.Name `xml:"category"`
+	ID       uint     `xml:"id,attr"`
+	ParentID *uint    `xml:"parentId,attr,omitempty"`
+	Name     string   `xml:",chardata"`
+}
+
+type ymlOutlet struct {
+	ID      uint `xml:"id,attr"`
+	InStock int  `xml:"instock,attr"`
+}
+
+type ymlParam struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ymlOffer одно предложение фида. Цены вариантов и точек продаж могут отличаться,
+// поэтому каждая позиция варианта на точке выгружается отдельным предложением
+// с общим group_id товара.
+type ymlOffer struct {
+	XMLName     xml.Name    `xml:"offer"`
+	ID          string      `xml:"id,attr"`
+	GroupID     uint        `xml:"group_id,attr"`
+	Available   bool        `xml:"available,attr"`
+	Name        string      `xml:"name"`
+	VendorCode  string      `xml:"vendorCode,omitempty"`
+	Price       string      `xml:"price"`
+	CurrencyID  string      `xml:"currencyId"`
+	CategoryID  *uint       `xml:"categoryId,omitempty"`
+	Pictures    []string    `xml:"picture"`
+	Barcode     string      `xml:"barcode,omitempty"`
+	Description string      `xml:"description,omitempty"`
+	Outlets     []ymlOutlet `xml:"outlets>outlet"`
+	Params      []ymlParam  `xml:"param"`
+}
+
+func (s *exportService) writeYML(
+	ctx context.Context,
+	catalog entity.Catalog,
+	categories []entity.CatalogCategory,
+	w io.Writer,
+) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	root := xml.StartElement{
+		Name: xml.Name{Local: "yml_catalog"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "date"}, Value: catalog.UpdatedAt.Format(ymlDateFormat)}},
+	}
+	shop := xml.StartElement{Name: xml.Name{Local: "shop"}}
+	if err := encodeTokens(enc, root, shop); err != nil {
+		return err
+	}
+
+	if err := enc.EncodeElement(catalog.ShopName, xml.StartElement{Name: xml.Name{Local: "name"}}); err != nil {
+		return err
+	}
+	if err := enc.EncodeElement(catalog.ShopName, xml.StartElement{Name: xml.Name{Local: "company"}}); err != nil {
+		return err
+	}
+
+	currencies := xml.StartElement{Name: xml.Name{Local: "currencies"}}
+	currency := xml.StartElement{
+		Name: xml.Name{Local: "currency"},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "id"}, Value: catalog.Currency},
+			{Name: xml.Name{Local: "rate"}, Value: "1"},
+		},
+	}
+	if err := encodeTokens(enc, currencies, currency, currency.End(), currencies.End()); err != nil {
+		return err
+	}
+
+	categoriesElem := xml.StartElement{Name: xml.Name{Local: "categories"}}
+	if err := enc.EncodeToken(categoriesElem); err != nil {
+		return err
+	}
+	for _, c := range categories {
+		if err := enc.Encode(ymlCategory{ID: c.ID, ParentID: c.ParentID, Name: c.Name}); err != nil {
+			return err
+		}
+	}
+	if err := enc.EncodeToken(categoriesElem.End()); err != nil {
+		return err
+	}
+
+	offers := xml.StartElement{Name: xml.Name{Local: "offers"}}
+	if err := enc.EncodeToken(offers); err != nil {
+		return err
+	}
+
+	err := s.exportRepository.StreamCatalogProducts(ctx, catalog.ShopID, func(p entity.CatalogProduct) error {
+		// без цены предложение в YML недопустимо
+		if len(p.Stock) == 0 {
+			return nil
+		}
+
+		pictures := s.imageURLs(p.ImageKeys)
+		params := ymlParams(p.Attributes)
+		for _, stock := range p.Stock {
+			offer := ymlOffer{
+				ID:          fmt.Sprintf("%d-%d-%d", p.ID, stock.VariantID, stock.ShopPointID),
+				GroupID:     p.ID,
+				Available:   p.Available && stock.Quantity > 0,
+				Name:        p.Name,
+				VendorCode:  stock.SKU,
+				Price:       strconv.FormatFloat(stock.Price, 'f', 2, 64),
+				CurrencyID:  catalog.Currency,
+				CategoryID:  p.CategoryID,
+				Pictures:    pictures,
+				Barcode:     stock.Barcode,
+				Description: p.Description,
+				Outlets:     []ymlOutlet{{ID: stock.ShopPointID, InStock: stock.Quantity}},
+				Params:      append(variantParams(stock.Options), params...),
+			}
+			if err := enc.Encode(offer); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := encodeTokens(enc, offers.End(), shop.End(), root.End()); err != nil {
+		return err
+	}
+
+	return enc.Flush()
+}
+
+func encodeTokens(enc *xml.Encoder, tokens ...xml.Token) error {
+	for _, token := range tokens {
+		if err := enc.EncodeToken(token); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ymlParams переводит атрибуты товара в элементы param в порядке имен.
+func ymlParams(attributes map[string]any) []ymlParam {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	params := make([]ymlParam, 0, len(names))
+	for _, name := range names {
+		var value string
+		switch v := attributes[name].(type) {
+		case nil:
+			continue
+		case string:
+			value = v
+		case float64:
+			value = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			if v {
+				value = "да"
+			} else {
+				value = "нет"
+			}
+		default:
+			value = fmt.Sprint(v)
+		}
+		params = append(params, ymlParam{Name: name, Value: value})
+	}
+
+	return params
+}
+
+// variantParams переводит опции варианта в элементы param в порядке имен.
+func variantParams(options map[string]string) []ymlParam {
+	attributes := make(map[string]any, len(options))
+	for name, value := range options {
+		attributes[name] = value
+	}
+
+	return ymlParams(attributes)
+}
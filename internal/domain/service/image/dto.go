@@ -0,0 +1,9 @@
+package image
+
+type Image struct {
+	ProductID   uint   `json:"product_id"`
+	Key         string `json:"key"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	ETag        string `json:"etag"`
+}
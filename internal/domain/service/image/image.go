@@ -0,0 +1,642 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/pkg/objectstorage"
+)
+
+const (
+	maxProductImages = 10
+	// maxVariantWorkers ограничивает число одновременно обрабатываемых изображений
+	maxVariantWorkers = 2
+	// sniffLen столько байт читает http.DetectContentType
+	sniffLen = 512
+)
+
+var errUnsupportedType = &apperror.ImageError{
+	Code:    apperror.UnsupportedMedia,
+	Message: "only JPEG, PNG and WebP images are supported",
+}
+
+// allowedTypes поддерживаемые форматы изображений и расширения для их ключей
+var allowedTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+type Repository interface {
+	InsertImage(ctx context.Context, image Image) (entity.ProductImage, error)
+	GetImage(ctx context.Context, productID, imageID uint) (entity.ProductImage, error)
+	GetImageByKey(ctx context.Context, productID uint, key string) (entity.ProductImage, error)
+	ReplaceImage(ctx context.Context, imageID uint, image Image) (entity.ProductImage, error)
+	UpdateVariantsETag(ctx context.Context, imageID uint, etag string) error
+	SelectStaleImages(ctx context.Context, limit int) ([]entity.ProductImage, error)
+	SelectProductsImages(ctx context.Context, productIDs []uint) (map[uint][]entity.ProductImage, error)
+	UpdateImagePositions(ctx context.Context, productID uint, imageIDs []uint) error
+	DeleteImage(ctx context.Context, productID, imageID uint) error
+}
+
+type ObjectStorage interface {
+	PresignPutObject(
+		ctx context.Context,
+		objectKey string,
+		contentType string,
+		contentLength int64,
+		ttl time.Duration,
+	) (objectstorage.PresignedRequest, error)
+	HeadObject(ctx context.Context, objectKey string) (objectstorage.ObjectInfo, error)
+	GetObject(ctx context.Context, objectKey string) (io.ReadCloser, objectstorage.ObjectInfo, error)
+	PutObject(ctx context.Context, objectKey string, body io.Reader, size int64, contentType string) error
+	DeleteObject(ctx context.Context, objectKey string) error
+	ObjectURL(objectKey string) string
+}
+
+type ProductRepository interface {
+	GetProductByID(ctx context.Context, id string) (entity.Product, error)
+}
+
+type RoleChecker interface {
+	// GetProductRoles возвращает роли пользователя в магазинах, в ассортименте которых есть товар
+	GetProductRoles(ctx context.Context, productID, userID uint) ([]string, error)
+}
+
+type imageService struct {
+	imageRepository   Repository
+	productRepository ProductRepository
+	roleChecker       RoleChecker
+	storage           ObjectStorage
+	maxSize           int64
+	uploadTTL         time.Duration
+	variantSlots      chan struct{}
+}
+
+func NewImageService(
+	imageRepo Repository,
+	productRepo ProductRepository,
+	roleChecker RoleChecker,
+	storage ObjectStorage,
+	maxSize int64,
+	uploadTTL time.Duration,
+) *imageService {
+	return &imageService{
+		imageRepository:   imageRepo,
+		productRepository: productRepo,
+		roleChecker:       roleChecker,
+		storage:           storage,
+		maxSize:           maxSize,
+		uploadTTL:         uploadTTL,
+		variantSlots:      make(chan struct{}, maxVariantWorkers),
+	}
+}
+
+// CreateUpload выдает подписанную ссылку для загрузки изображения товара
+// напрямую в хранилище. Ключ попадает в image_keys только после ConfirmUpload.
+func (is *imageService) CreateUpload(
+	ctx context.Context,
+	userID, productID uint,
+	contentType string,
+	size int64,
+) (entity.ImageUpload, error) {
+	if err := is.checkOwner(ctx, userID, productID); err != nil {
+		return entity.ImageUpload{}, err
+	}
+
+	if err := is.checkSize(size); err != nil {
+		return entity.ImageUpload{}, err
+	}
+
+	ext, ok := allowedTypes[contentType]
+	if !ok {
+		return entity.ImageUpload{}, errUnsupportedType
+	}
+
+	if err := is.checkImageLimit(ctx, productID); err != nil {
+		return entity.ImageUpload{}, err
+	}
+
+	key := fmt.Sprintf("%s%s%s", productKeyPrefix(productID), uuid.NewString(), ext)
+
+	return is.presignUpload(ctx, key, contentType, size)
+}
+
+func (is *imageService) presignUpload(
+	ctx context.Context,
+	key, contentType string,
+	size int64,
+) (entity.ImageUpload, error) {
+	presigned, err := is.storage.PresignPutObject(ctx, key, contentType, size, is.uploadTTL)
+	if err != nil {
+		return entity.ImageUpload{}, &apperror.ImageError{
+			Code:    apperror.InternalError,
+			Message: "failed to presign image upload",
+			Err:     err,
+		}
+	}
+
+	headers := make(map[string]string, len(presigned.Header))
+	for name := range presigned.Header {
+		headers[name] = presigned.Header.Get(name)
+	}
+
+	return entity.ImageUpload{
+		Key:       key,
+		URL:       presigned.URL,
+		Method:    presigned.Method,
+		Headers:   headers,
+		ExpiresAt: presigned.ExpiresAt,
+	}, nil
+}
+
+// CreateReplaceUpload выдает подписанную ссылку для замены оригинала существующего
+// изображения. Файл загружается во временный ключ и переносится на место оригинала
+// только в ConfirmUpload, поэтому формат нового файла должен совпадать со старым.
+func (is *imageService) CreateReplaceUpload(
+	ctx context.Context,
+	userID, productID, imageID uint,
+	contentType string,
+	size int64,
+) (entity.ImageUpload, error) {
+	if err := is.checkOwner(ctx, userID, productID); err != nil {
+		return entity.ImageUpload{}, err
+	}
+
+	if err := is.checkSize(size); err != nil {
+		return entity.ImageUpload{}, err
+	}
+
+	img, err := is.imageRepository.GetImage(ctx, productID, imageID)
+	if err != nil {
+		return entity.ImageUpload{}, err
+	}
+
+	if ext, ok := allowedTypes[contentType]; !ok || ext != path.Ext(img.Key) {
+		return entity.ImageUpload{}, &apperror.ImageError{
+			Code:    apperror.UnsupportedMedia,
+			Message: "replacement must have the same format as the original image",
+		}
+	}
+
+	stagingKey := fmt.Sprintf("%s%s/%s", stagingKeyPrefix(productID), uuid.NewString(), path.Base(img.Key))
+
+	return is.presignUpload(ctx, stagingKey, contentType, size)
+}
+
+// ConfirmUpload проверяет загруженный клиентом объект через HeadObject
+// и сигнатуру первых байт файла, после чего сохраняет ключ в image_keys
+// и запускает построение уменьшенных копий. Временный ключ замены
+// переносится на место оригинала. Объект, не прошедший проверку, удаляется из хранилища.
+func (is *imageService) ConfirmUpload(
+	ctx context.Context,
+	userID, productID uint,
+	key string,
+) (entity.ProductImage, error) {
+	if err := is.checkOwner(ctx, userID, productID); err != nil {
+		return entity.ProductImage{}, err
+	}
+
+	if !strings.HasPrefix(key, productKeyPrefix(productID)) || strings.Contains(key, "..") {
+		return entity.ProductImage{}, &apperror.ImageError{
+			Code:    apperror.BadRequest,
+			Message: "image key does not belong to this product",
+		}
+	}
+
+	if strings.HasPrefix(key, stagingKeyPrefix(productID)) {
+		originalKey, ok := stagedOriginal(productID, key)
+		if !ok {
+			return entity.ProductImage{}, &apperror.ImageError{
+				Code:    apperror.BadRequest,
+				Message: "invalid replacement image key",
+			}
+		}
+		return is.confirmReplace(ctx, productID, key, originalKey)
+	}
+
+	// уменьшенные копии лежат под тем же префиксом, но подтверждать можно только оригиналы
+	if !isOriginalName(strings.TrimPrefix(key, productKeyPrefix(productID))) {
+		return entity.ProductImage{}, &apperror.ImageError{
+			Code:    apperror.BadRequest,
+			Message: "image key was not issued for an upload",
+		}
+	}
+
+	// повторное подтверждение уже сохраненного ключа ничего не меняет
+	existing, err := is.imageRepository.GetImageByKey(ctx, productID, key)
+	if err == nil {
+		return is.withURL(existing), nil
+	}
+	if !errors.Is(err, apperror.ErrImageNotFound) {
+		return entity.ProductImage{}, err
+	}
+
+	info, err := is.headUpload(ctx, key)
+	if err != nil {
+		return entity.ProductImage{}, err
+	}
+
+	if err := is.verifyObject(ctx, info); err != nil {
+		is.removeObject(ctx, key)
+		return entity.ProductImage{}, err
+	}
+
+	if err := is.checkImageLimit(ctx, productID); err != nil {
+		is.removeObject(ctx, key)
+		return entity.ProductImage{}, err
+	}
+
+	img, err := is.imageRepository.InsertImage(ctx, Image{
+		ProductID:   productID,
+		Key:         key,
+		ContentType: info.ContentType,
+		Size:        info.Size,
+		ETag:        info.ETag,
+	})
+	if err != nil {
+		return entity.ProductImage{}, err
+	}
+
+	is.scheduleVariants(img)
+
+	return is.withURL(img), nil
+}
+
+// confirmReplace проверяет загруженную замену и копирует ее поверх оригинала.
+// Временный объект удаляется в любом случае, оригинал до успешной проверки не трогается.
+func (is *imageService) confirmReplace(
+	ctx context.Context,
+	productID uint,
+	stagingKey, originalKey string,
+) (entity.ProductImage, error) {
+	defer is.removeObject(ctx, stagingKey)
+
+	existing, err := is.imageRepository.GetImageByKey(ctx, productID, originalKey)
+	if err != nil {
+		return entity.ProductImage{}, err
+	}
+
+	info, err := is.headUpload(ctx, stagingKey)
+	if err != nil {
+		return entity.ProductImage{}, err
+	}
+
+	if err := is.verifyObject(ctx, info); err != nil {
+		return entity.ProductImage{}, err
+	}
+	if allowedTypes[info.ContentType] != path.Ext(originalKey) {
+		return entity.ProductImage{}, &apperror.ImageError{
+			Code:    apperror.UnsupportedMedia,
+			Message: "replacement must have the same format as the original image",
+		}
+	}
+
+	if err := is.copyObject(ctx, stagingKey, originalKey, info); err != nil {
+		return entity.ProductImage{}, &apperror.ImageError{
+			Code:    apperror.InternalError,
+			Message: "failed to replace image",
+			Err:     err,
+		}
+	}
+
+	replaced, err := is.storage.HeadObject(ctx, originalKey)
+	if err != nil {
+		return entity.ProductImage{}, &apperror.ImageError{
+			Code:    apperror.InternalError,
+			Message: "failed to check replaced image",
+			Err:     err,
+		}
+	}
+
+	img, err := is.imageRepository.ReplaceImage(ctx, existing.ID, Image{
+		ProductID:   productID,
+		Key:         originalKey,
+		ContentType: replaced.ContentType,
+		Size:        replaced.Size,
+		ETag:        replaced.ETag,
+	})
+	if err != nil {
+		return entity.ProductImage{}, err
+	}
+
+	if img.VariantsETag != img.ETag {
+		is.scheduleVariants(img)
+	}
+
+	return is.withURL(img), nil
+}
+
+// headUpload возвращает сведения о загруженном клиентом объекте.
+func (is *imageService) headUpload(ctx context.Context, key string) (objectstorage.ObjectInfo, error) {
+	info, err := is.storage.HeadObject(ctx, key)
+	if err != nil {
+		if errors.Is(err, objectstorage.ErrObjectNotFound) {
+			return objectstorage.ObjectInfo{}, &apperror.ImageError{
+				Code:    apperror.BadRequest,
+				Message: "image has not been uploaded",
+			}
+		}
+		return objectstorage.ObjectInfo{}, &apperror.ImageError{
+			Code:    apperror.InternalError,
+			Message: "failed to check uploaded image",
+			Err:     err,
+		}
+	}
+
+	return info, nil
+}
+
+// copyObject копирует объект src в dst через приложение,
+// хранилище не обязано уметь копировать объекты само.
+func (is *imageService) copyObject(ctx context.Context, src, dst string, info objectstorage.ObjectInfo) error {
+	body, _, err := is.storage.GetObject(ctx, src)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return is.storage.PutObject(ctx, dst, body, info.Size, info.ContentType)
+}
+
+// verifyObject сверяет размер, заявленный тип и реальную сигнатуру загруженного файла.
+func (is *imageService) verifyObject(ctx context.Context, info objectstorage.ObjectInfo) error {
+	if err := is.checkSize(info.Size); err != nil {
+		return err
+	}
+
+	if _, ok := allowedTypes[info.ContentType]; !ok {
+		return errUnsupportedType
+	}
+
+	head, err := is.readObject(ctx, info.Key, sniffLen)
+	if err != nil {
+		return &apperror.ImageError{
+			Code:    apperror.InternalError,
+			Message: "failed to read uploaded image",
+			Err:     err,
+		}
+	}
+
+	if http.DetectContentType(head) != info.ContentType {
+		return errUnsupportedType
+	}
+
+	return nil
+}
+
+func (is *imageService) checkSize(size int64) error {
+	if size <= 0 || size > is.maxSize {
+		return &apperror.ImageError{
+			Code:    apperror.TooLarge,
+			Message: fmt.Sprintf("image size must be between 1 and %d bytes", is.maxSize),
+		}
+	}
+
+	return nil
+}
+
+func (is *imageService) checkImageLimit(ctx context.Context, productID uint) error {
+	images, err := is.imageRepository.SelectProductsImages(ctx, []uint{productID})
+	if err != nil {
+		return err
+	}
+
+	if len(images[productID]) >= maxProductImages {
+		return &apperror.ImageError{
+			Code:    apperror.BadRequest,
+			Message: fmt.Sprintf("product may have at most %d images", maxProductImages),
+		}
+	}
+
+	return nil
+}
+
+// readObject читает не больше limit байт объекта.
+func (is *imageService) readObject(ctx context.Context, key string, limit int64) ([]byte, error) {
+	body, _, err := is.storage.GetObject(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return io.ReadAll(io.LimitReader(body, limit))
+}
+
+func (is *imageService) removeObject(ctx context.Context, key string) {
+	if err := is.storage.DeleteObject(ctx, key); err != nil {
+		log.Printf("failed to remove image object %s: %v", key, err)
+	}
+}
+
+// GetProductImages возвращает изображения товара в порядке отображения.
+func (is *imageService) GetProductImages(
+	ctx context.Context,
+	productID uint,
+) ([]entity.ProductImage, error) {
+	if _, err := is.getProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	images, err := is.GetProductsImages(ctx, []uint{productID})
+	if err != nil {
+		return nil, err
+	}
+
+	if images[productID] == nil {
+		return []entity.ProductImage{}, nil
+	}
+
+	return images[productID], nil
+}
+
+// GetProductsImages возвращает изображения с URL для нескольких товаров сразу.
+func (is *imageService) GetProductsImages(
+	ctx context.Context,
+	productIDs []uint,
+) (map[uint][]entity.ProductImage, error) {
+	images, err := is.imageRepository.SelectProductsImages(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for productID, productImages := range images {
+		for i := range productImages {
+			productImages[i] = is.withURL(productImages[i])
+		}
+		images[productID] = productImages
+	}
+
+	return images, nil
+}
+
+// ReorderImages задает новый порядок изображений товара.
+// imageIDs должен содержать все изображения товара ровно по одному разу.
+func (is *imageService) ReorderImages(
+	ctx context.Context,
+	userID, productID uint,
+	imageIDs []uint,
+) ([]entity.ProductImage, error) {
+	if err := is.checkOwner(ctx, userID, productID); err != nil {
+		return nil, err
+	}
+
+	images, err := is.imageRepository.SelectProductsImages(ctx, []uint{productID})
+	if err != nil {
+		return nil, err
+	}
+
+	current := images[productID]
+	if len(current) != len(imageIDs) {
+		return nil, &apperror.ImageError{
+			Code:    apperror.BadRequest,
+			Message: "image order must list every product image exactly once",
+		}
+	}
+
+	known := make(map[uint]bool, len(current))
+	for _, img := range current {
+		known[img.ID] = false
+	}
+	for _, id := range imageIDs {
+		seen, ok := known[id]
+		if !ok || seen {
+			return nil, &apperror.ImageError{
+				Code:    apperror.BadRequest,
+				Message: "image order must list every product image exactly once",
+			}
+		}
+		known[id] = true
+	}
+
+	if err := is.imageRepository.UpdateImagePositions(ctx, productID, imageIDs); err != nil {
+		return nil, err
+	}
+
+	return is.GetProductImages(ctx, productID)
+}
+
+// DeleteImage удаляет изображение из хранилища и из image_keys.
+func (is *imageService) DeleteImage(
+	ctx context.Context,
+	userID, productID, imageID uint,
+) error {
+	if err := is.checkOwner(ctx, userID, productID); err != nil {
+		return err
+	}
+
+	img, err := is.imageRepository.GetImage(ctx, productID, imageID)
+	if err != nil {
+		return err
+	}
+
+	if err := is.imageRepository.DeleteImage(ctx, productID, imageID); err != nil {
+		return err
+	}
+
+	is.removeObject(ctx, img.Key)
+	is.deleteVariants(ctx, img.Key)
+
+	return nil
+}
+
+// RemoveImageObjects удаляет из хранилища оригиналы и уменьшенные копии изображений,
+// записи о которых уже удалены из image_keys.
+func (is *imageService) RemoveImageObjects(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		is.removeObject(ctx, key)
+		is.deleteVariants(ctx, key)
+	}
+}
+
+// checkOwner проверяет, что товар существует и есть в ассортименте магазина,
+// где роль пользователя позволяет менять каталог.
+func (is *imageService) checkOwner(ctx context.Context, userID, productID uint) error {
+	if _, err := is.getProduct(ctx, productID); err != nil {
+		return err
+	}
+
+	roles, err := is.roleChecker.GetProductRoles(ctx, productID, userID)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if entity.RoleAllows(role, entity.PermissionManageCatalog) {
+			return nil
+		}
+	}
+
+	return apperror.ErrImageForbidden
+}
+
+func (is *imageService) getProduct(ctx context.Context, productID uint) (entity.Product, error) {
+	product, err := is.productRepository.GetProductByID(ctx, strconv.FormatUint(uint64(productID), 10))
+	if err != nil {
+		var productErr *apperror.ProductError
+		if errors.As(err, &productErr) && productErr.Code == apperror.NotFound {
+			return entity.Product{}, apperror.ErrImageProductNotFound
+		}
+		return entity.Product{}, err
+	}
+
+	return product, nil
+}
+
+// isOriginalName проверяет, что имя объекта выдано CreateUpload: <uuid><расширение формата>.
+// Имена уменьшенных копий и любые другие объекты под префиксом товара не подходят.
+func isOriginalName(name string) bool {
+	ext := path.Ext(name)
+	known := false
+	for _, allowed := range allowedTypes {
+		if ext == allowed {
+			known = true
+			break
+		}
+	}
+
+	return known && isUUID(strings.TrimSuffix(name, ext))
+}
+
+// isUUID проверяет, что строка — uuid в том виде, в каком его выдает uuid.NewString.
+func isUUID(s string) bool {
+	id, err := uuid.Parse(s)
+	return err == nil && id.String() == s
+}
+
+// productKeyPrefix общий префикс ключей изображений товара в хранилище.
+func productKeyPrefix(productID uint) string {
+	return fmt.Sprintf("products/%d/", productID)
+}
+
+// stagingKeyPrefix префикс временных ключей, куда загружается замена оригинала до подтверждения.
+func stagingKeyPrefix(productID uint) string {
+	return productKeyPrefix(productID) + "staging/"
+}
+
+// stagedOriginal возвращает ключ оригинала по временному ключу вида staging/<uuid>/<имя оригинала>.
+func stagedOriginal(productID uint, stagingKey string) (string, bool) {
+	rest := strings.TrimPrefix(stagingKey, stagingKeyPrefix(productID))
+	id, name, ok := strings.Cut(rest, "/")
+	if !ok || !isUUID(id) || !isOriginalName(name) {
+		return "", false
+	}
+
+	return productKeyPrefix(productID) + name, true
+}
+
+func (is *imageService) withURL(img entity.ProductImage) entity.ProductImage {
+	img.URL = is.storage.ObjectURL(img.Key)
+	img.Srcset = is.variantURLs(img)
+	return img
+}
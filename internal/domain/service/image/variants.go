@@ -0,0 +1,124 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/pkg/imaging"
+)
+
+const (
+	variantQuality     = 82
+	variantContentType = "image/jpeg"
+	// staleBatchSize столько изображений перегенерируется за один проход
+	staleBatchSize = 50
+)
+
+type variant struct {
+	Name    string
+	MaxSize int
+}
+
+// variants уменьшенные копии, которые строятся для каждого изображения товара
+var variants = []variant{
+	{Name: "thumb", MaxSize: 200},
+	{Name: "card", MaxSize: 600},
+	{Name: "full", MaxSize: 1600},
+}
+
+// variantKey строит ключ копии рядом с оригиналом:
+// products/1/abc.png -> products/1/abc_thumb.jpg
+func variantKey(key, name string) string {
+	return strings.TrimSuffix(key, path.Ext(key)) + "_" + name + ".jpg"
+}
+
+// scheduleVariants запускает построение копий в фоне, не задерживая ответ клиенту.
+func (is *imageService) scheduleVariants(img entity.ProductImage) {
+	go func() {
+		is.variantSlots <- struct{}{}
+		defer func() { <-is.variantSlots }()
+
+		if err := is.generateVariants(context.Background(), img); err != nil {
+			log.Printf("failed to generate variants for image %s: %v", img.Key, err)
+		}
+	}()
+}
+
+// generateVariants скачивает оригинал, исправляет ориентацию по EXIF
+// и сохраняет копии в JPEG без метаданных.
+func (is *imageService) generateVariants(ctx context.Context, img entity.ProductImage) error {
+	data, err := is.readObject(ctx, img.Key, is.maxSize)
+	if err != nil {
+		return err
+	}
+
+	original, err := imaging.Decode(data)
+	if err != nil {
+		return err
+	}
+	orientation := imaging.Orientation(data)
+
+	for _, v := range variants {
+		resized := imaging.ApplyOrientation(imaging.Fit(original, v.MaxSize), orientation)
+
+		var buf bytes.Buffer
+		if err := imaging.EncodeJPEG(&buf, resized, variantQuality); err != nil {
+			return err
+		}
+
+		size := int64(buf.Len())
+		if err := is.storage.PutObject(ctx, variantKey(img.Key, v.Name), &buf, size, variantContentType); err != nil {
+			return err
+		}
+	}
+
+	return is.imageRepository.UpdateVariantsETag(ctx, img.ID, img.ETag)
+}
+
+// RegenerateStaleVariants перестраивает копии изображений, оригинал которых
+// изменился после последней генерации, например если процесс упал во время обработки.
+func (is *imageService) RegenerateStaleVariants(ctx context.Context) error {
+	for {
+		images, err := is.imageRepository.SelectStaleImages(ctx, staleBatchSize)
+		if err != nil {
+			return err
+		}
+
+		regenerated := 0
+		for _, img := range images {
+			if err := is.generateVariants(ctx, img); err != nil {
+				log.Printf("failed to regenerate variants for image %s: %v", img.Key, err)
+				continue
+			}
+			regenerated++
+		}
+
+		// останавливаемся, если выборка закончилась или ни одно изображение не удалось обработать
+		if len(images) < staleBatchSize || regenerated == 0 {
+			return nil
+		}
+	}
+}
+
+func (is *imageService) deleteVariants(ctx context.Context, key string) {
+	for _, v := range variants {
+		is.removeObject(ctx, variantKey(key, v.Name))
+	}
+}
+
+func (is *imageService) variantURLs(img entity.ProductImage) map[string]string {
+	if img.ETag == "" || img.VariantsETag != img.ETag {
+		return nil
+	}
+
+	srcset := make(map[string]string, len(variants))
+	for _, v := range variants {
+		srcset[v.Name] = is.storage.ObjectURL(variantKey(img.Key, v.Name))
+	}
+
+	return srcset
+}
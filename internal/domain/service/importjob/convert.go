@@ -0,0 +1,273 @@
+package importjob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/category"
+)
+
+// rowConverter проверяет строки файла и переводит их в Row.
+// Категории и схемы атрибутов кэшируются на время одного задания.
+type rowConverter struct {
+	categories     Repository
+	schemaProvider SchemaProvider
+	mapping        map[string]string
+	shopPoints     map[uint]struct{}
+	categoryIDs    map[string]*uint
+	schemas        map[uint][]entity.CategoryAttribute
+}
+
+func newRowConverter(
+	categories Repository,
+	schemaProvider SchemaProvider,
+	mapping map[string]string,
+	shopPointIDs []uint,
+) *rowConverter {
+	points := make(map[uint]struct{}, len(shopPointIDs))
+	for _, id := range shopPointIDs {
+		points[id] = struct{}{}
+	}
+
+	return &rowConverter{
+		categories:     categories,
+		schemaProvider: schemaProvider,
+		mapping:        mapping,
+		shopPoints:     points,
+		categoryIDs:    make(map[string]*uint),
+		schemas:        make(map[uint][]entity.CategoryAttribute),
+	}
+}
+
+// convert возвращает ошибки строки отдельно от ошибок выполнения:
+// первые попадают в отчет, вторые прерывают задание.
+func (rc *rowConverter) convert(ctx context.Context, raw rawRow) (Row, []entity.ImportRowError, error) {
+	row := Row{Number: raw.number}
+	var rowErrors []entity.ImportRowError
+	addError := func(field, message string) {
+		rowErrors = append(rowErrors, entity.ImportRowError{Row: raw.number, Field: field, Message: message})
+	}
+
+	if raw.values == nil {
+		addError("", "row is not a valid JSON object")
+		return row, rowErrors, nil
+	}
+
+	mapped := applyMapping(raw.values, rc.mapping)
+
+	row.Name = stringValue(mapped.fields[FieldName])
+	if row.Name == "" {
+		addError(FieldName, "name is required")
+	} else if len([]rune(row.Name)) > 255 {
+		addError(FieldName, "name must be at most 255 characters")
+	}
+	row.Description = stringValue(mapped.fields[FieldDescription])
+
+	if path := stringValue(mapped.fields[FieldCategoryPath]); path != "" {
+		categoryID, err := rc.findCategory(ctx, path)
+		if err != nil {
+			return row, nil, err
+		}
+		if categoryID == nil {
+			addError(FieldCategoryPath, "category not found")
+		}
+		row.CategoryID = categoryID
+	}
+
+	attributes, err := objectValue(mapped.fields[FieldAttributes])
+	if err != nil {
+		addError(FieldAttributes, err.Error())
+	}
+	for name, value := range mapped.attributes {
+		if !isEmpty(value) {
+			attributes[name] = value
+		}
+	}
+	if len(attributes) > 0 {
+		row.Attributes = attributes
+	}
+
+	if row.CategoryID != nil {
+		schema, err := rc.schema(ctx, *row.CategoryID)
+		if err != nil {
+			return row, nil, err
+		}
+		coerceAttributes(schema, row.Attributes)
+		for _, fieldErr := range category.ValidateAttributes(schema, row.Attributes) {
+			addError(fieldErr.Field, fieldErr.Message)
+		}
+	}
+
+	convertVariant(&row, mapped, addError)
+	rc.convertStock(&row, mapped, addError)
+
+	return row, rowErrors, nil
+}
+
+// convertVariant разбирает артикул, штрихкод и опции варианта товара.
+func convertVariant(row *Row, mapped mappedRow, addError func(field, message string)) {
+	row.SKU = stringValue(mapped.fields[FieldSKU])
+	if len([]rune(row.SKU)) > 64 {
+		addError(FieldSKU, "must be at most 64 characters")
+	}
+
+	row.Barcode = stringValue(mapped.fields[FieldBarcode])
+	if row.Barcode != "" {
+		if _, err := strconv.ParseUint(row.Barcode, 10, 64); err != nil || len(row.Barcode) < 8 || len(row.Barcode) > 14 {
+			addError(FieldBarcode, "must contain from 8 to 14 digits")
+		}
+	}
+
+	options, err := objectValue(mapped.fields[FieldOptions])
+	if err != nil {
+		addError(FieldOptions, err.Error())
+		return
+	}
+	for name, value := range options {
+		s := stringValue(value)
+		if strings.TrimSpace(name) == "" || s == "" {
+			addError(FieldOptions, "option names and values must not be empty")
+			return
+		}
+		if row.Options == nil {
+			row.Options = make(map[string]string, len(options))
+		}
+		row.Options[name] = s
+	}
+}
+
+// convertStock разбирает цену и остаток на точке продаж магазина.
+func (rc *rowConverter) convertStock(row *Row, mapped mappedRow, addError func(field, message string)) {
+	pointValue := mapped.fields[FieldShopPointID]
+	priceValue := mapped.fields[FieldPrice]
+	quantityValue := mapped.fields[FieldQuantity]
+
+	if isEmpty(pointValue) {
+		if !isEmpty(priceValue) || !isEmpty(quantityValue) {
+			addError(FieldShopPointID, "shop point is required to set price or quantity")
+		}
+		return
+	}
+
+	pointID, err := intValue(pointValue)
+	if err != nil || pointID <= 0 {
+		addError(FieldShopPointID, "must be a positive integer")
+		return
+	}
+	if _, ok := rc.shopPoints[uint(pointID)]; !ok {
+		addError(FieldShopPointID, "shop point does not belong to this store")
+		return
+	}
+	id := uint(pointID)
+	row.ShopPointID = &id
+
+	if !isEmpty(priceValue) {
+		price, err := floatValue(priceValue)
+		switch {
+		case err != nil:
+			addError(FieldPrice, "must be a number")
+		case price < 0:
+			addError(FieldPrice, "must not be negative")
+		default:
+			row.Price = &price
+		}
+	}
+
+	if !isEmpty(quantityValue) {
+		quantity, err := intValue(quantityValue)
+		switch {
+		case err != nil:
+			addError(FieldQuantity, "must be an integer")
+		case quantity < 0:
+			addError(FieldQuantity, "must not be negative")
+		default:
+			row.Quantity = &quantity
+		}
+	}
+}
+
+func (rc *rowConverter) findCategory(ctx context.Context, path string) (*uint, error) {
+	names := splitCategoryPath(path)
+	cacheKey := strings.ToLower(strings.Join(names, "/"))
+	if categoryID, ok := rc.categoryIDs[cacheKey]; ok {
+		return categoryID, nil
+	}
+
+	var result *uint
+	if len(names) > 0 {
+		categoryID, found, err := rc.categories.FindCategoryByPath(ctx, names)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			result = &categoryID
+		}
+	}
+
+	rc.categoryIDs[cacheKey] = result
+	return result, nil
+}
+
+func (rc *rowConverter) schema(ctx context.Context, categoryID uint) ([]entity.CategoryAttribute, error) {
+	if schema, ok := rc.schemas[categoryID]; ok {
+		return schema, nil
+	}
+
+	schema, err := rc.schemaProvider.GetCategorySchema(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	rc.schemas[categoryID] = schema
+	return schema, nil
+}
+
+// objectValue принимает атрибуты или опции объектом JSON lines или строкой с JSON из CSV.
+func objectValue(v any) (map[string]any, error) {
+	attributes := make(map[string]any)
+
+	switch value := v.(type) {
+	case nil:
+	case map[string]any:
+		for name, attr := range value {
+			attributes[name] = attr
+		}
+	case string:
+		if strings.TrimSpace(value) == "" {
+			break
+		}
+		if err := json.Unmarshal([]byte(value), &attributes); err != nil {
+			return attributes, errors.New("must be a JSON object")
+		}
+	default:
+		return attributes, errors.New("must be a JSON object")
+	}
+
+	return attributes, nil
+}
+
+// coerceAttributes приводит строковые значения из CSV к типам схемы,
+// чтобы "1.5" и "true" проходили проверку числовых и логических атрибутов.
+func coerceAttributes(schema []entity.CategoryAttribute, attributes map[string]any) {
+	for _, attr := range schema {
+		s, ok := attributes[attr.Name].(string)
+		if !ok {
+			continue
+		}
+
+		switch attr.Type {
+		case entity.AttributeTypeNumber:
+			if f, err := floatValue(s); err == nil {
+				attributes[attr.Name] = f
+			}
+		case entity.AttributeTypeBool:
+			if b, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+				attributes[attr.Name] = b
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package importjob
+
+type Job struct {
+	ShopID  uint              `json:"shop_id"`
+	UserID  uint              `json:"user_id"`
+	Format  string            `json:"format"`
+	FileKey string            `json:"file_key"`
+	Mapping map[string]string `json:"mapping"`
+}
+
+// Row проверенная строка файла импорта, готовая к записи.
+// Цена и остаток относятся к варианту, заданному артикулом или опциями.
+type Row struct {
+	Number      int               `json:"number"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	CategoryID  *uint             `json:"category_id"`
+	Attributes  map[string]any    `json:"attributes"`
+	SKU         string            `json:"sku"`
+	Barcode     string            `json:"barcode"`
+	Options     map[string]string `json:"options"`
+	ShopPointID *uint             `json:"shop_point_id"`
+	Price       *float64          `json:"price"`
+	Quantity    *int              `json:"quantity"`
+}
+
+type Progress struct {
+	TotalRows    int `json:"total_rows"`
+	ImportedRows int `json:"imported_rows"`
+	FailedRows   int `json:"failed_rows"`
+}
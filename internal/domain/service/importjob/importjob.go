@@ -0,0 +1,342 @@
+package importjob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/pkg/objectstorage"
+)
+
+const (
+	// batchSize столько проверенных строк записывается в одной транзакции
+	batchSize = 100
+	// pollInterval как часто воркер проверяет очередь без явного сигнала
+	pollInterval = 30 * time.Second
+	// staleAfter задание в обработке, прогресс которого не обновлялся дольше этого срока,
+	// считается брошенным
+	staleAfter = 30 * time.Minute
+	// maxRowErrors ограничивает отчет об ошибках одного задания
+	maxRowErrors = 10000
+)
+
+type Repository interface {
+	InsertJob(ctx context.Context, job Job) (entity.ImportJob, error)
+	GetJob(ctx context.Context, jobID uint) (entity.ImportJob, error)
+	// ClaimPendingJob забирает следующее ожидающее задание в обработку,
+	// ok == false означает, что очередь пуста
+	ClaimPendingJob(ctx context.Context) (job entity.ImportJob, ok bool, err error)
+	ResetStaleJobs(ctx context.Context, updatedBefore time.Time) error
+	UpdateJobProgress(ctx context.Context, jobID uint, progress Progress) error
+	FinishJob(ctx context.Context, jobID uint, status string, progress Progress, message string) error
+	InsertRowErrors(ctx context.Context, jobID uint, rowErrors []entity.ImportRowError) error
+	SelectRowErrors(ctx context.Context, jobID uint) ([]entity.ImportRowError, error)
+	// UpsertProducts возвращает ошибки строк, которые не удалось сохранить
+	UpsertProducts(ctx context.Context, shopID uint, rows []Row) ([]entity.ImportRowError, error)
+	// FindCategoryByPath ищет категорию по цепочке имен от корня, ok == false если ее нет
+	FindCategoryByPath(ctx context.Context, names []string) (categoryID uint, ok bool, err error)
+	SelectShopPointIDs(ctx context.Context, shopID uint) ([]uint, error)
+}
+
+type ObjectStorage interface {
+	PutObject(ctx context.Context, objectKey string, body io.Reader, size int64, contentType string) error
+	GetObject(ctx context.Context, objectKey string) (io.ReadCloser, objectstorage.ObjectInfo, error)
+	DeleteObject(ctx context.Context, objectKey string) error
+}
+
+type SchemaProvider interface {
+	GetCategorySchema(ctx context.Context, categoryID uint) ([]entity.CategoryAttribute, error)
+}
+
+type RoleChecker interface {
+	// GetMemberRole возвращает роль пользователя в магазине, пустую строку если он не сотрудник
+	GetMemberRole(ctx context.Context, shopID, userID uint) (string, error)
+}
+
+type importService struct {
+	importRepository Repository
+	storage          ObjectStorage
+	schemaProvider   SchemaProvider
+	roleChecker      RoleChecker
+	wakeup           chan struct{}
+}
+
+func NewImportService(
+	importRepo Repository,
+	storage ObjectStorage,
+	schemaProvider SchemaProvider,
+	roleChecker RoleChecker,
+) *importService {
+	return &importService{
+		importRepository: importRepo,
+		storage:          storage,
+		schemaProvider:   schemaProvider,
+		roleChecker:      roleChecker,
+		wakeup:           make(chan struct{}, 1),
+	}
+}
+
+// CreateJob сохраняет файл в хранилище и ставит задание импорта в очередь.
+// Строки проверяются и записываются воркером асинхронно.
+func (s *importService) CreateJob(
+	ctx context.Context,
+	job Job,
+	file io.Reader,
+	size int64,
+) (entity.ImportJob, error) {
+	if err := s.checkOwner(ctx, job.ShopID, job.UserID); err != nil {
+		return entity.ImportJob{}, err
+	}
+
+	job.Format = strings.ToLower(strings.TrimSpace(job.Format))
+	contentType, ok := formatContentTypes[job.Format]
+	if !ok {
+		return entity.ImportJob{}, &apperror.ImportError{
+			Code:    apperror.BadRequest,
+			Message: "format must be csv or jsonl",
+		}
+	}
+
+	if err := validateMapping(job.Mapping); err != nil {
+		return entity.ImportJob{}, &apperror.ImportError{
+			Code:    apperror.BadRequest,
+			Message: "invalid column mapping",
+			Err:     err,
+		}
+	}
+	if job.Mapping == nil {
+		job.Mapping = map[string]string{}
+	}
+
+	job.FileKey = fmt.Sprintf("imports/%d/%s.%s", job.ShopID, uuid.NewString(), job.Format)
+	if err := s.storage.PutObject(ctx, job.FileKey, file, size, contentType); err != nil {
+		return entity.ImportJob{}, &apperror.ImportError{
+			Code:    apperror.InternalError,
+			Message: "failed to store import file",
+			Err:     err,
+		}
+	}
+
+	created, err := s.importRepository.InsertJob(ctx, job)
+	if err != nil {
+		s.removeFile(ctx, job.FileKey)
+		return entity.ImportJob{}, err
+	}
+
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+
+	return created, nil
+}
+
+// GetJob возвращает состояние задания его автору или владельцу магазина.
+func (s *importService) GetJob(ctx context.Context, userID, jobID uint) (entity.ImportJob, error) {
+	job, err := s.importRepository.GetJob(ctx, jobID)
+	if err != nil {
+		return entity.ImportJob{}, err
+	}
+
+	if job.UserID != userID {
+		if err := s.checkOwner(ctx, job.ShopID, userID); err != nil {
+			return entity.ImportJob{}, err
+		}
+	}
+
+	return job, nil
+}
+
+// GetJobErrors возвращает ошибки строк задания в порядке их номеров.
+func (s *importService) GetJobErrors(
+	ctx context.Context,
+	userID, jobID uint,
+) ([]entity.ImportRowError, error) {
+	if _, err := s.GetJob(ctx, userID, jobID); err != nil {
+		return nil, err
+	}
+
+	return s.importRepository.SelectRowErrors(ctx, jobID)
+}
+
+// Run обрабатывает очередь заданий до отмены контекста.
+func (s *importService) Run(ctx context.Context) {
+	if err := s.importRepository.ResetStaleJobs(ctx, time.Now().Add(-staleAfter)); err != nil {
+		log.Printf("failed to reset stale import jobs: %v", err)
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.processPending(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wakeup:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *importService) processPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, ok, err := s.importRepository.ClaimPendingJob(ctx)
+		if err != nil {
+			log.Printf("failed to claim import job: %v", err)
+			return
+		}
+		if !ok {
+			return
+		}
+
+		s.process(ctx, job)
+	}
+}
+
+func (s *importService) process(ctx context.Context, job entity.ImportJob) {
+	progress, err := s.importFile(ctx, job)
+
+	status, message := entity.ImportStatusCompleted, ""
+	if err != nil {
+		log.Printf("import job %d failed: %v", job.ID, err)
+		status, message = entity.ImportStatusFailed, err.Error()
+	}
+
+	if err := s.importRepository.FinishJob(ctx, job.ID, status, progress, message); err != nil {
+		log.Printf("failed to finish import job %d: %v", job.ID, err)
+		return
+	}
+
+	s.removeFile(ctx, job.FileKey)
+}
+
+func (s *importService) importFile(ctx context.Context, job entity.ImportJob) (Progress, error) {
+	var progress Progress
+
+	file, _, err := s.storage.GetObject(ctx, job.FileKey)
+	if err != nil {
+		return progress, fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer file.Close()
+
+	reader, err := newRowReader(job.Format, file)
+	if err != nil {
+		return progress, err
+	}
+
+	pointIDs, err := s.importRepository.SelectShopPointIDs(ctx, job.ShopID)
+	if err != nil {
+		return progress, err
+	}
+
+	conv := newRowConverter(s.importRepository, s.schemaProvider, job.Mapping, pointIDs)
+	batch := make([]Row, 0, batchSize)
+	var rowErrors []entity.ImportRowError
+	reported := 0
+
+	flush := func() error {
+		if len(batch) > 0 {
+			failed, err := s.importRepository.UpsertProducts(ctx, job.ShopID, batch)
+			if err != nil {
+				// не удалась сама транзакция пачки, поэтому отклоняются все ее строки
+				log.Printf("import job %d: failed to save batch: %v", job.ID, err)
+				for _, row := range batch {
+					rowErrors = append(rowErrors, entity.ImportRowError{
+						Row:     row.Number,
+						Message: "failed to save row",
+					})
+				}
+				progress.FailedRows += len(batch)
+			} else {
+				rowErrors = append(rowErrors, failed...)
+				progress.FailedRows += len(failed)
+				progress.ImportedRows += len(batch) - len(failed)
+			}
+			batch = batch[:0]
+		}
+
+		if len(rowErrors) > 0 && reported < maxRowErrors {
+			if len(rowErrors) > maxRowErrors-reported {
+				rowErrors = rowErrors[:maxRowErrors-reported]
+			}
+			if err := s.importRepository.InsertRowErrors(ctx, job.ID, rowErrors); err != nil {
+				return err
+			}
+			reported += len(rowErrors)
+		}
+		rowErrors = rowErrors[:0]
+
+		return s.importRepository.UpdateJobProgress(ctx, job.ID, progress)
+	}
+
+	for {
+		raw, err := reader.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if flushErr := flush(); flushErr != nil {
+				log.Printf("import job %d: failed to save progress: %v", job.ID, flushErr)
+			}
+			return progress, fmt.Errorf("failed to read row %d: %w", progress.TotalRows+1, err)
+		}
+		progress.TotalRows++
+
+		row, errs, err := conv.convert(ctx, raw)
+		if err != nil {
+			if flushErr := flush(); flushErr != nil {
+				log.Printf("import job %d: failed to save progress: %v", job.ID, flushErr)
+			}
+			return progress, err
+		}
+		if len(errs) > 0 {
+			progress.FailedRows++
+			rowErrors = append(rowErrors, errs...)
+		} else {
+			batch = append(batch, row)
+		}
+
+		// ошибки строк тоже сбрасываются пачками, чтобы файл из одних ошибок
+		// не копил их в памяти и прогресс обновлялся
+		if len(batch) == batchSize || len(rowErrors) >= batchSize {
+			if err := flush(); err != nil {
+				return progress, err
+			}
+		}
+	}
+
+	return progress, flush()
+}
+
+// checkOwner проверяет, что роль пользователя в магазине позволяет менять каталог
+func (s *importService) checkOwner(ctx context.Context, shopID, userID uint) error {
+	role, err := s.roleChecker.GetMemberRole(ctx, shopID, userID)
+	if err != nil {
+		return err
+	}
+	if !entity.RoleAllows(role, entity.PermissionManageCatalog) {
+		return apperror.ErrImportForbidden
+	}
+
+	return nil
+}
+
+func (s *importService) removeFile(ctx context.Context, key string) {
+	if err := s.storage.DeleteObject(ctx, key); err != nil {
+		log.Printf("failed to delete import file %s: %v", key, err)
+	}
+}
+
+var formatContentTypes = map[string]string{
+	entity.ImportFormatCSV:   "text/csv",
+	entity.ImportFormatJSONL: "application/x-ndjson",
+}
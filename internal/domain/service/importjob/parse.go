@@ -0,0 +1,258 @@
+package importjob
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Поля товара, на которые можно отобразить колонки файла.
+const (
+	FieldName         = "name"
+	FieldDescription  = "description"
+	FieldCategoryPath = "category_path"
+	FieldAttributes   = "attributes"
+	FieldShopPointID  = "shop_point_id"
+	FieldPrice        = "price"
+	FieldQuantity     = "quantity"
+	FieldSKU          = "sku"
+	FieldBarcode      = "barcode"
+	FieldOptions      = "options"
+
+	// attributePrefix колонка вида attr.<имя> становится отдельным атрибутом товара
+	attributePrefix = "attr."
+	// maxLineSize ограничивает длину одной строки JSON lines
+	maxLineSize = 1 << 20
+)
+
+var knownFields = map[string]struct{}{
+	FieldName:         {},
+	FieldDescription:  {},
+	FieldCategoryPath: {},
+	FieldAttributes:   {},
+	FieldShopPointID:  {},
+	FieldPrice:        {},
+	FieldQuantity:     {},
+	FieldSKU:          {},
+	FieldBarcode:      {},
+	FieldOptions:      {},
+}
+
+// rawRow строка файла до проверки: имя колонки -> значение.
+// В CSV все значения строки, в JSON lines сохраняются исходные типы.
+type rawRow struct {
+	number int
+	values map[string]any
+}
+
+// rowReader последовательно читает строки файла, не загружая его целиком.
+type rowReader interface {
+	// next возвращает io.EOF, когда строки закончились
+	next() (rawRow, error)
+}
+
+func newRowReader(format string, r io.Reader) (rowReader, error) {
+	switch format {
+	case "csv":
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		header, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv header: %w", err)
+		}
+		for i := range header {
+			header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+		}
+		return &csvRowReader{reader: reader, header: header, number: 1}, nil
+	case "jsonl":
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		return &jsonRowReader{scanner: scanner}, nil
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+type csvRowReader struct {
+	reader *csv.Reader
+	header []string
+	number int
+}
+
+func (cr *csvRowReader) next() (rawRow, error) {
+	record, err := cr.reader.Read()
+	if err != nil {
+		return rawRow{}, err
+	}
+	cr.number++
+
+	values := make(map[string]any, len(cr.header))
+	for i, column := range cr.header {
+		if i < len(record) {
+			values[column] = record[i]
+		}
+	}
+
+	return rawRow{number: cr.number, values: values}, nil
+}
+
+type jsonRowReader struct {
+	scanner *bufio.Scanner
+	number  int
+}
+
+func (jr *jsonRowReader) next() (rawRow, error) {
+	for jr.scanner.Scan() {
+		jr.number++
+		line := strings.TrimSpace(jr.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		values := make(map[string]any)
+		if err := json.Unmarshal([]byte(line), &values); err != nil {
+			// битая строка не должна останавливать весь импорт
+			return rawRow{number: jr.number, values: nil}, nil
+		}
+		return rawRow{number: jr.number, values: values}, nil
+	}
+
+	if err := jr.scanner.Err(); err != nil {
+		return rawRow{}, err
+	}
+	return rawRow{}, io.EOF
+}
+
+// mappedRow значения строки, разложенные по полям товара согласно отображению.
+type mappedRow struct {
+	fields     map[string]any
+	attributes map[string]any
+}
+
+// applyMapping переносит значения колонок в поля товара. Отображение задается как
+// поле -> колонка; поля без отображения берутся из одноименных колонок.
+func applyMapping(values map[string]any, mapping map[string]string) mappedRow {
+	row := mappedRow{
+		fields:     make(map[string]any),
+		attributes: make(map[string]any),
+	}
+
+	used := make(map[string]struct{}, len(mapping))
+	for field, column := range mapping {
+		used[column] = struct{}{}
+		value, ok := values[column]
+		if !ok {
+			continue
+		}
+
+		if name, isAttr := strings.CutPrefix(field, attributePrefix); isAttr {
+			row.attributes[name] = value
+		} else {
+			row.fields[field] = value
+		}
+	}
+
+	for column, value := range values {
+		if _, ok := used[column]; ok {
+			continue
+		}
+
+		if name, isAttr := strings.CutPrefix(column, attributePrefix); isAttr {
+			if _, mapped := row.attributes[name]; !mapped {
+				row.attributes[name] = value
+			}
+			continue
+		}
+
+		if _, ok := knownFields[column]; ok {
+			if _, mapped := row.fields[column]; !mapped {
+				row.fields[column] = value
+			}
+		}
+	}
+
+	return row
+}
+
+// validateMapping проверяет, что отображение ссылается только на известные поля.
+func validateMapping(mapping map[string]string) error {
+	for field, column := range mapping {
+		if strings.TrimSpace(column) == "" {
+			return fmt.Errorf("empty column for field %q", field)
+		}
+		if strings.HasPrefix(field, attributePrefix) && len(field) > len(attributePrefix) {
+			continue
+		}
+		if _, ok := knownFields[field]; !ok {
+			return fmt.Errorf("unknown field %q", field)
+		}
+	}
+
+	return nil
+}
+
+func stringValue(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		b, _ := json.Marshal(value)
+		return string(b)
+	}
+}
+
+func floatValue(v any) (float64, error) {
+	switch value := v.(type) {
+	case float64:
+		return value, nil
+	case string:
+		return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), ",", "."), 64)
+	default:
+		return 0, errors.New("must be a number")
+	}
+}
+
+func intValue(v any) (int, error) {
+	f, err := floatValue(v)
+	if err != nil {
+		return 0, err
+	}
+	if f != float64(int(f)) {
+		return 0, errors.New("must be an integer")
+	}
+
+	return int(f), nil
+}
+
+// isEmpty считает пустыми отсутствующие значения и пустые строки CSV.
+func isEmpty(v any) bool {
+	if v == nil {
+		return true
+	}
+	s, ok := v.(string)
+	return ok && strings.TrimSpace(s) == ""
+}
+
+// splitCategoryPath разбивает путь вида "Ягоды / Клубника" или "Ягоды > Клубника".
+func splitCategoryPath(path string) []string {
+	parts := strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '>' })
+	names := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if name := strings.TrimSpace(part); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
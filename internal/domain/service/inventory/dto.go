@@ -0,0 +1,59 @@
+package inventory
+
+import (
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/pkg/geo"
+)
+
+// Причины движения остатков
+const (
+	ReasonDelivery   = "delivery"
+	ReasonSale       = "sale"
+	ReasonSpoilage   = "spoilage"
+	ReasonReturn     = "return"
+	ReasonCorrection = "correction"
+	ReasonImport     = "import"
+)
+
+// StockChange изменение позиции на точке продаж. Quantity задает новый остаток,
+// Delta сдвигает текущий; Price меняет цену. Незаданные поля не меняются.
+type StockChange struct {
+	ShopPointID uint     `json:"shop_point_id"`
+	VariantID   uint     `json:"variant_id"`
+	Price       *float64 `json:"price"`
+	Quantity    *int     `json:"quantity"`
+	Delta       *int     `json:"delta"`
+	Reason      string   `json:"reason"`
+	Note        string   `json:"note"`
+	UserID      *uint    `json:"user_id"`
+}
+
+// MovementFilter отбор записей журнала точки продаж
+type MovementFilter struct {
+	ShopPointID uint  `json:"shop_point_id"`
+	VariantID   *uint `json:"variant_id"`
+}
+
+// ThresholdSetting порог остатка магазина, задается либо для товара, либо для категории
+type ThresholdSetting struct {
+	ShopID     uint  `json:"shop_id"`
+	ProductID  *uint `json:"product_id"`
+	CategoryID *uint `json:"category_id"`
+	Threshold  int   `json:"threshold"`
+}
+
+// NearbyFilter поиск точек продаж в круге. Если указан товар или вариант,
+// остаются точки, где он есть в наличии.
+type NearbyFilter struct {
+	Area      geo.Circle `json:"area"`
+	ProductID *uint      `json:"product_id"`
+	VariantID *uint      `json:"variant_id"`
+	Limit     int        `json:"limit"`
+}
+
+// HoursSetting часовой пояс и недельное расписание точки продаж
+type HoursSetting struct {
+	ShopPointID uint                  `json:"shop_point_id"`
+	Timezone    string                `json:"timezone"`
+	Hours       []entity.OpeningHours `json:"hours"`
+}
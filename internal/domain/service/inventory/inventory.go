@@ -0,0 +1,196 @@
+package inventory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
+)
+
+// maxNoteLength ограничивает комментарий к движению остатков
+const maxNoteLength = 500
+
+// setReasons причины, с которыми остаток задается напрямую
+var setReasons = []string{ReasonCorrection, ReasonDelivery, ReasonReturn}
+
+type Repository interface {
+	// GetShopPointRole возвращает роль пользователя в магазине, которому принадлежит точка продаж,
+	// пустую строку если он не сотрудник
+	GetShopPointRole(ctx context.Context, shopPointID, userID uint) (string, error)
+	// ChangeStock меняет позицию и записывает изменение в журнал одной транзакцией
+	ChangeStock(ctx context.Context, change StockChange) (entity.InventoryItem, error)
+	SelectPointInventory(
+		ctx context.Context,
+		shopPointID uint,
+		params pagination.Params,
+	) ([]entity.InventoryItem, pagination.Page, error)
+	SelectMovements(
+		ctx context.Context,
+		filter MovementFilter,
+		params pagination.Params,
+	) ([]entity.InventoryMovement, pagination.Page, error)
+	// GetShopRole возвращает роль пользователя в магазине, пустую строку если он не сотрудник
+	GetShopRole(ctx context.Context, shopID, userID uint) (string, error)
+	SelectThresholds(ctx context.Context, shopID uint) ([]entity.LowStockThreshold, error)
+	UpsertThreshold(ctx context.Context, setting ThresholdSetting) (entity.LowStockThreshold, error)
+	DeleteThreshold(ctx context.Context, shopID, thresholdID uint) error
+	// SelectLowStock возвращает по точкам магазина позиции, остаток которых не больше порога
+	SelectLowStock(ctx context.Context, shopID uint) ([]entity.LowStockPoint, error)
+	// NotifyLowStock проверяет необработанные уменьшения остатка, уведомляет владельцев магазинов
+	// о пересечении порога и возвращает число проверенных уменьшений
+	NotifyLowStock(ctx context.Context, limit int, message func(entity.LowStockAlert) string) (int, error)
+	// SelectNearbyPoints возвращает точки продаж в круге, отсортированные по расстоянию
+	SelectNearbyPoints(ctx context.Context, filter NearbyFilter) ([]entity.NearbyShopPoint, error)
+	GetPointSchedule(ctx context.Context, shopPointID uint) (entity.Schedule, error)
+	SelectPointSchedules(ctx context.Context, shopPointIDs []uint) (map[uint]entity.Schedule, error)
+	// ReplaceHours задает часовой пояс и заменяет недельное расписание точки продаж
+	ReplaceHours(ctx context.Context, shopPointID uint, timezone string, hours []entity.OpeningHours) error
+	UpsertException(ctx context.Context, shopPointID uint, date time.Time, exception entity.ScheduleException) error
+	DeleteException(ctx context.Context, shopPointID uint, date time.Time) error
+}
+
+type inventoryService struct {
+	inventoryRepository Repository
+}
+
+func NewInventoryService(inventoryRepository Repository) *inventoryService {
+	return &inventoryService{inventoryRepository: inventoryRepository}
+}
+
+// SetStock задает цену и остаток варианта на точке продаж, создавая позицию при необходимости.
+func (s *inventoryService) SetStock(
+	ctx context.Context,
+	userID uint,
+	change StockChange,
+) (entity.InventoryItem, error) {
+	if change.Reason == "" {
+		change.Reason = ReasonCorrection
+	}
+	if !slices.Contains(setReasons, change.Reason) {
+		return entity.InventoryItem{}, badRequest("reason must be one of correction, delivery, return")
+	}
+	if change.Price == nil || change.Quantity == nil {
+		return entity.InventoryItem{}, badRequest("price and quantity are required")
+	}
+	if *change.Price < 0 {
+		return entity.InventoryItem{}, badRequest("price must not be negative")
+	}
+	if *change.Quantity < 0 {
+		return entity.InventoryItem{}, badRequest("quantity must not be negative")
+	}
+	if err := validateNote(change.Note); err != nil {
+		return entity.InventoryItem{}, err
+	}
+
+	if err := s.checkPointPermission(ctx, userID, change.ShopPointID, entity.PermissionManageCatalog); err != nil {
+		return entity.InventoryItem{}, err
+	}
+
+	change.Delta = nil
+	change.UserID = &userID
+	return s.inventoryRepository.ChangeStock(ctx, change)
+}
+
+// AdjustStock сдвигает остаток существующей позиции. Знак изменения должен
+// соответствовать причине: поставка и возврат увеличивают остаток, продажа и списание уменьшают.
+func (s *inventoryService) AdjustStock(
+	ctx context.Context,
+	userID uint,
+	change StockChange,
+) (entity.InventoryItem, error) {
+	if change.Delta == nil || *change.Delta == 0 {
+		return entity.InventoryItem{}, badRequest("delta must not be zero")
+	}
+
+	delta := *change.Delta
+	switch change.Reason {
+	case ReasonDelivery, ReasonReturn:
+		if delta < 0 {
+			return entity.InventoryItem{}, badRequest(change.Reason + " must increase the quantity")
+		}
+	case ReasonSale, ReasonSpoilage:
+		if delta > 0 {
+			return entity.InventoryItem{}, badRequest(change.Reason + " must decrease the quantity")
+		}
+	case ReasonCorrection:
+	default:
+		return entity.InventoryItem{}, badRequest("reason must be one of delivery, sale, spoilage, return, correction")
+	}
+	if err := validateNote(change.Note); err != nil {
+		return entity.InventoryItem{}, err
+	}
+
+	// продажу и возврат на точке проводят кассиры, остальные изменения — только управляющие
+	permission := entity.PermissionManageCatalog
+	if change.Reason == ReasonSale || change.Reason == ReasonReturn {
+		permission = entity.PermissionHandleOffers
+	}
+	if err := s.checkPointPermission(ctx, userID, change.ShopPointID, permission); err != nil {
+		return entity.InventoryItem{}, err
+	}
+
+	change.Price = nil
+	change.Quantity = nil
+	change.UserID = &userID
+	return s.inventoryRepository.ChangeStock(ctx, change)
+}
+
+func (s *inventoryService) GetPointInventory(
+	ctx context.Context,
+	userID, shopPointID uint,
+	params pagination.Params,
+) ([]entity.InventoryItem, pagination.Page, error) {
+	if err := s.checkPointPermission(ctx, userID, shopPointID, entity.PermissionViewInventory); err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	return s.inventoryRepository.SelectPointInventory(ctx, shopPointID, params)
+}
+
+func (s *inventoryService) GetMovements(
+	ctx context.Context,
+	userID uint,
+	filter MovementFilter,
+	params pagination.Params,
+) ([]entity.InventoryMovement, pagination.Page, error) {
+	if err := s.checkPointPermission(ctx, userID, filter.ShopPointID, entity.PermissionViewInventory); err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	return s.inventoryRepository.SelectMovements(ctx, filter, params)
+}
+
+// checkPointPermission проверяет, что роль пользователя в магазине точки продаж дает право на действие
+func (s *inventoryService) checkPointPermission(
+	ctx context.Context,
+	userID, shopPointID uint,
+	permission string,
+) error {
+	role, err := s.inventoryRepository.GetShopPointRole(ctx, shopPointID, userID)
+	if err != nil {
+		return err
+	}
+	if !entity.RoleAllows(role, permission) {
+		return apperror.ErrInventoryForbidden
+	}
+
+	return nil
+}
+
+func validateNote(note string) error {
+	if len([]rune(note)) > maxNoteLength {
+		return badRequest("note is too long")
+	}
+
+	return nil
+}
+
+func badRequest(message string) error {
+	return &apperror.InventoryError{
+		Code:    apperror.BadRequest,
+		Message: message,
+	}
+}
@@ -0,0 +1,136 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+const (
+	// maxThreshold самый большой допустимый порог остатка
+	maxThreshold = 1_000_000
+	// alertBatchSize столько уменьшений остатка проверяется за одну транзакцию
+	alertBatchSize = 100
+	// alertPollInterval как часто воркер ищет новые уменьшения остатка
+	alertPollInterval = time.Minute
+)
+
+func (s *inventoryService) GetThresholds(
+	ctx context.Context,
+	userID, shopID uint,
+) ([]entity.LowStockThreshold, error) {
+	if err := s.checkShopPermission(ctx, userID, shopID, entity.PermissionViewInventory); err != nil {
+		return nil, err
+	}
+
+	return s.inventoryRepository.SelectThresholds(ctx, shopID)
+}
+
+// SetThreshold задает порог остатка для товара из ассортимента магазина или для категории.
+func (s *inventoryService) SetThreshold(
+	ctx context.Context,
+	userID uint,
+	setting ThresholdSetting,
+) (entity.LowStockThreshold, error) {
+	if (setting.ProductID == nil) == (setting.CategoryID == nil) {
+		return entity.LowStockThreshold{}, badRequest("exactly one of product_id and category_id is required")
+	}
+	if setting.Threshold < 0 || setting.Threshold > maxThreshold {
+		return entity.LowStockThreshold{}, badRequest(fmt.Sprintf("threshold must be between 0 and %d", maxThreshold))
+	}
+
+	if err := s.checkShopPermission(ctx, userID, setting.ShopID, entity.PermissionManageCatalog); err != nil {
+		return entity.LowStockThreshold{}, err
+	}
+
+	return s.inventoryRepository.UpsertThreshold(ctx, setting)
+}
+
+func (s *inventoryService) DeleteThreshold(ctx context.Context, userID, shopID, thresholdID uint) error {
+	if err := s.checkShopPermission(ctx, userID, shopID, entity.PermissionManageCatalog); err != nil {
+		return err
+	}
+
+	return s.inventoryRepository.DeleteThreshold(ctx, shopID, thresholdID)
+}
+
+// GetLowStockReport возвращает позиции всех точек магазина, остаток которых не больше порога.
+func (s *inventoryService) GetLowStockReport(
+	ctx context.Context,
+	userID, shopID uint,
+) (entity.LowStockReport, error) {
+	if err := s.checkShopPermission(ctx, userID, shopID, entity.PermissionViewInventory); err != nil {
+		return entity.LowStockReport{}, err
+	}
+
+	points, err := s.inventoryRepository.SelectLowStock(ctx, shopID)
+	if err != nil {
+		return entity.LowStockReport{}, err
+	}
+
+	return entity.LowStockReport{
+		ShopID:      shopID,
+		GeneratedAt: time.Now(),
+		Points:      points,
+	}, nil
+}
+
+// Run уведомляет владельцев магазинов о заканчивающихся товарах, пока не отменен контекст.
+func (s *inventoryService) Run(ctx context.Context) {
+	ticker := time.NewTicker(alertPollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.notifyPending(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *inventoryService) notifyPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := s.inventoryRepository.NotifyLowStock(ctx, alertBatchSize, lowStockMessage)
+		if err != nil {
+			log.Printf("failed to notify about low stock: %v", err)
+			return
+		}
+		if processed < alertBatchSize {
+			return
+		}
+	}
+}
+
+func lowStockMessage(alert entity.LowStockAlert) string {
+	product := fmt.Sprintf("%q", alert.ProductName)
+	if alert.SKU != "" {
+		product += " (" + alert.SKU + ")"
+	}
+
+	if alert.Quantity == 0 {
+		return fmt.Sprintf("%s is out of stock at %s", product, alert.Address)
+	}
+
+	return fmt.Sprintf("%s is running low at %s: %d left, threshold %d",
+		product, alert.Address, alert.Quantity, *alert.Threshold)
+}
+
+// checkShopPermission проверяет, что роль пользователя в магазине дает право на действие
+func (s *inventoryService) checkShopPermission(ctx context.Context, userID, shopID uint, permission string) error {
+	role, err := s.inventoryRepository.GetShopRole(ctx, shopID, userID)
+	if err != nil {
+		return err
+	}
+	if !entity.RoleAllows(role, permission) {
+		return apperror.ErrInventoryForbidden
+	}
+
+	return nil
+}
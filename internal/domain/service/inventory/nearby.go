@@ -0,0 +1,40 @@
+package inventory
+
+import (
+	"context"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+const (
+	defaultNearbyLimit = 20
+	maxNearbyLimit     = 100
+)
+
+// FindNearbyPoints ищет точки продаж в круге, ближайшие первыми
+func (s *inventoryService) FindNearbyPoints(
+	ctx context.Context,
+	filter NearbyFilter,
+) ([]entity.NearbyShopPoint, error) {
+	filter.Area = filter.Area.WithDefaults()
+	if !filter.Area.Valid() {
+		return nil, badRequest("lat must be between -90 and 90, lon between -180 and 180, radius up to 100 km")
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultNearbyLimit
+	}
+	if filter.Limit < 0 || filter.Limit > maxNearbyLimit {
+		return nil, badRequest("limit must be between 1 and 100")
+	}
+
+	points, err := s.inventoryRepository.SelectNearbyPoints(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachOpeningStatus(ctx, points); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
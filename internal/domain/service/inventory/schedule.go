@@ -0,0 +1,127 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+// maxPeriodsPerDay ограничивает число периодов работы в один день недели
+const maxPeriodsPerDay = 4
+
+// GetSchedule возвращает расписание точки продаж и ее состояние на текущий момент
+func (s *inventoryService) GetSchedule(ctx context.Context, shopPointID uint) (entity.Schedule, error) {
+	schedule, err := s.inventoryRepository.GetPointSchedule(ctx, shopPointID)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	schedule.OpeningStatus = schedule.Status(time.Now())
+	return schedule, nil
+}
+
+// SetHours заменяет часовой пояс и недельное расписание точки продаж.
+// Пустой часовой пояс означает UTC.
+func (s *inventoryService) SetHours(ctx context.Context, userID uint, setting HoursSetting) (entity.Schedule, error) {
+	if setting.Timezone == "" {
+		setting.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(setting.Timezone); err != nil || setting.Timezone == "Local" {
+		return entity.Schedule{}, badRequest("unknown timezone " + setting.Timezone)
+	}
+
+	perDay := make(map[time.Weekday]int)
+	for _, hours := range setting.Hours {
+		if hours.Weekday < time.Sunday || hours.Weekday > time.Saturday {
+			return entity.Schedule{}, badRequest("weekday must be between 0 (sunday) and 6 (saturday)")
+		}
+		perDay[hours.Weekday]++
+		if perDay[hours.Weekday] > maxPeriodsPerDay {
+			return entity.Schedule{}, badRequest("too many opening periods in one day")
+		}
+	}
+
+	if err := s.checkPointPermission(ctx, userID, setting.ShopPointID, entity.PermissionManageCatalog); err != nil {
+		return entity.Schedule{}, err
+	}
+
+	if err := s.inventoryRepository.ReplaceHours(ctx, setting.ShopPointID, setting.Timezone, setting.Hours); err != nil {
+		return entity.Schedule{}, err
+	}
+
+	return s.GetSchedule(ctx, setting.ShopPointID)
+}
+
+// SetException задает особый день точки продаж: выходной без часов работы или другие часы
+func (s *inventoryService) SetException(
+	ctx context.Context,
+	userID, shopPointID uint,
+	exception entity.ScheduleException,
+) (entity.Schedule, error) {
+	date, err := parseExceptionDate(exception.Date)
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+	if date.Before(time.Now().UTC().AddDate(0, 0, -1).Truncate(24 * time.Hour)) {
+		return entity.Schedule{}, badRequest("date must not be in the past")
+	}
+	if (exception.OpensAt == nil) != (exception.ClosesAt == nil) {
+		return entity.Schedule{}, badRequest("opens_at and closes_at must be set together")
+	}
+	if err := validateNote(exception.Note); err != nil {
+		return entity.Schedule{}, err
+	}
+
+	if err := s.checkPointPermission(ctx, userID, shopPointID, entity.PermissionManageCatalog); err != nil {
+		return entity.Schedule{}, err
+	}
+
+	if err := s.inventoryRepository.UpsertException(ctx, shopPointID, date, exception); err != nil {
+		return entity.Schedule{}, err
+	}
+
+	return s.GetSchedule(ctx, shopPointID)
+}
+
+func (s *inventoryService) DeleteException(ctx context.Context, userID, shopPointID uint, rawDate string) error {
+	date, err := parseExceptionDate(rawDate)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkPointPermission(ctx, userID, shopPointID, entity.PermissionManageCatalog); err != nil {
+		return err
+	}
+
+	return s.inventoryRepository.DeleteException(ctx, shopPointID, date)
+}
+
+// attachOpeningStatus выставляет точкам состояние работы по их расписаниям
+func (s *inventoryService) attachOpeningStatus(ctx context.Context, points []entity.NearbyShopPoint) error {
+	shopPointIDs := make([]uint, 0, len(points))
+	for _, point := range points {
+		shopPointIDs = append(shopPointIDs, point.ID)
+	}
+
+	schedules, err := s.inventoryRepository.SelectPointSchedules(ctx, shopPointIDs)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range points {
+		points[i].OpeningStatus = schedules[points[i].ID].Status(now)
+	}
+
+	return nil
+}
+
+func parseExceptionDate(raw string) (time.Time, error) {
+	date, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return time.Time{}, badRequest("date must be formatted as YYYY-MM-DD")
+	}
+
+	return date, nil
+}
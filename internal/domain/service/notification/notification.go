@@ -1,9 +1,12 @@
 package notification
 
-import "github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+import (
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
+)
 
 type Repository interface {
-	SelectUserNotifications(id string, offset, limit int) ([]entity.Notification, int, error)
+	SelectUserNotifications(id string, params pagination.Params) ([]entity.Notification, pagination.Page, error)
 }
 
 type notificationService struct {
@@ -14,6 +17,9 @@ func NewNotificationService(notificationRepository Repository) *notificationServ
 	return &notificationService{notificationRepository}
 }
 
-func (ns *notificationService) GetNotification(id string, offset int, limit int) ([]entity.Notification, int, error) {
-	return ns.notificationRepository.SelectUserNotifications(id, offset, limit)
+func (ns *notificationService) GetNotification(
+	id string,
+	params pagination.Params,
+) ([]entity.Notification, pagination.Page, error) {
+	return ns.notificationRepository.SelectUserNotifications(id, params)
 }
@@ -5,13 +5,18 @@ import (
 )
 
 type Offer struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	UserID    uint      `json:"user_id"`
-	ProductID uint      `json:"product_id"`
-	StoreID   uint      `json:"store_id"`
-	Price     float64   `json:"price"`
-	Status    string    `json:"status"`
-	ExpiresAt time.Time `json:"expires_at"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uint  `json:"id" gorm:"primaryKey"`
+	UserID    uint  `json:"user_id"`
+	ProductID uint  `json:"product_id"`
+	VariantID *uint `json:"variant_id"`
+	StoreID   uint  `json:"store_id"`
+	// ShopPointID точка магазина, где покупатель хочет забрать товар, необязательна
+	ShopPointID *uint     `json:"shop_point_id"`
+	Price       float64   `json:"price"`
+	Status      string    `json:"status"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// MaxDiscount наибольшая скидка по настройкам магазина, проверяется по цене магазина при сохранении
+	MaxDiscount *float64 `json:"-"`
 }
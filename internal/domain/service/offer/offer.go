@@ -2,60 +2,321 @@ package offer
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
 	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
+)
+
+// Границы оценки магазина покупателем
+const (
+	minRating = 1
+	maxRating = 5
 )
 
 type Repository interface {
 	InsertOffer(ctx context.Context, offer Offer) (uint, error)
 	GetOfferByID(ctx context.Context, offerID uint) (entity.Offer, error)
-	SelectUserOffers(ctx context.Context, userID uint, limit, offset int) ([]entity.Offer, int64, error)
-	UpdateOfferStatus(ctx context.Context, offerID uint, status string) (entity.Offer, error)
+	SelectUserOffers(ctx context.Context, userID uint, params pagination.Params) ([]entity.Offer, pagination.Page, error)
+	UpdateOfferStatus(ctx context.Context, offerID uint, from, to string) (entity.Offer, error)
+	UpdateOfferRating(ctx context.Context, offerID uint, rating int) (entity.Offer, error)
+	// CountNegotiationRounds возвращает число предложений покупателя на товар магазина
+	// после последнего принятого
+	CountNegotiationRounds(ctx context.Context, userID, storeID, productID uint) (int, error)
 	DeleteOffer(ctx context.Context, offerID uint) (entity.Offer, error)
 }
 
+type ScheduleProvider interface {
+	SelectStoreSchedules(ctx context.Context, shopID uint) ([]entity.Schedule, error)
+}
+
+type StoreChecker interface {
+	IsStoreVerified(ctx context.Context, shopID uint) (bool, error)
+	// GetMemberRole возвращает роль пользователя в магазине, пустую строку если он не сотрудник
+	GetMemberRole(ctx context.Context, shopID, userID uint) (string, error)
+	GetStoreSettings(ctx context.Context, storeID uint) (entity.StoreSettings, error)
+}
+
+type UserProvider interface {
+	GetUserByID(ctx context.Context, id uint) (entity.User, error)
+}
+
 type offerService struct {
-	offerRepository Repository
+	offerRepository  Repository
+	scheduleProvider ScheduleProvider
+	storeChecker     StoreChecker
+	userProvider     UserProvider
 }
 
-func NewOfferService(offerRepository Repository) *offerService {
-	return &offerService{offerRepository: offerRepository}
+func NewOfferService(
+	offerRepository Repository,
+	scheduleProvider ScheduleProvider,
+	storeChecker StoreChecker,
+	userProvider UserProvider,
+) *offerService {
+	return &offerService{
+		offerRepository:  offerRepository,
+		scheduleProvider: scheduleProvider,
+		storeChecker:     storeChecker,
+		userProvider:     userProvider,
+	}
 }
 
+// CreateOffer создает предложение по правилам торга магазина, срок действия которого
+// считается по расписанию точек магазина. Непроверенный магазин предложений не принимает,
+// покупатель должен подтвердить почту.
 func (os *offerService) CreateOffer(
 	ctx context.Context,
 	offer Offer,
 ) (uint, error) {
+	if offer.StoreID == 0 {
+		return 0, &apperror.OfferError{
+			Code:    apperror.BadRequest,
+			Message: "store_id is required",
+		}
+	}
+
+	buyer, err := os.userProvider.GetUserByID(ctx, offer.UserID)
+	if err != nil {
+		return 0, err
+	}
+	if buyer.EmailVerifiedAt == nil {
+		return 0, apperror.ErrOfferEmailNotVerified
+	}
+
+	settings, err := os.checkNegotiation(ctx, offer)
+	if err != nil {
+		return 0, err
+	}
+	offer.MaxDiscount = settings.MaxDiscount
+
+	expiresAt, err := os.pickupDeadline(ctx, offer.StoreID, time.Now().Add(settings.OfferTTL()))
+	if err != nil {
+		return 0, err
+	}
+	offer.ExpiresAt = expiresAt
+
 	return os.offerRepository.InsertOffer(ctx, offer)
 }
 
+// checkNegotiation проверяет, что магазин принимает предложения и покупатель
+// не исчерпал число попыток на этот товар, и возвращает настройки магазина
+func (os *offerService) checkNegotiation(ctx context.Context, offer Offer) (entity.StoreSettings, error) {
+	settings, err := os.storeChecker.GetStoreSettings(ctx, offer.StoreID)
+	if err != nil {
+		return entity.StoreSettings{}, err
+	}
+	if !settings.AcceptsOffers {
+		return entity.StoreSettings{}, apperror.ErrOfferStoreClosed
+	}
+	if err := os.checkStoreVerified(ctx, offer.StoreID); err != nil {
+		return entity.StoreSettings{}, err
+	}
+
+	if settings.MaxRounds != nil {
+		rounds, err := os.offerRepository.CountNegotiationRounds(ctx, offer.UserID, offer.StoreID, offer.ProductID)
+		if err != nil {
+			return entity.StoreSettings{}, err
+		}
+		if rounds >= *settings.MaxRounds {
+			return entity.StoreSettings{}, &apperror.OfferError{
+				Code:    apperror.Conflict,
+				Message: fmt.Sprintf("store allows at most %d offers for a product", *settings.MaxRounds),
+			}
+		}
+	}
+
+	return settings, nil
+}
+
+// pickupDeadline срок действия предложения: не раньше earliest и до закрытия точки магазина,
+// работающей в этот момент или открывающейся следующей, чтобы покупатель не упирался
+// в закрытую дверь. Без расписания магазина срок равен earliest.
+func (os *offerService) pickupDeadline(ctx context.Context, storeID uint, earliest time.Time) (time.Time, error) {
+	schedules, err := os.scheduleProvider.SelectStoreSchedules(ctx, storeID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	deadline := earliest
+	for _, schedule := range schedules {
+		if !schedule.Known() {
+			continue
+		}
+		if pointDeadline, ok := schedule.PickupDeadline(earliest); ok && pointDeadline.After(deadline) {
+			deadline = pointDeadline
+		}
+	}
+
+	return deadline, nil
+}
+
+// GetOffer отдает предложение покупателю или сотруднику магазина, которому оно адресовано
 func (os *offerService) GetOffer(
 	ctx context.Context,
-	offerID uint,
+	userID, offerID uint,
 ) (entity.Offer, error) {
-	return os.offerRepository.GetOfferByID(ctx, offerID)
+	offer, err := os.offerRepository.GetOfferByID(ctx, offerID)
+	if err != nil {
+		return entity.Offer{}, err
+	}
+	if offer.UserID == userID {
+		return offer, nil
+	}
+
+	role, err := os.storeChecker.GetMemberRole(ctx, offer.StoreID, userID)
+	if err != nil {
+		return entity.Offer{}, err
+	}
+	if !entity.RoleAllows(role, entity.PermissionHandleOffers) {
+		return entity.Offer{}, apperror.ErrOfferForbidden
+	}
+
+	return offer, nil
 }
 
 func (os *offerService) GetUserOffers(
 	ctx context.Context,
 	userID uint,
-	limit,
-	offset int,
-) ([]entity.Offer, int64, error) {
-	return os.offerRepository.SelectUserOffers(ctx, userID, limit, offset)
+	params pagination.Params,
+) ([]entity.Offer, pagination.Page, error) {
+	return os.offerRepository.SelectUserOffers(ctx, userID, params)
 }
 
+// UpdateOfferStatus меняет статус предложения. Отвечать на предложения могут сотрудники магазина,
+// которым это позволяет роль, принять предложение может только проверенный магазин и только
+// если цена не ниже допустимой по его текущим настройкам. Ожидающее предложение можно
+// принять или отклонить, пока оно не истекло, выдать товар можно только по принятому.
 func (os *offerService) UpdateOfferStatus(
 	ctx context.Context,
-	offerID uint,
+	userID, offerID uint,
 	status string,
 ) (entity.Offer, error) {
-	return os.offerRepository.UpdateOfferStatus(ctx, offerID, status)
+	switch status {
+	case entity.OfferStatusAccepted, entity.OfferStatusRejected, entity.OfferStatusCompleted:
+	default:
+		return entity.Offer{}, &apperror.OfferError{
+			Code:    apperror.BadRequest,
+			Message: "status must be one of accepted, rejected, completed",
+		}
+	}
+
+	offer, err := os.offerRepository.GetOfferByID(ctx, offerID)
+	if err != nil {
+		return entity.Offer{}, err
+	}
+	if !entity.CanTransitionOffer(offer.Status, status) {
+		return entity.Offer{}, &apperror.OfferError{
+			Code:    apperror.Conflict,
+			Message: fmt.Sprintf("offer cannot move from %s to %s", offer.Status, status),
+		}
+	}
+	if offer.Status == entity.OfferStatusPending && offer.ExpiresAt.Before(time.Now()) {
+		return entity.Offer{}, &apperror.OfferError{
+			Code:    apperror.Conflict,
+			Message: "offer has expired",
+		}
+	}
+
+	role, err := os.storeChecker.GetMemberRole(ctx, offer.StoreID, userID)
+	if err != nil {
+		return entity.Offer{}, err
+	}
+	if !entity.RoleAllows(role, entity.PermissionHandleOffers) {
+		return entity.Offer{}, apperror.ErrOfferForbidden
+	}
+
+	if status == entity.OfferStatusAccepted {
+		if err := os.checkStoreVerified(ctx, offer.StoreID); err != nil {
+			return entity.Offer{}, err
+		}
+		if err := os.checkMinPrice(ctx, offer); err != nil {
+			return entity.Offer{}, err
+		}
+	}
+
+	return os.offerRepository.UpdateOfferStatus(ctx, offerID, offer.Status, status)
 }
 
+// RateOffer записывает оценку магазина покупателем. Оценить можно только принятое предложение,
+// повторная оценка заменяет прежнюю.
+func (os *offerService) RateOffer(ctx context.Context, userID, offerID uint, rating int) (entity.Offer, error) {
+	if rating < minRating || rating > maxRating {
+		return entity.Offer{}, &apperror.OfferError{
+			Code:    apperror.BadRequest,
+			Message: fmt.Sprintf("rating must be between %d and %d", minRating, maxRating),
+		}
+	}
+
+	offer, err := os.offerRepository.GetOfferByID(ctx, offerID)
+	if err != nil {
+		return entity.Offer{}, err
+	}
+	if offer.UserID != userID {
+		return entity.Offer{}, &apperror.OfferError{
+			Code:    apperror.Forbidden,
+			Message: "only the buyer may rate the store",
+		}
+	}
+	if offer.Status != entity.OfferStatusAccepted && offer.Status != entity.OfferStatusCompleted {
+		return entity.Offer{}, &apperror.OfferError{
+			Code:    apperror.Conflict,
+			Message: "only accepted offers can be rated",
+		}
+	}
+
+	return os.offerRepository.UpdateOfferRating(ctx, offerID, rating)
+}
+
+// checkMinPrice проверяет, что скидка в предложении не больше допустимой магазином
+func (os *offerService) checkMinPrice(ctx context.Context, offer entity.Offer) error {
+	if offer.ListPrice == nil {
+		return nil
+	}
+
+	settings, err := os.storeChecker.GetStoreSettings(ctx, offer.StoreID)
+	if err != nil {
+		return err
+	}
+	if minPrice := entity.MinOfferPrice(*offer.ListPrice, settings.MaxDiscount); offer.Price < minPrice {
+		return &apperror.OfferError{
+			Code:    apperror.Conflict,
+			Message: fmt.Sprintf("offer price is below the store minimum of %.2f", minPrice),
+		}
+	}
+
+	return nil
+}
+
+func (os *offerService) checkStoreVerified(ctx context.Context, storeID uint) error {
+	verified, err := os.storeChecker.IsStoreVerified(ctx, storeID)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return apperror.ErrOfferStoreNotVerified
+	}
+
+	return nil
+}
+
+// DeleteOffer удаляет предложение, отозвать его может только покупатель
 func (os *offerService) DeleteOffer(
 	ctx context.Context,
-	offerID uint,
+	userID, offerID uint,
 ) (entity.Offer, error) {
+	offer, err := os.offerRepository.GetOfferByID(ctx, offerID)
+	if err != nil {
+		return entity.Offer{}, err
+	}
+	if offer.UserID != userID {
+		return entity.Offer{}, &apperror.OfferError{
+			Code:    apperror.Forbidden,
+			Message: "only the buyer may withdraw the offer",
+		}
+	}
+
 	return os.offerRepository.DeleteOffer(ctx, offerID)
 }
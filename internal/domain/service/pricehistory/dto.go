@@ -0,0 +1,8 @@
+package pricehistory
+
+// Filter сужает историю цен товара до варианта и/или точки продаж.
+type Filter struct {
+	ProductID   uint  `json:"product_id"`
+	VariantID   *uint `json:"variant_id"`
+	ShopPointID *uint `json:"shop_point_id"`
+}
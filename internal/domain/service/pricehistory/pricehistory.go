@@ -0,0 +1,178 @@
+package pricehistory
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+const (
+	// maxWindowDays самое длинное окно статистики
+	maxWindowDays = 365
+	// maxWindows ограничивает число окон в одном запросе
+	maxWindows = 5
+	// historyLimit столько последних изменений отдается вместе со статистикой
+	historyLimit = 1000
+	// lowestWindowDays за этот срок снижение цены сравнивается с минимумом
+	lowestWindowDays = 30
+	// dropBatchSize столько снижений цен обрабатывается за одну транзакцию
+	dropBatchSize = 100
+	// pollInterval как часто воркер ищет новые снижения цен
+	pollInterval = time.Minute
+)
+
+// DefaultWindows окна статистики, если клиент их не указал
+var DefaultWindows = []int{7, 30, 90}
+
+type Repository interface {
+	// ProductExists проверяет, что товар есть в каталоге и не в архиве
+	ProductExists(ctx context.Context, productID uint) (bool, error)
+	SelectPriceStats(ctx context.Context, filter Filter, since time.Time) (entity.PriceStats, error)
+	SelectPriceHistory(ctx context.Context, filter Filter, since time.Time, limit int) ([]entity.PricePoint, error)
+	// SelectCurrentPrice возвращает минимальную из действующих цен, nil если цен нет
+	SelectCurrentPrice(ctx context.Context, filter Filter) (*float64, error)
+	// NotifyPriceDrops рассылает уведомления о необработанных снижениях цен покупателям,
+	// которые делали предложения на товар, и возвращает число обработанных снижений
+	NotifyPriceDrops(
+		ctx context.Context,
+		limit int,
+		lowestSince time.Duration,
+		message func(entity.PriceDrop) string,
+	) (int, error)
+}
+
+type priceHistoryService struct {
+	priceHistoryRepository Repository
+}
+
+func NewPriceHistoryService(priceHistoryRepository Repository) *priceHistoryService {
+	return &priceHistoryService{priceHistoryRepository: priceHistoryRepository}
+}
+
+// GetPriceHistory возвращает статистику цен товара по окнам и историю изменений
+// за самое длинное из них.
+func (s *priceHistoryService) GetPriceHistory(
+	ctx context.Context,
+	filter Filter,
+	windows []int,
+) (entity.PriceHistory, error) {
+	windows, err := normalizeWindows(windows)
+	if err != nil {
+		return entity.PriceHistory{}, err
+	}
+
+	exists, err := s.priceHistoryRepository.ProductExists(ctx, filter.ProductID)
+	if err != nil {
+		return entity.PriceHistory{}, err
+	}
+	if !exists {
+		return entity.PriceHistory{}, apperror.ErrProductNotFound
+	}
+
+	now := time.Now()
+	history := entity.PriceHistory{
+		ProductID: filter.ProductID,
+		Windows:   make([]entity.PriceStats, 0, len(windows)),
+	}
+
+	for _, days := range windows {
+		stats, err := s.priceHistoryRepository.SelectPriceStats(ctx, filter, now.AddDate(0, 0, -days))
+		if err != nil {
+			return entity.PriceHistory{}, err
+		}
+		stats.Days = days
+		history.Windows = append(history.Windows, stats)
+	}
+
+	if history.CurrentPrice, err = s.priceHistoryRepository.SelectCurrentPrice(ctx, filter); err != nil {
+		return entity.PriceHistory{}, err
+	}
+
+	since := now.AddDate(0, 0, -windows[len(windows)-1])
+	if history.History, err = s.priceHistoryRepository.SelectPriceHistory(ctx, filter, since, historyLimit); err != nil {
+		return entity.PriceHistory{}, err
+	}
+
+	return history, nil
+}
+
+// Run рассылает уведомления о снижении цен, пока не отменен контекст.
+func (s *priceHistoryService) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.notifyPending(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *priceHistoryService) notifyPending(ctx context.Context) {
+	lowestSince := lowestWindowDays * 24 * time.Hour
+	for ctx.Err() == nil {
+		processed, err := s.priceHistoryRepository.NotifyPriceDrops(ctx, dropBatchSize, lowestSince, priceDropMessage)
+		if err != nil {
+			log.Printf("failed to notify about price drops: %v", err)
+			return
+		}
+		if processed < dropBatchSize {
+			return
+		}
+	}
+}
+
+func priceDropMessage(drop entity.PriceDrop) string {
+	message := fmt.Sprintf(
+		"Price of %q dropped from %s to %s",
+		drop.ProductName,
+		strconv.FormatFloat(drop.PreviousPrice, 'f', 2, 64),
+		strconv.FormatFloat(drop.Price, 'f', 2, 64),
+	)
+	if drop.Address != "" {
+		message += " at " + drop.Address
+	}
+	if drop.LowestPrice == nil || drop.Price < *drop.LowestPrice {
+		message += fmt.Sprintf(", the lowest price in %d days", lowestWindowDays)
+	}
+
+	return message
+}
+
+// normalizeWindows проверяет окна и упорядочивает их по возрастанию без повторов.
+func normalizeWindows(windows []int) ([]int, error) {
+	if len(windows) == 0 {
+		return slices.Clone(DefaultWindows), nil
+	}
+
+	if len(windows) > maxWindows {
+		return nil, &apperror.ProductError{
+			Code:    apperror.BadRequest,
+			Message: fmt.Sprintf("at most %d windows are allowed", maxWindows),
+		}
+	}
+
+	for _, days := range windows {
+		if days < 1 || days > maxWindowDays {
+			return nil, &apperror.ProductError{
+				Code:    apperror.BadRequest,
+				Message: fmt.Sprintf("window must be between 1 and %d days", maxWindowDays),
+			}
+		}
+	}
+
+	windows = slices.Clone(windows)
+	slices.Sort(windows)
+
+	return slices.Compact(windows), nil
+}
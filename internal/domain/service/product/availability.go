@@ -0,0 +1,82 @@
+package product
+
+import (
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+// applyAvailability выставляет товару наличие по остаткам магазинов.
+// Остаток магазина — сумма остатков его точек. Товар в наличии, если он есть
+// хотя бы в одном магазине, не снявшем его с продажи, и цены считаются только по таким магазинам.
+func applyAvailability(product *entity.Product, shops []entity.ShopAvailability) {
+	product.InStock = false
+	product.MinPrice = nil
+	product.MaxPrice = nil
+	product.Availability = shops
+	if product.Availability == nil {
+		product.Availability = []entity.ShopAvailability{}
+	}
+
+	for i := range product.Availability {
+		shop := &product.Availability[i]
+		shop.Quantity = 0
+		for _, point := range shop.Points {
+			shop.Quantity += point.Quantity
+		}
+		shop.InStock = shop.IsAvailable && shop.Quantity > 0
+		if !shop.InStock {
+			continue
+		}
+
+		product.InStock = true
+		for _, point := range shop.Points {
+			product.MinPrice = minPrice(product.MinPrice, point.MinPrice)
+			product.MaxPrice = maxPrice(product.MaxPrice, point.MaxPrice)
+		}
+	}
+}
+
+// applyOpeningStatus выставляет точкам продаж товара состояние работы на момент now
+func applyOpeningStatus(product *entity.Product, schedules map[uint]entity.Schedule, now time.Time) {
+	for i := range product.Availability {
+		points := product.Availability[i].Points
+		for j := range points {
+			points[j].OpeningStatus = schedules[points[j].ShopPointID].Status(now)
+		}
+	}
+}
+
+// availabilityPointIDs точки продаж, встречающиеся в наличии товаров, без повторов
+func availabilityPointIDs(availability map[uint][]entity.ShopAvailability) []uint {
+	seen := make(map[uint]bool)
+	var shopPointIDs []uint
+	for _, shops := range availability {
+		for _, shop := range shops {
+			for _, point := range shop.Points {
+				if !seen[point.ShopPointID] {
+					seen[point.ShopPointID] = true
+					shopPointIDs = append(shopPointIDs, point.ShopPointID)
+				}
+			}
+		}
+	}
+
+	return shopPointIDs
+}
+
+func minPrice(current, price *float64) *float64 {
+	if price == nil || (current != nil && *current <= *price) {
+		return current
+	}
+
+	return price
+}
+
+func maxPrice(current, price *float64) *float64 {
+	if price == nil || (current != nil && *current >= *price) {
+		return current
+	}
+
+	return price
+}
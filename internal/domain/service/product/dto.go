@@ -2,25 +2,55 @@ package product
 
 import (
 	"time"
+
+	"github.com/zuzaaa-dev/stawberry/pkg/geo"
 )
 
 type Product struct {
-	ID          uint      `json:"id" gorm:"primaryKey"`
-	StoreID     uint      `json:"store_id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	Category    string    `json:"category"`
-	InStock     bool      `json:"in_stock"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	StoreID     uint           `json:"store_id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Price       float64        `json:"price"`
+	CategoryID  *uint          `json:"category_id"`
+	Attributes  map[string]any `json:"attributes"`
+	InStock     bool           `json:"in_stock"`
+	Variants    []Variant      `json:"variants"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
 }
 
 type UpdateProduct struct {
-	StoreID     *uint    `json:"store_id,omitempty"`
-	Name        *string  `json:"name,omitempty"`
-	Description *string  `json:"description,omitempty"`
-	Price       *float64 `json:"price,omitempty"`
-	Category    *string  `json:"category,omitempty"`
-	InStock     *bool    `json:"in_stock,omitempty"`
+	Name        *string        `json:"name,omitempty"`
+	Description *string        `json:"description,omitempty"`
+	Price       *float64       `json:"price,omitempty"`
+	CategoryID  *uint          `json:"category_id,omitempty"`
+	Attributes  map[string]any `json:"attributes,omitempty"`
+}
+
+type Variant struct {
+	ProductID uint              `json:"product_id"`
+	SKU       string            `json:"sku"`
+	Barcode   string            `json:"barcode"`
+	Options   map[string]string `json:"options"`
+}
+
+type UpdateVariant struct {
+	SKU     *string           `json:"sku,omitempty"`
+	Barcode *string           `json:"barcode,omitempty"`
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// Filter отбирает товары по наличию. Если указан магазин, точка продаж или область
+// поиска, остаются товары, которые есть в наличии именно там.
+type Filter struct {
+	InStock     bool
+	ShopID      *uint
+	ShopPointID *uint
+	Near        *geo.Circle
+}
+
+// Available сообщает, что нужны только товары в наличии.
+func (f Filter) Available() bool {
+	return f.InStock || f.ShopID != nil || f.ShopPointID != nil || f.Near != nil
 }
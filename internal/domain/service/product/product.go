@@ -2,30 +2,115 @@ package product
 
 import (
 	"context"
+	"errors"
+	"strconv"
+	"time"
 
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
 	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
 )
 
 type Repository interface {
 	InsertProduct(ctx context.Context, product Product) (uint, error)
 	GetProductByID(ctx context.Context, id string) (entity.Product, error)
-	SelectProducts(ctx context.Context, offset, limit int) ([]entity.Product, int, error)
-	SelectStoreProducts(ctx context.Context, id string, offset, limit int) ([]entity.Product, int, error)
+	SelectProducts(
+		ctx context.Context,
+		filter Filter,
+		params pagination.Params,
+	) ([]entity.Product, pagination.Page, error)
+	SelectStoreProducts(
+		ctx context.Context,
+		id string,
+		params pagination.Params,
+	) ([]entity.Product, pagination.Page, error)
 	UpdateProduct(ctx context.Context, id string, update UpdateProduct) error
+	ArchiveProduct(ctx context.Context, id uint) error
+	RestoreProduct(ctx context.Context, id uint) error
+	DeleteProduct(ctx context.Context, id uint) ([]string, error)
+	// SelectProductsAvailability возвращает магазины, в ассортименте которых есть товары,
+	// с остатками и ценами на их точках продаж
+	SelectProductsAvailability(ctx context.Context, productIDs []uint) (map[uint][]entity.ShopAvailability, error)
+}
+
+type VariantRepository interface {
+	InsertVariant(ctx context.Context, variant Variant) (entity.ProductVariant, error)
+	SelectProductsVariants(ctx context.Context, productIDs []uint) (map[uint][]entity.ProductVariant, error)
+	UpdateVariant(ctx context.Context, productID, variantID uint, update UpdateVariant) (entity.ProductVariant, error)
+	DeleteVariant(ctx context.Context, productID, variantID uint) error
+}
+
+type AttributeValidator interface {
+	ValidateAttributes(ctx context.Context, categoryID uint, attributes map[string]any) ([]apperror.FieldError, error)
+}
+
+type ImageProvider interface {
+	GetProductsImages(ctx context.Context, productIDs []uint) (map[uint][]entity.ProductImage, error)
+	RemoveImageObjects(ctx context.Context, keys []string)
+}
+
+type RoleChecker interface {
+	// GetProductRoles возвращает роли пользователя в магазинах, в ассортименте которых есть товар
+	GetProductRoles(ctx context.Context, productID, userID uint) ([]string, error)
+	// GetMemberRole возвращает роль пользователя в магазине, пустую строку если он не сотрудник
+	GetMemberRole(ctx context.Context, shopID, userID uint) (string, error)
+}
+
+type ScheduleProvider interface {
+	SelectPointSchedules(ctx context.Context, shopPointIDs []uint) (map[uint]entity.Schedule, error)
 }
 
 type productService struct {
-	productRepository Repository
+	productRepository  Repository
+	variantRepository  VariantRepository
+	attributeValidator AttributeValidator
+	imageProvider      ImageProvider
+	roleChecker        RoleChecker
+	scheduleProvider   ScheduleProvider
 }
 
-func NewProductService(productRepo Repository) *productService {
-	return &productService{productRepository: productRepo}
+func NewProductService(
+	productRepo Repository,
+	variantRepo VariantRepository,
+	attributeValidator AttributeValidator,
+	imageProvider ImageProvider,
+	roleChecker RoleChecker,
+	scheduleProvider ScheduleProvider,
+) *productService {
+	return &productService{
+		productRepository:  productRepo,
+		variantRepository:  variantRepo,
+		attributeValidator: attributeValidator,
+		imageProvider:      imageProvider,
+		roleChecker:        roleChecker,
+		scheduleProvider:   scheduleProvider,
+	}
 }
 
+// CreateProduct создает товар. Товар в ассортименте магазина может завести только сотрудник,
+// которому роль позволяет менять каталог.
 func (ps *productService) CreateProduct(
 	ctx context.Context,
+	userID uint,
 	product Product,
 ) (uint, error) {
+	if err := ps.validateAttributes(ctx, product.CategoryID, product.Attributes); err != nil {
+		return 0, err
+	}
+	if err := validateVariants(product.Variants); err != nil {
+		return 0, err
+	}
+
+	if product.StoreID != 0 {
+		role, err := ps.roleChecker.GetMemberRole(ctx, product.StoreID, userID)
+		if err != nil {
+			return 0, err
+		}
+		if !entity.RoleAllows(role, entity.PermissionManageCatalog) {
+			return 0, apperror.ErrProductForbidden
+		}
+	}
+
 	return ps.productRepository.InsertProduct(ctx, product)
 }
 
@@ -33,30 +118,231 @@ func (ps *productService) GetProductByID(
 	ctx context.Context,
 	id string,
 ) (entity.Product, error) {
-	return ps.productRepository.GetProductByID(ctx, id)
+	product, err := ps.productRepository.GetProductByID(ctx, id)
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	products := []entity.Product{product}
+	if err := ps.attachDetails(ctx, products); err != nil {
+		return entity.Product{}, err
+	}
+
+	return products[0], nil
 }
 
+// GetProducts возвращает страницу каталога, при отборе по наличию только товары в наличии.
 func (ps *productService) GetProducts(
 	ctx context.Context,
-	offset,
-	limit int,
-) ([]entity.Product, int, error) {
-	return ps.productRepository.SelectProducts(ctx, offset, limit)
+	filter Filter,
+	params pagination.Params,
+) ([]entity.Product, pagination.Page, error) {
+	if filter.Near != nil {
+		near := filter.Near.WithDefaults()
+		if !near.Valid() {
+			return nil, pagination.Page{}, &apperror.ProductError{
+				Code:    apperror.BadRequest,
+				Message: "lat must be between -90 and 90, lon between -180 and 180, radius up to 100 km",
+			}
+		}
+		filter.Near = &near
+	}
+
+	products, page, err := ps.productRepository.SelectProducts(ctx, filter, params)
+	if err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	if err := ps.attachDetails(ctx, products); err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	return products, page, nil
 }
 
 func (ps *productService) GetStoreProducts(
 	ctx context.Context,
 	id string,
-	offset,
-	limit int,
-) ([]entity.Product, int, error) {
-	return ps.productRepository.SelectStoreProducts(ctx, id, offset, limit)
+	params pagination.Params,
+) ([]entity.Product, pagination.Page, error) {
+	products, page, err := ps.productRepository.SelectStoreProducts(ctx, id, params)
+	if err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	if err := ps.attachDetails(ctx, products); err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	return products, page, nil
 }
 
+// UpdateProduct обновляет товар, в том числе цену, если роль пользователя позволяет менять каталог.
+// При смене категории или атрибутов итоговые атрибуты заново проверяются по схеме категории.
 func (ps *productService) UpdateProduct(
 	ctx context.Context,
+	userID uint,
 	id string,
 	updateProduct UpdateProduct,
 ) error {
+	productID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return apperror.ErrProductNotFound
+	}
+	if err := ps.checkOwner(ctx, userID, uint(productID)); err != nil {
+		return err
+	}
+
+	if updateProduct.CategoryID != nil || updateProduct.Attributes != nil {
+		current, err := ps.productRepository.GetProductByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		categoryID := current.CategoryID
+		if updateProduct.CategoryID != nil {
+			categoryID = updateProduct.CategoryID
+		}
+
+		attributes := current.Attributes
+		if updateProduct.Attributes != nil {
+			attributes = updateProduct.Attributes
+		}
+
+		if err := ps.validateAttributes(ctx, categoryID, attributes); err != nil {
+			return err
+		}
+	}
+
 	return ps.productRepository.UpdateProduct(ctx, id, updateProduct)
 }
+
+// ArchiveProduct скрывает товар из каталога. Предложения на него сохраняются.
+func (ps *productService) ArchiveProduct(ctx context.Context, userID, id uint) error {
+	if err := ps.checkOwner(ctx, userID, id); err != nil {
+		return err
+	}
+
+	return ps.productRepository.ArchiveProduct(ctx, id)
+}
+
+// RestoreProduct возвращает архивный товар в каталог.
+func (ps *productService) RestoreProduct(ctx context.Context, userID, id uint) error {
+	if err := ps.checkOwner(ctx, userID, id); err != nil {
+		return err
+	}
+
+	return ps.productRepository.RestoreProduct(ctx, id)
+}
+
+// DeleteProduct удаляет товар навсегда, если на него нет предложений,
+// и после этого убирает его изображения из хранилища.
+func (ps *productService) DeleteProduct(ctx context.Context, userID, id uint) error {
+	if err := ps.checkOwner(ctx, userID, id); err != nil {
+		return err
+	}
+
+	imageKeys, err := ps.productRepository.DeleteProduct(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	ps.imageProvider.RemoveImageObjects(ctx, imageKeys)
+
+	return nil
+}
+
+// checkOwner проверяет, что товар, в том числе архивный, есть в ассортименте магазина,
+// где роль пользователя позволяет менять каталог.
+func (ps *productService) checkOwner(ctx context.Context, userID, productID uint) error {
+	roles, err := ps.roleChecker.GetProductRoles(ctx, productID, userID)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if entity.RoleAllows(role, entity.PermissionManageCatalog) {
+			return nil
+		}
+	}
+
+	return apperror.ErrProductForbidden
+}
+
+// validateAttributes проверяет атрибуты товара по схеме его категории.
+func (ps *productService) validateAttributes(
+	ctx context.Context,
+	categoryID *uint,
+	attributes map[string]any,
+) error {
+	if categoryID == nil {
+		return nil
+	}
+
+	fieldErrors, err := ps.attributeValidator.ValidateAttributes(ctx, *categoryID, attributes)
+	if err != nil {
+		var categoryErr *apperror.CategoryError
+		if errors.As(err, &categoryErr) && categoryErr.Code == apperror.NotFound {
+			return &apperror.ProductError{
+				Code:    apperror.BadRequest,
+				Message: "category not found",
+				Err:     err,
+			}
+		}
+		return err
+	}
+
+	if len(fieldErrors) > 0 {
+		return &apperror.ProductError{
+			Code:    apperror.Validation,
+			Message: "product attributes do not match category schema",
+			Fields:  fieldErrors,
+		}
+	}
+
+	return nil
+}
+
+// attachDetails дополняет товары их изображениями, вариантами, наличием и состоянием
+// работы точек продаж одним запросом на всю страницу для каждого вида данных.
+func (ps *productService) attachDetails(ctx context.Context, products []entity.Product) error {
+	productIDs := make([]uint, 0, len(products))
+	for _, p := range products {
+		productIDs = append(productIDs, p.ID)
+	}
+
+	images, err := ps.imageProvider.GetProductsImages(ctx, productIDs)
+	if err != nil {
+		return err
+	}
+
+	variants, err := ps.variantRepository.SelectProductsVariants(ctx, productIDs)
+	if err != nil {
+		return err
+	}
+
+	availability, err := ps.productRepository.SelectProductsAvailability(ctx, productIDs)
+	if err != nil {
+		return err
+	}
+
+	schedules, err := ps.scheduleProvider.SelectPointSchedules(ctx, availabilityPointIDs(availability))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	for i := range products {
+		products[i].Images = images[products[i].ID]
+		if products[i].Images == nil {
+			products[i].Images = []entity.ProductImage{}
+		}
+		products[i].Variants = variants[products[i].ID]
+		if products[i].Variants == nil {
+			products[i].Variants = []entity.ProductVariant{}
+		}
+		applyAvailability(&products[i], availability[products[i].ID])
+		applyOpeningStatus(&products[i], schedules, now)
+	}
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+const (
+	maxVariantCodeLength = 64
+	minBarcodeLength     = 8
+	maxBarcodeLength     = 14
+)
+
+// GetProductVariants возвращает варианты товара в порядке их позиций
+func (ps *productService) GetProductVariants(ctx context.Context, productID uint) ([]entity.ProductVariant, error) {
+	if _, err := ps.productRepository.GetProductByID(ctx, strconv.FormatUint(uint64(productID), 10)); err != nil {
+		return nil, err
+	}
+
+	variants, err := ps.variantRepository.SelectProductsVariants(ctx, []uint{productID})
+	if err != nil {
+		return nil, err
+	}
+
+	if variants[productID] == nil {
+		return []entity.ProductVariant{}, nil
+	}
+
+	return variants[productID], nil
+}
+
+// CreateVariant добавляет товару новый вариант
+func (ps *productService) CreateVariant(
+	ctx context.Context,
+	userID uint,
+	variant Variant,
+) (entity.ProductVariant, error) {
+	if err := ps.checkOwner(ctx, userID, variant.ProductID); err != nil {
+		return entity.ProductVariant{}, err
+	}
+
+	if err := validateVariant(variant.SKU, variant.Barcode, variant.Options); err != nil {
+		return entity.ProductVariant{}, err
+	}
+
+	if err := ps.checkSiblingOptions(ctx, variant.ProductID, 0, variant.Options); err != nil {
+		return entity.ProductVariant{}, err
+	}
+
+	return ps.variantRepository.InsertVariant(ctx, variant)
+}
+
+// UpdateVariant меняет артикул, штрихкод или опции варианта
+func (ps *productService) UpdateVariant(
+	ctx context.Context,
+	userID, productID, variantID uint,
+	update UpdateVariant,
+) (entity.ProductVariant, error) {
+	if err := ps.checkOwner(ctx, userID, productID); err != nil {
+		return entity.ProductVariant{}, err
+	}
+
+	var sku, barcode string
+	if update.SKU != nil {
+		sku = *update.SKU
+	}
+	if update.Barcode != nil {
+		barcode = *update.Barcode
+	}
+	if err := validateVariant(sku, barcode, update.Options); err != nil {
+		return entity.ProductVariant{}, err
+	}
+
+	if update.Options != nil {
+		if err := ps.checkSiblingOptions(ctx, productID, variantID, update.Options); err != nil {
+			return entity.ProductVariant{}, err
+		}
+	}
+
+	return ps.variantRepository.UpdateVariant(ctx, productID, variantID, update)
+}
+
+// DeleteVariant удаляет вариант товара вместе с его остатками
+func (ps *productService) DeleteVariant(ctx context.Context, userID, productID, variantID uint) error {
+	if err := ps.checkOwner(ctx, userID, productID); err != nil {
+		return err
+	}
+
+	return ps.variantRepository.DeleteVariant(ctx, productID, variantID)
+}
+
+// checkSiblingOptions проверяет, что у других вариантов товара нет такого же набора опций,
+// по тем же правилам, что validateVariants. Вариант variantID при сравнении пропускается.
+func (ps *productService) checkSiblingOptions(
+	ctx context.Context,
+	productID, variantID uint,
+	options map[string]string,
+) error {
+	variants, err := ps.variantRepository.SelectProductsVariants(ctx, []uint{productID})
+	if err != nil {
+		return err
+	}
+
+	key := optionsKey(options)
+	for _, sibling := range variants[productID] {
+		if sibling.ID != variantID && optionsKey(sibling.Options) == key {
+			return &apperror.ProductError{
+				Code:    apperror.BadRequest,
+				Message: fmt.Sprintf("variant %d already has the same options", sibling.ID),
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateVariants проверяет варианты нового товара. Два варианта
+// с одинаковым набором опций покупатель не различит, поэтому они запрещены.
+func validateVariants(variants []Variant) error {
+	seen := make(map[string]int, len(variants))
+	skus := make(map[string]int, len(variants))
+	for i, v := range variants {
+		if err := validateVariant(v.SKU, v.Barcode, v.Options); err != nil {
+			return err
+		}
+
+		key := optionsKey(v.Options)
+		if j, ok := seen[key]; ok {
+			return &apperror.ProductError{
+				Code:    apperror.BadRequest,
+				Message: fmt.Sprintf("variants %d and %d have the same options", j+1, i+1),
+			}
+		}
+		seen[key] = i
+
+		if v.SKU == "" {
+			continue
+		}
+		if j, ok := skus[v.SKU]; ok {
+			return &apperror.ProductError{
+				Code:    apperror.BadRequest,
+				Message: fmt.Sprintf("variants %d and %d have the same sku", j+1, i+1),
+			}
+		}
+		skus[v.SKU] = i
+	}
+
+	return nil
+}
+
+func validateVariant(sku, barcode string, options map[string]string) error {
+	if utf8.RuneCountInString(sku) > maxVariantCodeLength {
+		return &apperror.ProductError{
+			Code:    apperror.BadRequest,
+			Message: fmt.Sprintf("sku must be at most %d characters", maxVariantCodeLength),
+		}
+	}
+
+	if barcode != "" {
+		if len(barcode) < minBarcodeLength || len(barcode) > maxBarcodeLength || !isDigits(barcode) {
+			return &apperror.ProductError{
+				Code: apperror.BadRequest,
+				Message: fmt.Sprintf(
+					"barcode must contain from %d to %d digits", minBarcodeLength, maxBarcodeLength,
+				),
+			}
+		}
+	}
+
+	for name, value := range options {
+		if strings.TrimSpace(name) == "" || strings.TrimSpace(value) == "" {
+			return &apperror.ProductError{
+				Code:    apperror.BadRequest,
+				Message: "variant option names and values must not be empty",
+			}
+		}
+	}
+
+	return nil
+}
+
+// optionsKey строит ключ набора опций, не зависящий от порядка
+func optionsKey(options map[string]string) string {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(strings.ToLower(name))
+		b.WriteByte(0)
+		b.WriteString(strings.ToLower(options[name]))
+		b.WriteByte(0)
+	}
+
+	return b.String()
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
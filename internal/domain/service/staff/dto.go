@@ -0,0 +1,19 @@
+package staff
+
+import "time"
+
+// Invitation новое приглашение сотрудника. В базе хранится только хеш токена из письма.
+type Invitation struct {
+	StoreID   uint      `json:"store_id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	TokenHash string    `json:"-"`
+	InvitedBy uint      `json:"invited_by"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Notice уведомление, которое записывается вместе с изменением состава сотрудников
+type Notice struct {
+	UserID  uint   `json:"user_id"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,283 @@
+package staff
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/pkg/mailer"
+	"github.com/zuzaaa-dev/stawberry/pkg/security"
+)
+
+// invitationTTL сколько действует ссылка из письма с приглашением
+const invitationTTL = 7 * 24 * time.Hour
+
+type Repository interface {
+	GetStoreByID(ctx context.Context, storeID uint) (entity.Store, error)
+	// GetMemberRole возвращает роль пользователя в магазине, пустую строку если он не сотрудник
+	GetMemberRole(ctx context.Context, shopID, userID uint) (string, error)
+	SelectMembers(ctx context.Context, shopID uint) ([]entity.StoreMember, error)
+	// DeleteMember убирает сотрудника и отправляет уведомление одной транзакцией
+	DeleteMember(ctx context.Context, shopID, userID uint, notice Notice) error
+	// InsertInvitation заменяет неиспользованное приглашение на тот же адрес новым
+	InsertInvitation(ctx context.Context, invitation Invitation) (entity.StoreInvitation, error)
+	// SelectInvitations возвращает неиспользованные приглашения, срок которых не истек
+	SelectInvitations(ctx context.Context, shopID uint) ([]entity.StoreInvitation, error)
+	DeleteInvitation(ctx context.Context, shopID, invitationID uint) error
+	GetInvitationByToken(ctx context.Context, tokenHash string) (entity.StoreInvitation, error)
+	// AcceptInvitation добавляет пользователя в сотрудники по неиспользованному приглашению
+	// и отправляет уведомление одной транзакцией
+	AcceptInvitation(ctx context.Context, invitationID, userID uint, notice Notice) (entity.StoreMember, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, message mailer.Message) error
+}
+
+type staffService struct {
+	staffRepository Repository
+	mailer          Mailer
+	appURL          string
+}
+
+// NewStaffService создает сервис сотрудников магазина. appURL — адрес клиентского
+// приложения, на котором открывается ссылка из приглашения.
+func NewStaffService(staffRepository Repository, mailer Mailer, appURL string) *staffService {
+	return &staffService{
+		staffRepository: staffRepository,
+		mailer:          mailer,
+		appURL:          strings.TrimRight(appURL, "/"),
+	}
+}
+
+// GetMembers возвращает сотрудников магазина любому из них
+func (s *staffService) GetMembers(ctx context.Context, userID, storeID uint) ([]entity.StoreMember, error) {
+	if _, err := s.checkMember(ctx, userID, storeID); err != nil {
+		return nil, err
+	}
+
+	return s.staffRepository.SelectMembers(ctx, storeID)
+}
+
+// InviteMember отправляет приглашение на почту. Повторное приглашение на тот же адрес
+// заменяет прежнее, старая ссылка перестает действовать.
+func (s *staffService) InviteMember(
+	ctx context.Context,
+	userID, storeID uint,
+	email, role string,
+) (entity.StoreInvitation, error) {
+	if role != entity.StoreRoleManager && role != entity.StoreRoleCashier {
+		return entity.StoreInvitation{}, badRequest("role must be manager or cashier")
+	}
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil || address.Name != "" {
+		return entity.StoreInvitation{}, badRequest("invalid email")
+	}
+	email = strings.ToLower(address.Address)
+
+	store, err := s.checkPermission(ctx, userID, storeID, entity.PermissionManageStore)
+	if err != nil {
+		return entity.StoreInvitation{}, err
+	}
+
+	token, err := security.NewToken()
+	if err != nil {
+		return entity.StoreInvitation{}, &apperror.StoreError{
+			Code:    apperror.InternalError,
+			Message: "failed to create invitation",
+			Err:     err,
+		}
+	}
+
+	invitation, err := s.staffRepository.InsertInvitation(ctx, Invitation{
+		StoreID:   storeID,
+		Email:     email,
+		Role:      role,
+		TokenHash: security.HashToken(token),
+		InvitedBy: userID,
+		ExpiresAt: time.Now().Add(invitationTTL),
+	})
+	if err != nil {
+		return entity.StoreInvitation{}, err
+	}
+
+	if err := s.mailer.Send(ctx, s.invitationMail(store, invitation, token)); err != nil {
+		// приглашение без письма принять нельзя, поэтому оно удаляется
+		if deleteErr := s.staffRepository.DeleteInvitation(ctx, storeID, invitation.ID); deleteErr != nil {
+			err = fmt.Errorf("%w; failed to remove invitation: %v", err, deleteErr)
+		}
+		return entity.StoreInvitation{}, &apperror.StoreError{
+			Code:    apperror.InternalError,
+			Message: "failed to send invitation",
+			Err:     err,
+		}
+	}
+
+	return invitation, nil
+}
+
+func (s *staffService) GetInvitations(
+	ctx context.Context,
+	userID, storeID uint,
+) ([]entity.StoreInvitation, error) {
+	if _, err := s.checkPermission(ctx, userID, storeID, entity.PermissionManageStore); err != nil {
+		return nil, err
+	}
+
+	return s.staffRepository.SelectInvitations(ctx, storeID)
+}
+
+func (s *staffService) RevokeInvitation(ctx context.Context, userID, storeID, invitationID uint) error {
+	if _, err := s.checkPermission(ctx, userID, storeID, entity.PermissionManageStore); err != nil {
+		return err
+	}
+
+	return s.staffRepository.DeleteInvitation(ctx, storeID, invitationID)
+}
+
+// AcceptInvitation принимает приглашение по токену из письма. Принять его может только
+// пользователь с той почтой, на которую оно отправлено.
+func (s *staffService) AcceptInvitation(
+	ctx context.Context,
+	user entity.User,
+	token string,
+) (entity.StoreMember, error) {
+	invitation, err := s.staffRepository.GetInvitationByToken(ctx, security.HashToken(token))
+	if err != nil {
+		return entity.StoreMember{}, err
+	}
+	if invitation.AcceptedAt != nil || !invitation.ExpiresAt.After(time.Now()) {
+		return entity.StoreMember{}, apperror.ErrStoreInvitationNotFound
+	}
+	if !strings.EqualFold(invitation.Email, user.Email) {
+		return entity.StoreMember{}, &apperror.StoreError{
+			Code:    apperror.Forbidden,
+			Message: "invitation was sent to another email",
+		}
+	}
+
+	store, err := s.staffRepository.GetStoreByID(ctx, invitation.StoreID)
+	if err != nil {
+		return entity.StoreMember{}, err
+	}
+
+	return s.staffRepository.AcceptInvitation(ctx, invitation.ID, user.ID, Notice{
+		UserID:  store.UserID,
+		Message: fmt.Sprintf("%s joined store %q as %s", user.Name, store.Name, invitation.Role),
+	})
+}
+
+// RemoveMember убирает сотрудника из магазина. Сотрудник может уйти сам,
+// убрать другого может только владелец. Владельца убрать нельзя.
+func (s *staffService) RemoveMember(ctx context.Context, userID, storeID, memberID uint) error {
+	var (
+		store entity.Store
+		err   error
+	)
+	if userID == memberID {
+		store, err = s.checkMember(ctx, userID, storeID)
+	} else {
+		store, err = s.checkPermission(ctx, userID, storeID, entity.PermissionManageStore)
+	}
+	if err != nil {
+		return err
+	}
+
+	role, err := s.staffRepository.GetMemberRole(ctx, storeID, memberID)
+	if err != nil {
+		return err
+	}
+	switch role {
+	case "":
+		return apperror.ErrStoreMemberNotFound
+	case entity.StoreRoleOwner:
+		return &apperror.StoreError{
+			Code:    apperror.Conflict,
+			Message: "the store owner cannot be removed",
+		}
+	}
+
+	notice := Notice{
+		UserID:  memberID,
+		Message: fmt.Sprintf("You have been removed from store %q", store.Name),
+	}
+	if userID == memberID {
+		notice = Notice{
+			UserID:  store.UserID,
+			Message: fmt.Sprintf("A %s has left store %q", role, store.Name),
+		}
+	}
+
+	return s.staffRepository.DeleteMember(ctx, storeID, memberID, notice)
+}
+
+// checkMember проверяет, что пользователь — сотрудник магазина, и возвращает магазин
+func (s *staffService) checkMember(ctx context.Context, userID, storeID uint) (entity.Store, error) {
+	store, err := s.staffRepository.GetStoreByID(ctx, storeID)
+	if err != nil {
+		return entity.Store{}, err
+	}
+
+	role, err := s.staffRepository.GetMemberRole(ctx, storeID, userID)
+	if err != nil {
+		return entity.Store{}, err
+	}
+	if role == "" {
+		return entity.Store{}, apperror.ErrStoreForbidden
+	}
+
+	return store, nil
+}
+
+// checkPermission проверяет, что роль пользователя в магазине дает право на действие
+func (s *staffService) checkPermission(
+	ctx context.Context,
+	userID, storeID uint,
+	permission string,
+) (entity.Store, error) {
+	store, err := s.staffRepository.GetStoreByID(ctx, storeID)
+	if err != nil {
+		return entity.Store{}, err
+	}
+
+	role, err := s.staffRepository.GetMemberRole(ctx, storeID, userID)
+	if err != nil {
+		return entity.Store{}, err
+	}
+	if !entity.RoleAllows(role, permission) {
+		return entity.Store{}, apperror.ErrStoreForbidden
+	}
+
+	return store, nil
+}
+
+func (s *staffService) invitationMail(
+	store entity.Store,
+	invitation entity.StoreInvitation,
+	token string,
+) mailer.Message {
+	link := s.appURL + "/invitations/accept?token=" + url.QueryEscape(token)
+
+	return mailer.Message{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("Invitation to join %s", store.Name),
+		Body: fmt.Sprintf(
+			"You have been invited to join store %q as %s.\n\n"+
+				"Sign in with this email and open the link to accept:\n%s\n\n"+
+				"The invitation expires on %s.",
+			store.Name, invitation.Role, link, invitation.ExpiresAt.UTC().Format(time.RFC1123),
+		),
+	}
+}
+
+func badRequest(message string) error {
+	return &apperror.StoreError{
+		Code:    apperror.BadRequest,
+		Message: message,
+	}
+}
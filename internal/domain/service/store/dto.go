@@ -1,15 +1,80 @@
 package store
 
-import (
-	"time"
-)
-
-// Store здесь еще надо думать тому, кто возьмется Store реализовывать
+// Store новый магазин пользователя
 type Store struct {
-	ID          uint   `json:"id" gorm:"primaryKey"`
+	UserID      uint   `json:"user_id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
-	// Products    []product.Product `json:"products,omitempty" gorm:"foreignKey:StoreID"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UpdateStore изменение магазина, незаданные поля не меняются
+type UpdateStore struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// Filter отбор магазинов: UserID оставляет магазины одного владельца,
+// MemberID — магазины, в которых пользователь работает в любой роли,
+// Status — магазины на одном этапе проверки
+type Filter struct {
+	UserID   *uint   `json:"user_id"`
+	MemberID *uint   `json:"member_id"`
+	Status   *string `json:"status"`
+}
+
+// ShopPoint новая точка продаж. Координаты задаются вместе, пустой часовой пояс означает UTC.
+type ShopPoint struct {
+	StoreID   uint     `json:"store_id"`
+	Address   string   `json:"address"`
+	Phone     string   `json:"phone"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	Timezone  string   `json:"timezone"`
+}
+
+// UpdateShopPoint изменение точки продаж, незаданные поля не меняются.
+// Координаты меняются только вместе.
+type UpdateShopPoint struct {
+	Address   *string  `json:"address"`
+	Phone     *string  `json:"phone"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+// LegalDetails юридические данные заявки на проверку магазина
+type LegalDetails struct {
+	LegalName    string `json:"legal_name"`
+	TaxID        string `json:"tax_id"`
+	LegalAddress string `json:"legal_address"`
+}
+
+// Document загруженный и проверенный документ заявки
+type Document struct {
+	StoreID     uint   `json:"store_id"`
+	Kind        string `json:"kind"`
+	Key         string `json:"key"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// Review решение администратора по заявке. Comment обязателен при отказе и приостановке.
+type Review struct {
+	Status  string `json:"status"`
+	Comment string `json:"comment"`
+}
+
+// VerificationChange переход заявки на новый этап и уведомление, которое получит владелец
+type VerificationChange struct {
+	Status  string `json:"status"`
+	Comment string `json:"comment"`
+	Message string `json:"message"`
+}
+
+// Settings правила торга магазина, задаются целиком
+type Settings struct {
+	OfferTTLMinutes int      `json:"offer_ttl_minutes"`
+	MaxRounds       *int     `json:"max_rounds"`
+	MaxDiscount     *float64 `json:"max_discount"`
+	AcceptsOffers   bool     `json:"accepts_offers"`
+	Currency        string   `json:"currency"`
 }
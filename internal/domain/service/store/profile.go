@@ -0,0 +1,86 @@
+package store
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+// statsRefreshInterval как часто пересчитывается статистика магазинов в профилях
+const statsRefreshInterval = 10 * time.Minute
+
+// GetStoreProfile возвращает публичный профиль проверенного магазина: точки продаж
+// с расписанием, число товаров и статистику по предложениям на момент последнего пересчета.
+func (s *storeService) GetStoreProfile(ctx context.Context, storeID uint) (entity.StoreProfile, error) {
+	store, err := s.visibleStore(ctx, storeID)
+	if err != nil {
+		return entity.StoreProfile{}, err
+	}
+
+	points, err := s.storeRepository.SelectShopPoints(ctx, storeID)
+	if err != nil {
+		return entity.StoreProfile{}, err
+	}
+
+	shopPointIDs := make([]uint, 0, len(points))
+	for _, point := range points {
+		shopPointIDs = append(shopPointIDs, point.ID)
+	}
+
+	schedules, err := s.scheduleProvider.SelectPointSchedules(ctx, shopPointIDs)
+	if err != nil {
+		return entity.StoreProfile{}, err
+	}
+
+	productCount, err := s.storeRepository.CountStoreProducts(ctx, storeID)
+	if err != nil {
+		return entity.StoreProfile{}, err
+	}
+
+	stats, err := s.storeRepository.GetStoreStats(ctx, storeID)
+	if err != nil {
+		return entity.StoreProfile{}, err
+	}
+
+	now := time.Now()
+	profilePoints := make([]entity.ProfilePoint, 0, len(points))
+	for _, point := range points {
+		schedule := schedules[point.ID]
+		point.OpeningStatus = schedule.Status(now)
+		profilePoints = append(profilePoints, entity.ProfilePoint{
+			ShopPoint:  point,
+			Hours:      schedule.Hours,
+			Exceptions: schedule.Exceptions,
+		})
+	}
+
+	return entity.StoreProfile{
+		ID:           store.ID,
+		Name:         store.Name,
+		Description:  store.Description,
+		Points:       profilePoints,
+		ProductCount: productCount,
+		Stats:        stats,
+		CreatedAt:    store.CreatedAt,
+	}, nil
+}
+
+// Run пересчитывает статистику магазинов, пока не отменен контекст.
+func (s *storeService) Run(ctx context.Context) {
+	ticker := time.NewTicker(statsRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.storeRepository.RefreshStoreStats(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("failed to refresh store statistics: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+// Границы срока действия предложения в настройках магазина
+const (
+	minOfferTTLMinutes = 15
+	maxOfferTTLMinutes = 30 * 24 * 60
+)
+
+// currencyCode код валюты по ISO 4217
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// GetStoreSettings отдает правила торга магазина. Минимальную цену видят только те,
+// кто может менять настройки, поэтому покупателям настройки не отдаются.
+func (s *storeService) GetStoreSettings(ctx context.Context, userID, storeID uint) (entity.StoreSettings, error) {
+	if _, err := s.checkOwner(ctx, userID, storeID); err != nil {
+		return entity.StoreSettings{}, err
+	}
+
+	return s.storeRepository.GetStoreSettings(ctx, storeID)
+}
+
+// UpdateStoreSettings заменяет правила торга магазина. Новые правила действуют
+// на предложения, созданные и обработанные после изменения.
+func (s *storeService) UpdateStoreSettings(
+	ctx context.Context,
+	userID, storeID uint,
+	settings Settings,
+) (entity.StoreSettings, error) {
+	settings.Currency = strings.ToUpper(strings.TrimSpace(settings.Currency))
+	if err := validateSettings(settings); err != nil {
+		return entity.StoreSettings{}, err
+	}
+
+	if _, err := s.checkOwner(ctx, userID, storeID); err != nil {
+		return entity.StoreSettings{}, err
+	}
+
+	return s.storeRepository.UpdateStoreSettings(ctx, storeID, settings)
+}
+
+func validateSettings(settings Settings) error {
+	if settings.OfferTTLMinutes < minOfferTTLMinutes || settings.OfferTTLMinutes > maxOfferTTLMinutes {
+		return badRequest("offer_ttl_minutes must be between 15 minutes and 30 days")
+	}
+	if settings.MaxRounds != nil && *settings.MaxRounds < 1 {
+		return badRequest("max_rounds must be positive")
+	}
+	if settings.MaxDiscount != nil && (*settings.MaxDiscount <= 0 || *settings.MaxDiscount >= 1) {
+		return badRequest("max_discount must be between 0 and 1")
+	}
+	if !currencyCode.MatchString(settings.Currency) {
+		return badRequest("currency must be a three-letter ISO 4217 code")
+	}
+
+	return nil
+}
@@ -1 +1,363 @@
 package store
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/pkg/geo"
+	"github.com/zuzaaa-dev/stawberry/pkg/objectstorage"
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
+)
+
+// Ограничения длины полей магазина и точки продаж. Имя и телефон ограничены размером колонок.
+const (
+	maxNameLength        = 255
+	maxDescriptionLength = 2000
+	maxAddressLength     = 500
+	maxPhoneLength       = 30
+)
+
+type Repository interface {
+	InsertStore(ctx context.Context, store Store) (entity.Store, error)
+	GetStoreByID(ctx context.Context, storeID uint) (entity.Store, error)
+	// GetMemberRole возвращает роль пользователя в магазине, пустую строку если он не сотрудник
+	GetMemberRole(ctx context.Context, shopID, userID uint) (string, error)
+	SelectStores(ctx context.Context, filter Filter, params pagination.Params) ([]entity.Store, pagination.Page, error)
+	UpdateStore(ctx context.Context, storeID uint, update UpdateStore) (entity.Store, error)
+	SelectShopPoints(ctx context.Context, storeID uint) ([]entity.ShopPoint, error)
+	GetShopPoint(ctx context.Context, shopPointID uint) (entity.ShopPoint, error)
+	InsertShopPoint(ctx context.Context, point ShopPoint) (entity.ShopPoint, error)
+	UpdateShopPoint(ctx context.Context, shopPointID uint, update UpdateShopPoint) (entity.ShopPoint, error)
+	// DeleteShopPoint удаляет точку продаж, если на ней не осталось товара
+	DeleteShopPoint(ctx context.Context, shopPointID uint) error
+	// CountStoreProducts возвращает число неархивных товаров в ассортименте магазина
+	CountStoreProducts(ctx context.Context, storeID uint) (int, error)
+	// GetStoreStats возвращает последнюю посчитанную статистику магазина
+	GetStoreStats(ctx context.Context, storeID uint) (entity.StoreStats, error)
+	// RefreshStoreStats пересчитывает статистику всех магазинов по предложениям
+	RefreshStoreStats(ctx context.Context) error
+	GetStoreSettings(ctx context.Context, storeID uint) (entity.StoreSettings, error)
+	UpdateStoreSettings(ctx context.Context, storeID uint, settings Settings) (entity.StoreSettings, error)
+	GetVerification(ctx context.Context, storeID uint) (entity.StoreVerification, error)
+	// UpdateLegalDetails меняет юридические данные, пока заявку можно редактировать
+	UpdateLegalDetails(ctx context.Context, storeID uint, details LegalDetails) error
+	InsertDocument(ctx context.Context, document Document) (entity.StoreDocument, error)
+	GetDocument(ctx context.Context, storeID, documentID uint) (entity.StoreDocument, error)
+	DeleteDocument(ctx context.Context, storeID, documentID uint) error
+	// ChangeVerification переводит заявку на новый этап, если она находится на одном из этапов from,
+	// и уведомляет владельца магазина
+	ChangeVerification(
+		ctx context.Context,
+		storeID uint,
+		from []string,
+		change VerificationChange,
+	) (entity.StoreVerification, error)
+}
+
+type ScheduleProvider interface {
+	SelectPointSchedules(ctx context.Context, shopPointIDs []uint) (map[uint]entity.Schedule, error)
+}
+
+type ObjectStorage interface {
+	PresignPutObject(
+		ctx context.Context,
+		objectKey string,
+		contentType string,
+		contentLength int64,
+		ttl time.Duration,
+	) (objectstorage.PresignedRequest, error)
+	PresignGetObject(ctx context.Context, objectKey string, ttl time.Duration) (objectstorage.PresignedRequest, error)
+	HeadObject(ctx context.Context, objectKey string) (objectstorage.ObjectInfo, error)
+	GetObject(ctx context.Context, objectKey string) (io.ReadCloser, objectstorage.ObjectInfo, error)
+	DeleteObject(ctx context.Context, objectKey string) error
+}
+
+type storeService struct {
+	storeRepository  Repository
+	scheduleProvider ScheduleProvider
+	storage          ObjectStorage
+	documentMaxSize  int64
+	documentTTL      time.Duration
+}
+
+// NewStoreService создает сервис магазинов. documentTTL задает срок жизни
+// подписанных ссылок на загрузку и чтение документов заявки.
+func NewStoreService(
+	storeRepository Repository,
+	scheduleProvider ScheduleProvider,
+	storage ObjectStorage,
+	documentMaxSize int64,
+	documentTTL time.Duration,
+) *storeService {
+	return &storeService{
+		storeRepository:  storeRepository,
+		scheduleProvider: scheduleProvider,
+		storage:          storage,
+		documentMaxSize:  documentMaxSize,
+		documentTTL:      documentTTL,
+	}
+}
+
+// CreateStore создает магазин. Магазины заводят только пользователи с аккаунтом магазина
+// и подтвержденной почтой.
+func (s *storeService) CreateStore(ctx context.Context, user entity.User, store Store) (entity.Store, error) {
+	if !user.IsStore {
+		return entity.Store{}, apperror.ErrStoreAccountRequired
+	}
+	if user.EmailVerifiedAt == nil {
+		return entity.Store{}, apperror.ErrStoreEmailNotVerified
+	}
+
+	store.Name = strings.TrimSpace(store.Name)
+	if err := validateStore(store.Name, store.Description); err != nil {
+		return entity.Store{}, err
+	}
+
+	store.UserID = user.ID
+	return s.storeRepository.InsertStore(ctx, store)
+}
+
+// GetStore возвращает проверенный магазин вместе с его точками продаж
+func (s *storeService) GetStore(ctx context.Context, storeID uint) (entity.Store, error) {
+	store, err := s.visibleStore(ctx, storeID)
+	if err != nil {
+		return entity.Store{}, err
+	}
+
+	if store.Points, err = s.shopPoints(ctx, storeID); err != nil {
+		return entity.Store{}, err
+	}
+
+	return store, nil
+}
+
+// GetStores возвращает проверенные магазины каталога
+func (s *storeService) GetStores(
+	ctx context.Context,
+	filter Filter,
+	params pagination.Params,
+) ([]entity.Store, pagination.Page, error) {
+	verified := entity.VerificationVerified
+	filter.Status = &verified
+
+	return s.storeRepository.SelectStores(ctx, filter, params)
+}
+
+// GetUserStores возвращает магазины, в которых пользователь владелец или сотрудник,
+// на любом этапе проверки
+func (s *storeService) GetUserStores(
+	ctx context.Context,
+	userID uint,
+	params pagination.Params,
+) ([]entity.Store, pagination.Page, error) {
+	return s.storeRepository.SelectStores(ctx, Filter{MemberID: &userID}, params)
+}
+
+func (s *storeService) UpdateStore(
+	ctx context.Context,
+	userID, storeID uint,
+	update UpdateStore,
+) (entity.Store, error) {
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		update.Name = &name
+	}
+
+	current, err := s.checkOwner(ctx, userID, storeID)
+	if err != nil {
+		return entity.Store{}, err
+	}
+
+	name, description := current.Name, current.Description
+	if update.Name != nil {
+		name = *update.Name
+	}
+	if update.Description != nil {
+		description = *update.Description
+	}
+	if err := validateStore(name, description); err != nil {
+		return entity.Store{}, err
+	}
+
+	return s.storeRepository.UpdateStore(ctx, storeID, update)
+}
+
+// GetShopPoints возвращает точки продаж проверенного магазина с состоянием работы
+func (s *storeService) GetShopPoints(ctx context.Context, storeID uint) ([]entity.ShopPoint, error) {
+	if _, err := s.visibleStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+
+	return s.shopPoints(ctx, storeID)
+}
+
+func (s *storeService) CreateShopPoint(ctx context.Context, userID uint, point ShopPoint) (entity.ShopPoint, error) {
+	point.Address = strings.TrimSpace(point.Address)
+	point.Phone = strings.TrimSpace(point.Phone)
+	if point.Timezone == "" {
+		point.Timezone = "UTC"
+	}
+	if err := validateShopPoint(point.Address, point.Phone, point.Latitude, point.Longitude); err != nil {
+		return entity.ShopPoint{}, err
+	}
+	if _, err := time.LoadLocation(point.Timezone); err != nil || point.Timezone == "Local" {
+		return entity.ShopPoint{}, badRequest("unknown timezone " + point.Timezone)
+	}
+
+	if _, err := s.checkOwner(ctx, userID, point.StoreID); err != nil {
+		return entity.ShopPoint{}, err
+	}
+
+	return s.storeRepository.InsertShopPoint(ctx, point)
+}
+
+func (s *storeService) UpdateShopPoint(
+	ctx context.Context,
+	userID, shopPointID uint,
+	update UpdateShopPoint,
+) (entity.ShopPoint, error) {
+	if update.Address != nil {
+		address := strings.TrimSpace(*update.Address)
+		update.Address = &address
+	}
+	if update.Phone != nil {
+		phone := strings.TrimSpace(*update.Phone)
+		update.Phone = &phone
+	}
+
+	current, err := s.storeRepository.GetShopPoint(ctx, shopPointID)
+	if err != nil {
+		return entity.ShopPoint{}, err
+	}
+	if _, err := s.checkOwner(ctx, userID, current.ShopID); err != nil {
+		return entity.ShopPoint{}, err
+	}
+
+	address, phone := current.Address, current.Phone
+	if update.Address != nil {
+		address = *update.Address
+	}
+	if update.Phone != nil {
+		phone = *update.Phone
+	}
+	if err := validateShopPoint(address, phone, update.Latitude, update.Longitude); err != nil {
+		return entity.ShopPoint{}, err
+	}
+
+	return s.storeRepository.UpdateShopPoint(ctx, shopPointID, update)
+}
+
+func (s *storeService) DeleteShopPoint(ctx context.Context, userID, shopPointID uint) error {
+	point, err := s.storeRepository.GetShopPoint(ctx, shopPointID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.checkOwner(ctx, userID, point.ShopID); err != nil {
+		return err
+	}
+
+	return s.storeRepository.DeleteShopPoint(ctx, shopPointID)
+}
+
+// shopPoints получает точки продаж магазина и выставляет им состояние работы
+func (s *storeService) shopPoints(ctx context.Context, storeID uint) ([]entity.ShopPoint, error) {
+	points, err := s.storeRepository.SelectShopPoints(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	shopPointIDs := make([]uint, 0, len(points))
+	for _, point := range points {
+		shopPointIDs = append(shopPointIDs, point.ID)
+	}
+
+	schedules, err := s.scheduleProvider.SelectPointSchedules(ctx, shopPointIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range points {
+		points[i].OpeningStatus = schedules[points[i].ID].Status(now)
+	}
+
+	return points, nil
+}
+
+// visibleStore возвращает магазин, если он виден в каталоге.
+// Непроверенный магазин для покупателей не существует.
+func (s *storeService) visibleStore(ctx context.Context, storeID uint) (entity.Store, error) {
+	store, err := s.storeRepository.GetStoreByID(ctx, storeID)
+	if err != nil {
+		return entity.Store{}, err
+	}
+	if store.VerificationStatus != entity.VerificationVerified {
+		return entity.Store{}, apperror.ErrStoreNotFound
+	}
+
+	return store, nil
+}
+
+// checkOwner проверяет, что роль пользователя в магазине дает право управлять им, и возвращает магазин
+func (s *storeService) checkOwner(ctx context.Context, userID, storeID uint) (entity.Store, error) {
+	store, err := s.storeRepository.GetStoreByID(ctx, storeID)
+	if err != nil {
+		return entity.Store{}, err
+	}
+
+	role, err := s.storeRepository.GetMemberRole(ctx, storeID, userID)
+	if err != nil {
+		return entity.Store{}, err
+	}
+	if !entity.RoleAllows(role, entity.PermissionManageStore) {
+		return entity.Store{}, apperror.ErrStoreForbidden
+	}
+
+	return store, nil
+}
+
+func validateStore(name, description string) error {
+	if name == "" {
+		return badRequest("name is required")
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return badRequest("name is too long")
+	}
+	if utf8.RuneCountInString(description) > maxDescriptionLength {
+		return badRequest("description is too long")
+	}
+
+	return nil
+}
+
+func validateShopPoint(address, phone string, latitude, longitude *float64) error {
+	if address == "" || phone == "" {
+		return badRequest("address and phone are required")
+	}
+	if utf8.RuneCountInString(address) > maxAddressLength {
+		return badRequest("address is too long")
+	}
+	if utf8.RuneCountInString(phone) > maxPhoneLength {
+		return badRequest("phone is too long")
+	}
+	if (latitude == nil) != (longitude == nil) {
+		return badRequest("latitude and longitude must be set together")
+	}
+	if latitude != nil && !(geo.Point{Lat: *latitude, Lon: *longitude}).Valid() {
+		return badRequest("latitude must be between -90 and 90, longitude between -180 and 180")
+	}
+
+	return nil
+}
+
+func badRequest(message string) error {
+	return &apperror.StoreError{
+		Code:    apperror.BadRequest,
+		Message: message,
+	}
+}
@@ -0,0 +1,479 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/pkg/objectstorage"
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
+)
+
+const (
+	maxStoreDocuments = 10
+	// sniffLen столько байт читает http.DetectContentType
+	sniffLen = 512
+)
+
+// documentKinds виды документов заявки
+var documentKinds = map[string]bool{
+	"registration_certificate": true,
+	"tax_certificate":          true,
+	"identity_document":        true,
+	"other":                    true,
+}
+
+// documentTypes поддерживаемые форматы документов и расширения для их ключей
+var documentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// reviewTransitions этапы, с которых администратор может перевести заявку на этап-ключ
+var reviewTransitions = map[string][]string{
+	entity.VerificationVerified:  {entity.VerificationSubmitted, entity.VerificationSuspended},
+	entity.VerificationRejected:  {entity.VerificationSubmitted},
+	entity.VerificationSuspended: {entity.VerificationVerified},
+}
+
+// editableStatuses этапы, на которых владелец может менять заявку и отправлять ее
+var editableStatuses = []string{entity.VerificationDraft, entity.VerificationRejected}
+
+var errNotEditable = &apperror.StoreError{
+	Code:    apperror.Conflict,
+	Message: "verification request can only be changed in draft or after rejection",
+}
+
+var errTooManyDocuments = &apperror.StoreError{
+	Code:    apperror.BadRequest,
+	Message: fmt.Sprintf("verification request may have at most %d documents", maxStoreDocuments),
+}
+
+var errUnsupportedDocument = &apperror.StoreError{
+	Code:    apperror.UnsupportedMedia,
+	Message: "only PDF, JPEG and PNG documents are supported",
+}
+
+// GetVerification возвращает заявку на проверку магазина владельцу или администратору
+func (s *storeService) GetVerification(
+	ctx context.Context,
+	user entity.User,
+	storeID uint,
+) (entity.StoreVerification, error) {
+	if !user.IsAdmin {
+		if _, err := s.checkOwner(ctx, user.ID, storeID); err != nil {
+			return entity.StoreVerification{}, err
+		}
+	}
+
+	return s.verification(ctx, storeID)
+}
+
+// GetStoresForReview возвращает магазины на одном этапе проверки для администратора
+func (s *storeService) GetStoresForReview(
+	ctx context.Context,
+	status string,
+	params pagination.Params,
+) ([]entity.Store, pagination.Page, error) {
+	switch status {
+	case entity.VerificationDraft, entity.VerificationSubmitted, entity.VerificationVerified,
+		entity.VerificationRejected, entity.VerificationSuspended:
+	default:
+		return nil, pagination.Page{}, badRequest("unknown verification status " + status)
+	}
+
+	return s.storeRepository.SelectStores(ctx, Filter{Status: &status}, params)
+}
+
+// UpdateLegalDetails задает юридические данные заявки
+func (s *storeService) UpdateLegalDetails(
+	ctx context.Context,
+	userID, storeID uint,
+	details LegalDetails,
+) (entity.StoreVerification, error) {
+	details.LegalName = strings.TrimSpace(details.LegalName)
+	details.TaxID = strings.TrimSpace(details.TaxID)
+	details.LegalAddress = strings.TrimSpace(details.LegalAddress)
+	if err := validateLegalDetails(details); err != nil {
+		return entity.StoreVerification{}, err
+	}
+
+	if _, err := s.checkEditable(ctx, userID, storeID); err != nil {
+		return entity.StoreVerification{}, err
+	}
+
+	if err := s.storeRepository.UpdateLegalDetails(ctx, storeID, details); err != nil {
+		return entity.StoreVerification{}, err
+	}
+
+	return s.verification(ctx, storeID)
+}
+
+// CreateDocumentUpload выдает подписанную ссылку для загрузки документа заявки
+// напрямую в хранилище. Документ попадает в заявку только после AddDocument.
+func (s *storeService) CreateDocumentUpload(
+	ctx context.Context,
+	userID, storeID uint,
+	contentType string,
+	size int64,
+) (entity.DocumentUpload, error) {
+	if err := s.checkDocumentSize(size); err != nil {
+		return entity.DocumentUpload{}, err
+	}
+	ext, ok := documentTypes[contentType]
+	if !ok {
+		return entity.DocumentUpload{}, errUnsupportedDocument
+	}
+
+	verification, err := s.checkEditable(ctx, userID, storeID)
+	if err != nil {
+		return entity.DocumentUpload{}, err
+	}
+	if len(verification.Documents) >= maxStoreDocuments {
+		return entity.DocumentUpload{}, errTooManyDocuments
+	}
+
+	key := documentKeyPrefix(storeID) + uuid.NewString() + ext
+	presigned, err := s.storage.PresignPutObject(ctx, key, contentType, size, s.documentTTL)
+	if err != nil {
+		return entity.DocumentUpload{}, &apperror.StoreError{
+			Code:    apperror.InternalError,
+			Message: "failed to presign document upload",
+			Err:     err,
+		}
+	}
+
+	headers := make(map[string]string, len(presigned.Header))
+	for name := range presigned.Header {
+		headers[name] = presigned.Header.Get(name)
+	}
+
+	return entity.DocumentUpload{
+		Key:       key,
+		URL:       presigned.URL,
+		Method:    presigned.Method,
+		Headers:   headers,
+		ExpiresAt: presigned.ExpiresAt,
+	}, nil
+}
+
+// AddDocument проверяет загруженный владельцем объект через HeadObject и сигнатуру
+// первых байт файла и прикладывает его к заявке. Объект, не прошедший проверку, удаляется.
+func (s *storeService) AddDocument(
+	ctx context.Context,
+	userID, storeID uint,
+	kind, key string,
+) (entity.StoreDocument, error) {
+	if !documentKinds[kind] {
+		return entity.StoreDocument{}, badRequest(
+			"kind must be registration_certificate, tax_certificate, identity_document or other")
+	}
+	if !strings.HasPrefix(key, documentKeyPrefix(storeID)) || strings.Contains(key, "..") {
+		return entity.StoreDocument{}, badRequest("document key does not belong to this store")
+	}
+
+	verification, err := s.checkEditable(ctx, userID, storeID)
+	if err != nil {
+		return entity.StoreDocument{}, err
+	}
+	// приложенный документ не проверяется повторно, иначе при ошибке он был бы удален из хранилища
+	for _, document := range verification.Documents {
+		if document.Key == key {
+			return entity.StoreDocument{}, &apperror.StoreError{
+				Code:    apperror.Conflict,
+				Message: "document is already attached",
+			}
+		}
+	}
+
+	info, err := s.storage.HeadObject(ctx, key)
+	if err != nil {
+		if errors.Is(err, objectstorage.ErrObjectNotFound) {
+			return entity.StoreDocument{}, badRequest("document has not been uploaded")
+		}
+		return entity.StoreDocument{}, &apperror.StoreError{
+			Code:    apperror.InternalError,
+			Message: "failed to check uploaded document",
+			Err:     err,
+		}
+	}
+
+	if err := s.verifyDocument(ctx, info); err != nil {
+		s.removeDocument(ctx, key)
+		return entity.StoreDocument{}, err
+	}
+	if len(verification.Documents) >= maxStoreDocuments {
+		s.removeDocument(ctx, key)
+		return entity.StoreDocument{}, errTooManyDocuments
+	}
+
+	document, err := s.storeRepository.InsertDocument(ctx, Document{
+		StoreID:     storeID,
+		Kind:        kind,
+		Key:         key,
+		ContentType: info.ContentType,
+		Size:        info.Size,
+	})
+	if err != nil {
+		return entity.StoreDocument{}, err
+	}
+
+	return s.withDocumentURL(ctx, document)
+}
+
+// DeleteDocument убирает документ из заявки и удаляет его из хранилища
+func (s *storeService) DeleteDocument(ctx context.Context, userID, storeID, documentID uint) error {
+	if _, err := s.checkEditable(ctx, userID, storeID); err != nil {
+		return err
+	}
+
+	document, err := s.storeRepository.GetDocument(ctx, storeID, documentID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.storeRepository.DeleteDocument(ctx, storeID, documentID); err != nil {
+		return err
+	}
+
+	s.removeDocument(ctx, document.Key)
+	return nil
+}
+
+// SubmitVerification отправляет заявку администратору. В заявке должны быть
+// юридические данные и хотя бы один документ.
+func (s *storeService) SubmitVerification(
+	ctx context.Context,
+	userID, storeID uint,
+) (entity.StoreVerification, error) {
+	store, err := s.checkOwner(ctx, userID, storeID)
+	if err != nil {
+		return entity.StoreVerification{}, err
+	}
+
+	verification, err := s.storeRepository.GetVerification(ctx, storeID)
+	if err != nil {
+		return entity.StoreVerification{}, err
+	}
+	if !verification.Editable() {
+		return entity.StoreVerification{}, errNotEditable
+	}
+	if verification.LegalName == "" || verification.TaxID == "" || verification.LegalAddress == "" {
+		return entity.StoreVerification{}, badRequest("legal details are required")
+	}
+	if len(verification.Documents) == 0 {
+		return entity.StoreVerification{}, badRequest("at least one document is required")
+	}
+
+	return s.changeVerification(ctx, store, editableStatuses, entity.VerificationSubmitted, "")
+}
+
+// ReviewVerification записывает решение администратора: подтвердить или отклонить
+// отправленную заявку, приостановить проверенный магазин или вернуть приостановленный
+func (s *storeService) ReviewVerification(
+	ctx context.Context,
+	storeID uint,
+	review Review,
+) (entity.StoreVerification, error) {
+	from, ok := reviewTransitions[review.Status]
+	if !ok {
+		return entity.StoreVerification{}, badRequest("status must be verified, rejected or suspended")
+	}
+
+	review.Comment = strings.TrimSpace(review.Comment)
+	if review.Status != entity.VerificationVerified && review.Comment == "" {
+		return entity.StoreVerification{}, badRequest("comment is required to reject or suspend a store")
+	}
+	if utf8.RuneCountInString(review.Comment) > maxDescriptionLength {
+		return entity.StoreVerification{}, badRequest("comment is too long")
+	}
+
+	store, err := s.storeRepository.GetStoreByID(ctx, storeID)
+	if err != nil {
+		return entity.StoreVerification{}, err
+	}
+
+	return s.changeVerification(ctx, store, from, review.Status, review.Comment)
+}
+
+func (s *storeService) changeVerification(
+	ctx context.Context,
+	store entity.Store,
+	from []string,
+	status, comment string,
+) (entity.StoreVerification, error) {
+	verification, err := s.storeRepository.ChangeVerification(ctx, store.ID, from, VerificationChange{
+		Status:  status,
+		Comment: comment,
+		Message: verificationMessage(store.Name, status, comment),
+	})
+	if err != nil {
+		return entity.StoreVerification{}, err
+	}
+
+	return s.withDocumentURLs(ctx, verification)
+}
+
+// checkEditable проверяет, что магазин принадлежит пользователю и его заявку можно менять
+func (s *storeService) checkEditable(
+	ctx context.Context,
+	userID, storeID uint,
+) (entity.StoreVerification, error) {
+	if _, err := s.checkOwner(ctx, userID, storeID); err != nil {
+		return entity.StoreVerification{}, err
+	}
+
+	verification, err := s.storeRepository.GetVerification(ctx, storeID)
+	if err != nil {
+		return entity.StoreVerification{}, err
+	}
+	if !verification.Editable() {
+		return entity.StoreVerification{}, errNotEditable
+	}
+
+	return verification, nil
+}
+
+func (s *storeService) verification(ctx context.Context, storeID uint) (entity.StoreVerification, error) {
+	verification, err := s.storeRepository.GetVerification(ctx, storeID)
+	if err != nil {
+		return entity.StoreVerification{}, err
+	}
+
+	return s.withDocumentURLs(ctx, verification)
+}
+
+func (s *storeService) withDocumentURLs(
+	ctx context.Context,
+	verification entity.StoreVerification,
+) (entity.StoreVerification, error) {
+	for i, document := range verification.Documents {
+		withURL, err := s.withDocumentURL(ctx, document)
+		if err != nil {
+			return entity.StoreVerification{}, err
+		}
+		verification.Documents[i] = withURL
+	}
+
+	return verification, nil
+}
+
+// withDocumentURL выставляет документу временную ссылку на чтение,
+// документы не должны быть доступны по постоянному адресу
+func (s *storeService) withDocumentURL(
+	ctx context.Context,
+	document entity.StoreDocument,
+) (entity.StoreDocument, error) {
+	presigned, err := s.storage.PresignGetObject(ctx, document.Key, s.documentTTL)
+	if err != nil {
+		return entity.StoreDocument{}, &apperror.StoreError{
+			Code:    apperror.InternalError,
+			Message: "failed to presign document download",
+			Err:     err,
+		}
+	}
+
+	document.URL = presigned.URL
+	return document, nil
+}
+
+func (s *storeService) verifyDocument(ctx context.Context, info objectstorage.ObjectInfo) error {
+	if err := s.checkDocumentSize(info.Size); err != nil {
+		return err
+	}
+	if _, ok := documentTypes[info.ContentType]; !ok {
+		return errUnsupportedDocument
+	}
+
+	body, _, err := s.storage.GetObject(ctx, info.Key)
+	if err != nil {
+		return &apperror.StoreError{
+			Code:    apperror.InternalError,
+			Message: "failed to read uploaded document",
+			Err:     err,
+		}
+	}
+	defer body.Close()
+
+	head, err := io.ReadAll(io.LimitReader(body, sniffLen))
+	if err != nil {
+		return &apperror.StoreError{
+			Code:    apperror.InternalError,
+			Message: "failed to read uploaded document",
+			Err:     err,
+		}
+	}
+	if http.DetectContentType(head) != info.ContentType {
+		return errUnsupportedDocument
+	}
+
+	return nil
+}
+
+func (s *storeService) checkDocumentSize(size int64) error {
+	if size <= 0 || size > s.documentMaxSize {
+		return &apperror.StoreError{
+			Code:    apperror.TooLarge,
+			Message: fmt.Sprintf("document size must be between 1 and %d bytes", s.documentMaxSize),
+		}
+	}
+
+	return nil
+}
+
+func (s *storeService) removeDocument(ctx context.Context, key string) {
+	if err := s.storage.DeleteObject(ctx, key); err != nil {
+		log.Printf("failed to remove document object %s: %v", key, err)
+	}
+}
+
+func documentKeyPrefix(storeID uint) string {
+	return fmt.Sprintf("stores/%d/documents/", storeID)
+}
+
+func validateLegalDetails(details LegalDetails) error {
+	if details.LegalName == "" || details.LegalAddress == "" {
+		return badRequest("legal name and legal address are required")
+	}
+	if utf8.RuneCountInString(details.LegalName) > maxNameLength {
+		return badRequest("legal name is too long")
+	}
+	if utf8.RuneCountInString(details.LegalAddress) > maxAddressLength {
+		return badRequest("legal address is too long")
+	}
+	if len(details.TaxID) != 10 && len(details.TaxID) != 12 {
+		return badRequest("tax id must have 10 or 12 digits")
+	}
+	for _, r := range details.TaxID {
+		if r < '0' || r > '9' {
+			return badRequest("tax id must have 10 or 12 digits")
+		}
+	}
+
+	return nil
+}
+
+// verificationMessage уведомление владельца о новом этапе проверки магазина
+func verificationMessage(storeName, status, comment string) string {
+	switch status {
+	case entity.VerificationSubmitted:
+		return fmt.Sprintf("Store %q has been submitted for verification", storeName)
+	case entity.VerificationVerified:
+		return fmt.Sprintf("Store %q has been verified and is now visible in the catalog", storeName)
+	case entity.VerificationRejected:
+		return fmt.Sprintf("Verification of store %q was rejected: %s", storeName, comment)
+	case entity.VerificationSuspended:
+		return fmt.Sprintf("Store %q has been suspended: %s", storeName, comment)
+	}
+
+	return fmt.Sprintf("Verification status of store %q changed to %s", storeName, status)
+}
@@ -1,5 +1,7 @@
 package user
 
+import "time"
+
 type User struct {
 	Name     string `json:"name"`
 	Password string `json:"password"`
@@ -8,8 +10,20 @@ type User struct {
 	IsStore  bool   `json:"is_store"`
 }
 
+// UpdateUser изменение профиля, незаданные поля не меняются. Для смены почты
+// и пароля нужен текущий пароль CurrentPassword.
 type UpdateUser struct {
-	Name     string `json:"name,omitempty"`
-	Password string `json:"password,omitempty"`
-	Email    string `json:"email,omitempty"`
+	Name            *string `json:"name"`
+	Phone           *string `json:"phone"`
+	Email           *string `json:"email"`
+	Password        *string `json:"password"`
+	CurrentPassword string  `json:"-"`
+}
+
+// EmailVerification ссылка подтверждения почты с хешем токена из письма
+type EmailVerification struct {
+	UserID    uint      `json:"user_id"`
+	Email     string    `json:"email"`
+	TokenHash string    `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
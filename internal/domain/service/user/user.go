@@ -4,20 +4,48 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
 	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/pkg/mailer"
 	"github.com/zuzaaa-dev/stawberry/pkg/security"
 )
 
 const maxUsers = 5
 
+// Ограничения длины полей профиля по размеру колонок
+const (
+	maxNameLength  = 255
+	maxPhoneLength = 20
+	maxEmailLength = 255
+)
+
+const (
+	// verificationTTL сколько действует ссылка из письма с подтверждением почты
+	verificationTTL = 24 * time.Hour
+	// verificationInterval как часто можно запрашивать письмо с подтверждением повторно
+	verificationInterval = time.Minute
+)
+
 type Repository interface {
 	InsertUser(ctx context.Context, user User) (uint, error)
 	GetUser(ctx context.Context, email string) (entity.User, error)
 	GetUserByID(ctx context.Context, id uint) (entity.User, error)
-	UpdateUser(ctx context.Context, user User) error
+	// UpdateUser меняет заданные поля профиля, смена почты сбрасывает ее подтверждение
+	UpdateUser(ctx context.Context, id uint, update UpdateUser) (entity.User, error)
+	// InsertEmailVerification заменяет неиспользованные ссылки пользователя новой
+	InsertEmailVerification(ctx context.Context, verification EmailVerification) (entity.EmailVerification, error)
+	// HasRecentEmailVerification проверяет, отправлялось ли письмо с подтверждением за последние within
+	HasRecentEmailVerification(ctx context.Context, userID uint, within time.Duration) (bool, error)
+	GetEmailVerificationByToken(ctx context.Context, tokenHash string) (entity.EmailVerification, error)
+	// VerifyEmail подтверждает почту по неиспользованной ссылке, если почта пользователя с тех пор не менялась
+	VerifyEmail(ctx context.Context, verificationID uint) (entity.User, error)
 }
 
 type TokenService interface {
@@ -29,17 +57,31 @@ type TokenService interface {
 	Update(ctx context.Context, refresh entity.RefreshToken) (entity.RefreshToken, error)
 }
 
+type Mailer interface {
+	Send(ctx context.Context, message mailer.Message) error
+}
+
 type userService struct {
 	userRepository Repository
 	tokenService   TokenService
+	mailer         Mailer
+	appURL         string
 }
 
-func NewUserService(userRepo Repository) *userService {
-	return &userService{userRepository: userRepo}
+// NewUserService создает сервис пользователей. appURL — адрес клиентского приложения,
+// на котором открывается ссылка подтверждения почты.
+func NewUserService(userRepo Repository, tokenService TokenService, mailer Mailer, appURL string) *userService {
+	return &userService{
+		userRepository: userRepo,
+		tokenService:   tokenService,
+		mailer:         mailer,
+		appURL:         strings.TrimRight(appURL, "/"),
+	}
 }
 
 // CreateUser создает пользователя, хэшируя его пароль, используя HashArgon2id
-// генерирует access токен и uuid refresh uuid.
+// генерирует access токен и uuid refresh uuid. На почту отправляется ссылка для ее подтверждения,
+// ошибка отправки не мешает регистрации: письмо можно запросить повторно.
 func (us *userService) CreateUser(
 	ctx context.Context,
 	user User,
@@ -57,6 +99,9 @@ func (us *userService) CreateUser(
 	if err != nil {
 		return "", "", err
 	}
+	if err := us.sendVerification(ctx, id, user.Email); err != nil {
+		log.Printf("Failed to send verification email to user %d: %v", id, err)
+	}
 
 	accessToken, refreshToken, err := us.tokenService.GenerateTokens(ctx, fingerprint, id)
 	if err != nil {
@@ -193,6 +238,191 @@ func (us *userService) GetUserByID(ctx context.Context, id uint) (entity.User, e
 	return us.userRepository.GetUserByID(ctx, id)
 }
 
-func (us *userService) UpdateUser(ctx context.Context, id uint, updateUser UpdateUser) error {
-	panic("implement me")
+// UpdateUser меняет профиль пользователя. Почту и пароль можно сменить только с текущим паролем.
+// Новую почту нужно подтвердить заново, после смены пароля все сессии пользователя завершаются.
+func (us *userService) UpdateUser(ctx context.Context, id uint, updateUser UpdateUser) (entity.User, error) {
+	if err := normalizeUpdate(&updateUser); err != nil {
+		return entity.User{}, err
+	}
+
+	user, err := us.userRepository.GetUserByID(ctx, id)
+	if err != nil {
+		return entity.User{}, err
+	}
+	if updateUser.Email != nil && *updateUser.Email == user.Email {
+		updateUser.Email = nil
+	}
+
+	if updateUser.Email != nil || updateUser.Password != nil {
+		if updateUser.CurrentPassword == "" {
+			return entity.User{}, badRequest("current_password is required to change email or password")
+		}
+		compared, err := security.ComparePasswordAndArgon2id(updateUser.CurrentPassword, user.Password)
+		if err != nil {
+			return entity.User{}, err
+		}
+		if !compared {
+			return entity.User{}, apperror.ErrUserWrongPassword
+		}
+	}
+
+	if updateUser.Password != nil {
+		hash, err := security.HashArgon2id(*updateUser.Password)
+		if err != nil {
+			return entity.User{}, &apperror.UserError{
+				Code:    apperror.InternalError,
+				Message: "failed to generate password",
+				Err:     err,
+			}
+		}
+		updateUser.Password = &hash
+	}
+
+	if updateUser.Name == nil && updateUser.Phone == nil && updateUser.Email == nil && updateUser.Password == nil {
+		return user, nil
+	}
+
+	updated, err := us.userRepository.UpdateUser(ctx, id, updateUser)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	if updateUser.Password != nil {
+		if err := us.tokenService.RevokeActivesByUserID(ctx, id); err != nil {
+			return entity.User{}, err
+		}
+	}
+	if updateUser.Email != nil {
+		if err := us.sendVerification(ctx, id, updated.Email); err != nil {
+			log.Printf("Failed to send verification email to user %d: %v", id, err)
+		}
+	}
+
+	return updated, nil
+}
+
+// ResendVerification отправляет новую ссылку подтверждения почты взамен прежних,
+// но не чаще раза в verificationInterval.
+func (us *userService) ResendVerification(ctx context.Context, id uint) error {
+	user, err := us.userRepository.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return apperror.ErrEmailAlreadyVerified
+	}
+
+	recent, err := us.userRepository.HasRecentEmailVerification(ctx, id, verificationInterval)
+	if err != nil {
+		return err
+	}
+	if recent {
+		return &apperror.UserError{
+			Code:    apperror.Conflict,
+			Message: "verification email was sent recently, try again later",
+		}
+	}
+
+	return us.sendVerification(ctx, id, user.Email)
+}
+
+// VerifyEmail подтверждает почту по токену из письма. Ссылка одноразовая и действует,
+// только пока пользователь не сменил почту.
+func (us *userService) VerifyEmail(ctx context.Context, token string) (entity.User, error) {
+	verification, err := us.userRepository.GetEmailVerificationByToken(ctx, security.HashToken(token))
+	if err != nil {
+		return entity.User{}, err
+	}
+	if verification.UsedAt != nil || !verification.ExpiresAt.After(time.Now()) {
+		return entity.User{}, apperror.ErrEmailVerificationNotFound
+	}
+
+	return us.userRepository.VerifyEmail(ctx, verification.ID)
+}
+
+// sendVerification создает ссылку подтверждения почты и отправляет ее на email
+func (us *userService) sendVerification(ctx context.Context, userID uint, email string) error {
+	token, err := security.NewToken()
+	if err != nil {
+		return &apperror.UserError{
+			Code:    apperror.InternalError,
+			Message: "failed to create verification link",
+			Err:     err,
+		}
+	}
+
+	verification, err := us.userRepository.InsertEmailVerification(ctx, EmailVerification{
+		UserID:    userID,
+		Email:     email,
+		TokenHash: security.HashToken(token),
+		ExpiresAt: time.Now().Add(verificationTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	link := us.appURL + "/verify-email?token=" + url.QueryEscape(token)
+	if err := us.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Confirm your email",
+		Body: fmt.Sprintf(
+			"Open the link to confirm your email:\n%s\n\n"+
+				"The link expires on %s. If you did not sign up, ignore this email.",
+			link, verification.ExpiresAt.UTC().Format(time.RFC1123),
+		),
+	}); err != nil {
+		return &apperror.UserError{
+			Code:    apperror.InternalError,
+			Message: "failed to send verification email",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// normalizeUpdate обрезает пробелы в полях профиля и проверяет их
+func normalizeUpdate(update *UpdateUser) error {
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return badRequest("name must not be empty")
+		}
+		if utf8.RuneCountInString(name) > maxNameLength {
+			return badRequest("name is too long")
+		}
+		update.Name = &name
+	}
+
+	if update.Phone != nil {
+		phone := strings.TrimSpace(*update.Phone)
+		if phone == "" {
+			return badRequest("phone must not be empty")
+		}
+		if utf8.RuneCountInString(phone) > maxPhoneLength {
+			return badRequest("phone is too long")
+		}
+		update.Phone = &phone
+	}
+
+	if update.Email != nil {
+		address, err := mail.ParseAddress(strings.TrimSpace(*update.Email))
+		if err != nil || address.Name != "" || len(address.Address) > maxEmailLength {
+			return badRequest("invalid email")
+		}
+		update.Email = &address.Address
+	}
+
+	if update.Password != nil && *update.Password == "" {
+		return badRequest("password must not be empty")
+	}
+
+	return nil
+}
+
+func badRequest(message string) error {
+	return &apperror.UserError{
+		Code:    apperror.BadRequest,
+		Message: message,
+	}
 }
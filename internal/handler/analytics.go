@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/analytics"
+)
+
+// defaultAnalyticsDays за сколько последних дней строится аналитика, если период не задан
+const defaultAnalyticsDays = 30
+
+type AnalyticsService interface {
+	GetStoreAnalytics(ctx context.Context, userID uint, filter analytics.Filter) (entity.StoreAnalytics, error)
+	WriteCSV(storeAnalytics entity.StoreAnalytics, section string, w io.Writer) error
+}
+
+type analyticsHandler struct {
+	analyticsService AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService AnalyticsService) analyticsHandler {
+	return analyticsHandler{analyticsService: analyticsService}
+}
+
+// GetStoreAnalytics отдает аналитику магазина по предложениям. Период задается датами
+// from и to включительно, по умолчанию последние 30 дней. С format=csv отдается
+// один раздел аналитики, выбранный параметром section.
+func (h *analyticsHandler) GetStoreAnalytics(c *gin.Context) {
+	user, storeID, ok := parseStaffRequest(c)
+	if !ok {
+		return
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	filter := analytics.Filter{
+		StoreID: storeID,
+		From:    today.AddDate(0, 0, 1-defaultAnalyticsDays),
+		To:      today.AddDate(0, 0, 1),
+		Bucket:  c.DefaultQuery("bucket", entity.AnalyticsBucketDay),
+	}
+	if !parseDateQuery(c, "from", &filter.From) {
+		return
+	}
+	if !parseDateQuery(c, "to", &filter.To) {
+		return
+	}
+	if c.Query("to") != "" {
+		filter.To = filter.To.AddDate(0, 0, 1)
+		if c.Query("from") == "" {
+			filter.From = filter.To.AddDate(0, 0, -defaultAnalyticsDays)
+		}
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Format must be json or csv",
+		})
+		return
+	}
+
+	storeAnalytics, err := h.analyticsService.GetStoreAnalytics(context.Background(), user.ID, filter)
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, gin.H{"data": storeAnalytics})
+		return
+	}
+
+	section := c.DefaultQuery("section", entity.AnalyticsSectionSeries)
+	var buf bytes.Buffer
+	if err := h.analyticsService.WriteCSV(storeAnalytics, section, &buf); err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="store-%d-%s.csv"`, storeID, section))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// parseDateQuery читает дату в формате 2006-01-02, если параметр передан.
+// При ошибке ответ уже записан и возвращается false.
+func parseDateQuery(c *gin.Context, name string, date *time.Time) bool {
+	value := c.Query(name)
+	if value == "" {
+		return true
+	}
+
+	parsed, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": fmt.Sprintf("Invalid %s date, expected YYYY-MM-DD", name),
+		})
+		return false
+	}
+	*date = parsed
+
+	return true
+}
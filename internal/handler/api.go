@@ -6,8 +6,9 @@ import (
 	"time"
 
 	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
 	"github.com/zuzaaa-dev/stawberry/internal/handler/middleware"
-	objectstorage "github.com/zuzaaa-dev/stawberry/pkg/s3"
+	"github.com/zuzaaa-dev/stawberry/pkg/objectstorage"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,7 +18,18 @@ func SetupRouter(
 	offerH offerHandler,
 	userH userHandler,
 	notificationH notificationHandler,
-	s3 *objectstorage.BucketBasics,
+	categoryH categoryHandler,
+	imageH imageHandler,
+	importH importHandler,
+	exportH exportHandler,
+	priceHistoryH priceHistoryHandler,
+	inventoryH inventoryHandler,
+	storeH storeHandler,
+	staffH staffHandler,
+	analyticsH analyticsHandler,
+	userGetter middleware.UserGetter,
+	tokenValidator middleware.TokenValidator,
+	storageH storageHandler,
 	basePath string,
 ) *gin.Engine {
 	router := gin.New()
@@ -35,6 +47,12 @@ func SetupRouter(
 		})
 	})
 
+	// объекты локального хранилища раздаются самим приложением
+	if storageH.storage != nil {
+		router.GET(objectstorage.LocalRoutePrefix+"*key", storageH.GetObject)
+		router.PUT(objectstorage.LocalRoutePrefix+"*key", storageH.PutObject)
+	}
+
 	base := router.Group(basePath)
 	auth := base.Group("/auth")
 	{
@@ -42,6 +60,111 @@ func SetupRouter(
 		auth.POST("/login", userH.Login)
 		auth.POST("/logout", userH.Logout)
 		auth.POST("/refresh", userH.Refresh)
+		auth.POST("/verify-email", userH.VerifyEmail)
+	}
+
+	authMiddleware := middleware.AuthMiddleware(userGetter, tokenValidator)
+
+	base.PATCH("/users/me", authMiddleware, userH.PatchMe)
+	base.POST("/users/me/verification", authMiddleware, userH.PostResendVerification)
+	base.GET("/notifications", authMiddleware, notificationH.GetNotification)
+
+	products := base.Group("/products")
+	{
+		products.GET("", productH.GetProducts)
+		products.GET("/:id", productH.GetProduct)
+		products.POST("", authMiddleware, productH.PostProduct)
+		products.PATCH("/:id", authMiddleware, productH.PatchProduct)
+		products.DELETE("/:id", authMiddleware, productH.DeleteProduct)
+		products.POST("/:id/archive", authMiddleware, productH.ArchiveProduct)
+		products.POST("/:id/restore", authMiddleware, productH.RestoreProduct)
+
+		products.GET("/:id/variants", productH.GetVariants)
+		products.POST("/:id/variants", authMiddleware, productH.PostVariant)
+		products.PATCH("/:id/variants/:variantID", authMiddleware, productH.PatchVariant)
+		products.DELETE("/:id/variants/:variantID", authMiddleware, productH.DeleteVariant)
+
+		products.GET("/:id/price-history", priceHistoryH.GetPriceHistory)
+
+		products.GET("/:id/images", imageH.GetImages)
+		products.POST("/:id/images/uploads", authMiddleware, imageH.PostImageUpload)
+		products.POST("/:id/images", authMiddleware, imageH.PostImage)
+		products.PUT("/:id/images/order", authMiddleware, imageH.PutImageOrder)
+		products.POST("/:id/images/:imageID/uploads", authMiddleware, imageH.PostImageReplaceUpload)
+		products.DELETE("/:id/images/:imageID", authMiddleware, imageH.DeleteImage)
+	}
+
+	offers := base.Group("/offers", authMiddleware)
+	{
+		offers.POST("", offerH.PostOffer)
+		offers.GET("", offerH.GetUserOffers)
+		offers.GET("/:id", offerH.GetOffer)
+		offers.PATCH("/:id/status", offerH.PatchOfferStatus)
+		offers.PUT("/:id/rating", offerH.PutOfferRating)
+		offers.DELETE("/:id", offerH.DeleteOffer)
+	}
+
+	base.GET("/stores", storeH.GetStores)
+	base.POST("/stores", authMiddleware, storeH.PostStore)
+	base.GET("/stores/mine", authMiddleware, storeH.GetMyStores)
+	base.GET("/stores/verification-queue", authMiddleware, middleware.AdminOnly(), storeH.GetStoresForReview)
+	base.GET("/stores/:id", storeH.GetStore)
+	base.PATCH("/stores/:id", authMiddleware, storeH.PatchStore)
+	base.GET("/stores/:id/profile", storeH.GetStoreProfile)
+	base.GET("/stores/:id/settings", authMiddleware, storeH.GetSettings)
+	base.PUT("/stores/:id/settings", authMiddleware, storeH.PutSettings)
+	base.GET("/stores/:id/verification", authMiddleware, storeH.GetVerification)
+	base.PUT("/stores/:id/verification", authMiddleware, storeH.PutLegalDetails)
+	base.POST("/stores/:id/verification/documents/uploads", authMiddleware, storeH.PostDocumentUpload)
+	base.POST("/stores/:id/verification/documents", authMiddleware, storeH.PostDocument)
+	base.DELETE("/stores/:id/verification/documents/:documentID", authMiddleware, storeH.DeleteDocument)
+	base.POST("/stores/:id/verification/submit", authMiddleware, storeH.PostSubmitVerification)
+	base.POST("/stores/:id/verification/review", authMiddleware, middleware.AdminOnly(), storeH.PostReview)
+	base.GET("/stores/:id/shop-points", storeH.GetShopPoints)
+	base.POST("/stores/:id/shop-points", authMiddleware, storeH.PostShopPoint)
+	base.GET("/stores/:id/products", productH.GetStoreProducts)
+	base.POST("/stores/:id/imports", authMiddleware, importH.PostImport)
+	base.GET("/stores/:id/export", exportH.GetExport)
+	base.GET("/stores/:id/low-stock-thresholds", authMiddleware, inventoryH.GetThresholds)
+	base.PUT("/stores/:id/low-stock-thresholds", authMiddleware, inventoryH.PutThreshold)
+	base.DELETE("/stores/:id/low-stock-thresholds/:thresholdID", authMiddleware, inventoryH.DeleteThreshold)
+	base.GET("/stores/:id/low-stock-report", authMiddleware, inventoryH.GetLowStockReport)
+	base.GET("/stores/:id/members", authMiddleware, staffH.GetMembers)
+	base.DELETE("/stores/:id/members/:userID", authMiddleware, staffH.DeleteMember)
+	base.GET("/stores/:id/invitations", authMiddleware, staffH.GetInvitations)
+	base.POST("/stores/:id/invitations", authMiddleware, staffH.PostInvitation)
+	base.DELETE("/stores/:id/invitations/:invitationID", authMiddleware, staffH.DeleteInvitation)
+	base.GET("/stores/:id/analytics", authMiddleware, analyticsH.GetStoreAnalytics)
+	base.POST("/invitations/accept", authMiddleware, staffH.PostAcceptInvitation)
+
+	shopPoints := base.Group("/shop-points")
+	{
+		shopPoints.GET("/nearby", inventoryH.GetNearbyPoints)
+		shopPoints.PATCH("/:id", authMiddleware, storeH.PatchShopPoint)
+		shopPoints.DELETE("/:id", authMiddleware, storeH.DeleteShopPoint)
+		shopPoints.GET("/:id/schedule", inventoryH.GetSchedule)
+		shopPoints.PUT("/:id/schedule", authMiddleware, inventoryH.PutHours)
+		shopPoints.PUT("/:id/schedule/exceptions/:date", authMiddleware, inventoryH.PutException)
+		shopPoints.DELETE("/:id/schedule/exceptions/:date", authMiddleware, inventoryH.DeleteException)
+		shopPoints.GET("/:id/inventory", authMiddleware, inventoryH.GetInventory)
+		shopPoints.GET("/:id/inventory/movements", authMiddleware, inventoryH.GetMovements)
+		shopPoints.PUT("/:id/inventory/:variantID", authMiddleware, inventoryH.PutInventoryItem)
+		shopPoints.POST("/:id/inventory/:variantID/adjustments", authMiddleware, inventoryH.PostAdjustment)
+	}
+
+	imports := base.Group("/imports", authMiddleware)
+	{
+		imports.GET("/:id", importH.GetImport)
+		imports.GET("/:id/errors", importH.GetImportErrors)
+	}
+
+	categories := base.Group("/categories")
+	{
+		categories.GET("/:id/attributes", categoryH.GetAttributes)
+
+		admin := categories.Group("", authMiddleware, middleware.AdminOnly())
+		admin.PUT("/:id/attributes/:name", categoryH.PutAttribute)
+		admin.DELETE("/:id/attributes/:name", categoryH.DeleteAttribute)
 	}
 
 	return router
@@ -55,16 +178,27 @@ func handleProductError(c *gin.Context, err error) {
 		switch productErr.Code {
 		case apperror.NotFound:
 			status = http.StatusNotFound
-		case apperror.DuplicateError:
+		case apperror.Forbidden:
+			status = http.StatusForbidden
+		case apperror.DuplicateError, apperror.Conflict:
 			status = http.StatusConflict
+		case apperror.BadRequest:
+			status = http.StatusBadRequest
+		case apperror.Validation:
+			status = http.StatusUnprocessableEntity
 		case apperror.DatabaseError:
 			status = http.StatusInternalServerError
 		}
 
-		c.JSON(status, gin.H{
+		response := gin.H{
 			"code":    productErr.Code,
 			"message": productErr.Message,
-		})
+		}
+		if len(productErr.Fields) > 0 {
+			response["fields"] = productErr.Fields
+		}
+
+		c.JSON(status, response)
 		return
 	}
 
@@ -82,7 +216,11 @@ func handleOfferError(c *gin.Context, err error) {
 		switch offerError.Code {
 		case apperror.NotFound:
 			status = http.StatusNotFound
-		case apperror.DuplicateError:
+		case apperror.Forbidden:
+			status = http.StatusForbidden
+		case apperror.BadRequest:
+			status = http.StatusBadRequest
+		case apperror.DuplicateError, apperror.Conflict:
 			status = http.StatusConflict
 		case apperror.DatabaseError:
 			status = http.StatusInternalServerError
@@ -109,7 +247,11 @@ func handleUserError(c *gin.Context, err error) {
 		switch userError.Code {
 		case apperror.NotFound:
 			status = http.StatusNotFound
-		case apperror.DuplicateError:
+		case apperror.BadRequest:
+			status = http.StatusBadRequest
+		case apperror.Forbidden:
+			status = http.StatusForbidden
+		case apperror.DuplicateError, apperror.Conflict:
 			status = http.StatusConflict
 		case apperror.DatabaseError:
 			status = http.StatusInternalServerError
@@ -137,6 +279,8 @@ func handleNotificationError(c *gin.Context, err error) {
 		switch notificationErr.Code {
 		case apperror.NotFound:
 			status = http.StatusNotFound
+		case apperror.BadRequest:
+			status = http.StatusBadRequest
 		case apperror.DuplicateError:
 			status = http.StatusConflict
 		case apperror.DatabaseError:
@@ -155,3 +299,168 @@ func handleNotificationError(c *gin.Context, err error) {
 		"message": "An unexpected error occurred",
 	})
 }
+
+func handleCategoryError(c *gin.Context, err error) {
+	var categoryErr *apperror.CategoryError
+	if errors.As(err, &categoryErr) {
+		status := http.StatusInternalServerError
+
+		switch categoryErr.Code {
+		case apperror.NotFound:
+			status = http.StatusNotFound
+		case apperror.BadRequest:
+			status = http.StatusBadRequest
+		case apperror.DatabaseError:
+			status = http.StatusInternalServerError
+		}
+
+		c.JSON(status, gin.H{
+			"code":    categoryErr.Code,
+			"message": categoryErr.Message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"code":    apperror.InternalError,
+		"message": "An unexpected error occurred",
+	})
+}
+
+func handleImageError(c *gin.Context, err error) {
+	var imageErr *apperror.ImageError
+	if errors.As(err, &imageErr) {
+		status := http.StatusInternalServerError
+
+		switch imageErr.Code {
+		case apperror.NotFound:
+			status = http.StatusNotFound
+		case apperror.Forbidden:
+			status = http.StatusForbidden
+		case apperror.BadRequest:
+			status = http.StatusBadRequest
+		case apperror.UnsupportedMedia:
+			status = http.StatusUnsupportedMediaType
+		case apperror.TooLarge:
+			status = http.StatusRequestEntityTooLarge
+		case apperror.DuplicateError:
+			status = http.StatusConflict
+		case apperror.DatabaseError:
+			status = http.StatusInternalServerError
+		}
+
+		c.JSON(status, gin.H{
+			"code":    imageErr.Code,
+			"message": imageErr.Message,
+		})
+		return
+	}
+
+	handleProductError(c, err)
+}
+
+func handleImportError(c *gin.Context, err error) {
+	var importErr *apperror.ImportError
+	if errors.As(err, &importErr) {
+		status := http.StatusInternalServerError
+
+		switch importErr.Code {
+		case apperror.NotFound:
+			status = http.StatusNotFound
+		case apperror.Forbidden:
+			status = http.StatusForbidden
+		case apperror.BadRequest:
+			status = http.StatusBadRequest
+		case apperror.DatabaseError:
+			status = http.StatusInternalServerError
+		}
+
+		c.JSON(status, gin.H{
+			"code":    importErr.Code,
+			"message": importErr.Message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"code":    apperror.InternalError,
+		"message": "An unexpected error occurred",
+	})
+}
+
+func handleStoreError(c *gin.Context, err error) {
+	var storeErr *apperror.StoreError
+	if errors.As(err, &storeErr) {
+		status := http.StatusInternalServerError
+
+		switch storeErr.Code {
+		case apperror.NotFound:
+			status = http.StatusNotFound
+		case apperror.Forbidden:
+			status = http.StatusForbidden
+		case apperror.BadRequest:
+			status = http.StatusBadRequest
+		case apperror.Conflict:
+			status = http.StatusConflict
+		case apperror.UnsupportedMedia:
+			status = http.StatusUnsupportedMediaType
+		case apperror.TooLarge:
+			status = http.StatusRequestEntityTooLarge
+		case apperror.DatabaseError:
+			status = http.StatusInternalServerError
+		}
+
+		c.JSON(status, gin.H{
+			"code":    storeErr.Code,
+			"message": storeErr.Message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"code":    apperror.InternalError,
+		"message": "An unexpected error occurred",
+	})
+}
+
+func handleInventoryError(c *gin.Context, err error) {
+	var inventoryErr *apperror.InventoryError
+	if errors.As(err, &inventoryErr) {
+		status := http.StatusInternalServerError
+
+		switch inventoryErr.Code {
+		case apperror.NotFound:
+			status = http.StatusNotFound
+		case apperror.Forbidden:
+			status = http.StatusForbidden
+		case apperror.BadRequest:
+			status = http.StatusBadRequest
+		case apperror.Conflict:
+			status = http.StatusConflict
+		case apperror.DatabaseError:
+			status = http.StatusInternalServerError
+		}
+
+		c.JSON(status, gin.H{
+			"code":    inventoryErr.Code,
+			"message": inventoryErr.Message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"code":    apperror.InternalError,
+		"message": "An unexpected error occurred",
+	})
+}
+
+// getUserFromContext достает пользователя, которого положил AuthMiddleware
+func getUserFromContext(c *gin.Context) (entity.User, bool) {
+	value, ok := c.Get("user")
+	if !ok {
+		return entity.User{}, false
+	}
+
+	user, ok := value.(entity.User)
+	return user, ok
+}
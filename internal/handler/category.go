@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/category"
+	"github.com/zuzaaa-dev/stawberry/internal/handler/dto"
+)
+
+type CategoryService interface {
+	GetCategorySchema(ctx context.Context, categoryID uint) ([]entity.CategoryAttribute, error)
+	SetCategoryAttribute(ctx context.Context, attribute category.Attribute) (entity.CategoryAttribute, error)
+	DeleteCategoryAttribute(ctx context.Context, categoryID uint, name string) error
+}
+
+type categoryHandler struct {
+	categoryService CategoryService
+}
+
+func NewCategoryHandler(categoryService CategoryService) categoryHandler {
+	return categoryHandler{categoryService: categoryService}
+}
+
+// GetAttributes возвращает схему атрибутов категории с учетом унаследованных атрибутов
+func (h *categoryHandler) GetAttributes(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid category id",
+		})
+		return
+	}
+
+	schema, err := h.categoryService.GetCategorySchema(context.Background(), uint(id))
+	if err != nil {
+		handleCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": schema})
+}
+
+// PutAttribute создает или заменяет атрибут в схеме категории
+func (h *categoryHandler) PutAttribute(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid category id",
+		})
+		return
+	}
+
+	var req dto.PutCategoryAttributeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid attribute data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	attribute, err := h.categoryService.SetCategoryAttribute(
+		context.Background(),
+		req.ConvertToSvc(uint(id), c.Param("name")),
+	)
+	if err != nil {
+		handleCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attribute)
+}
+
+// DeleteAttribute удаляет атрибут из схемы категории
+func (h *categoryHandler) DeleteAttribute(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid category id",
+		})
+		return
+	}
+
+	if err := h.categoryService.DeleteCategoryAttribute(context.Background(), uint(id), c.Param("name")); err != nil {
+		handleCategoryError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "github.com/zuzaaa-dev/stawberry/internal/domain/service/category"
+
+type PutCategoryAttributeReq struct {
+	Type          string   `json:"type" binding:"required"`
+	Unit          string   `json:"unit"`
+	Required      bool     `json:"required"`
+	AllowedValues []string `json:"allowed_values"`
+}
+
+func (pa *PutCategoryAttributeReq) ConvertToSvc(categoryID uint, name string) category.Attribute {
+	return category.Attribute{
+		CategoryID:    categoryID,
+		Name:          name,
+		Type:          pa.Type,
+		Unit:          pa.Unit,
+		Required:      pa.Required,
+		AllowedValues: pa.AllowedValues,
+	}
+}
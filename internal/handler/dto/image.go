@@ -0,0 +1,14 @@
+package dto
+
+type PostImageUploadReq struct {
+	ContentType string `json:"content_type" binding:"required"`
+	Size        int64  `json:"size" binding:"required"`
+}
+
+type PostImageReq struct {
+	Key string `json:"key" binding:"required"`
+}
+
+type PutImageOrderReq struct {
+	ImageIDs []uint `json:"image_ids" binding:"required"`
+}
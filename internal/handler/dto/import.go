@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/importjob"
+)
+
+type PostImportReq struct {
+	// Format csv или jsonl, по умолчанию определяется по расширению файла
+	Format string `form:"format"`
+	// Mapping JSON-объект поле товара -> колонка файла
+	Mapping string `form:"mapping"`
+}
+
+func (pi *PostImportReq) ConvertToSvc(shopID, userID uint, filename string) (importjob.Job, error) {
+	format := pi.Format
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(path.Ext(filename)), ".")
+		if format == "ndjson" {
+			format = "jsonl"
+		}
+	}
+
+	var mapping map[string]string
+	if strings.TrimSpace(pi.Mapping) != "" {
+		if err := json.Unmarshal([]byte(pi.Mapping), &mapping); err != nil {
+			return importjob.Job{}, err
+		}
+	}
+
+	return importjob.Job{
+		ShopID:  shopID,
+		UserID:  userID,
+		Format:  format,
+		Mapping: mapping,
+	}, nil
+}
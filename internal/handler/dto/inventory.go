@@ -0,0 +1,52 @@
+package dto
+
+import "github.com/zuzaaa-dev/stawberry/internal/domain/service/inventory"
+
+type PutInventoryReq struct {
+	Price    *float64 `json:"price" binding:"required"`
+	Quantity *int     `json:"quantity" binding:"required"`
+	Reason   string   `json:"reason"`
+	Note     string   `json:"note"`
+}
+
+func (pi *PutInventoryReq) ConvertToSvc(shopPointID, variantID uint) inventory.StockChange {
+	return inventory.StockChange{
+		ShopPointID: shopPointID,
+		VariantID:   variantID,
+		Price:       pi.Price,
+		Quantity:    pi.Quantity,
+		Reason:      pi.Reason,
+		Note:        pi.Note,
+	}
+}
+
+type PostAdjustmentReq struct {
+	Delta  int    `json:"delta" binding:"required"`
+	Reason string `json:"reason" binding:"required"`
+	Note   string `json:"note"`
+}
+
+func (pa *PostAdjustmentReq) ConvertToSvc(shopPointID, variantID uint) inventory.StockChange {
+	return inventory.StockChange{
+		ShopPointID: shopPointID,
+		VariantID:   variantID,
+		Delta:       &pa.Delta,
+		Reason:      pa.Reason,
+		Note:        pa.Note,
+	}
+}
+
+type PutThresholdReq struct {
+	ProductID  *uint `json:"product_id"`
+	CategoryID *uint `json:"category_id"`
+	Threshold  *int  `json:"threshold" binding:"required"`
+}
+
+func (pt *PutThresholdReq) ConvertToSvc(shopID uint) inventory.ThresholdSetting {
+	return inventory.ThresholdSetting{
+		ShopID:     shopID,
+		ProductID:  pt.ProductID,
+		CategoryID: pt.CategoryID,
+		Threshold:  *pt.Threshold,
+	}
+}
@@ -7,29 +7,33 @@ import (
 )
 
 type PostOfferReq struct {
-	UserID    uint      `json:"user_id"`
-	ProductID uint      `json:"product_id"`
-	StoreID   uint      `json:"store_id"`
-	Price     float64   `json:"price"`
-	Status    string    `json:"status"`
-	ExpiresAt time.Time `json:"expires_at"`
-}
-
-type PostOfferResp struct {
-	ID uint `json:"id"`
+	UserID      uint      `json:"user_id"`
+	ProductID   uint      `json:"product_id"`
+	VariantID   *uint     `json:"variant_id"`
+	StoreID     uint      `json:"store_id" binding:"required"`
+	ShopPointID *uint     `json:"shop_point_id"`
+	Price       float64   `json:"price"`
+	Status      string    `json:"status"`
+	ExpiresAt   time.Time `json:"expires_at"`
 }
 
 func (po *PostOfferReq) ConvertToSvc() offer.Offer {
 	return offer.Offer{
-		UserID:    po.UserID,
-		ProductID: po.ProductID,
-		StoreID:   po.StoreID,
-		Price:     po.Price,
-		Status:    po.Status,
-		ExpiresAt: po.ExpiresAt,
+		UserID:      po.UserID,
+		ProductID:   po.ProductID,
+		VariantID:   po.VariantID,
+		StoreID:     po.StoreID,
+		ShopPointID: po.ShopPointID,
+		Price:       po.Price,
+		Status:      po.Status,
+		ExpiresAt:   po.ExpiresAt,
 	}
 }
 
 type PatchOfferStatusReq struct {
 	Status string `json:"status" binding:"required"`
 }
+
+type PutOfferRatingReq struct {
+	Rating int `json:"rating" binding:"required"`
+}
@@ -3,12 +3,14 @@ package dto
 import "github.com/zuzaaa-dev/stawberry/internal/domain/service/product"
 
 type PostProductReq struct {
-	StoreID     uint    `json:"store_id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Category    string  `json:"category"`
-	InStock     bool    `json:"in_stock"`
+	StoreID     uint             `json:"store_id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Price       float64          `json:"price"`
+	CategoryID  *uint            `json:"category_id"`
+	Attributes  map[string]any   `json:"attributes"`
+	InStock     bool             `json:"in_stock"`
+	Variants    []PostVariantReq `json:"variants"`
 }
 
 type PostProductResp struct {
@@ -16,32 +18,37 @@ type PostProductResp struct {
 }
 
 func (pp *PostProductReq) ConvertToSvc() product.Product {
+	variants := make([]product.Variant, 0, len(pp.Variants))
+	for i := range pp.Variants {
+		variants = append(variants, pp.Variants[i].ConvertToSvc(0))
+	}
+
 	return product.Product{
 		StoreID:     pp.StoreID,
 		Name:        pp.Name,
 		Description: pp.Description,
 		Price:       pp.Price,
-		Category:    pp.Category,
+		CategoryID:  pp.CategoryID,
+		Attributes:  pp.Attributes,
 		InStock:     pp.InStock,
+		Variants:    variants,
 	}
 }
 
 type PatchProductReq struct {
-	StoreID     *uint    `json:"store_id,omitempty"`
-	Name        *string  `json:"name,omitempty"`
-	Description *string  `json:"description,omitempty"`
-	Price       *float64 `json:"price,omitempty"`
-	Category    *string  `json:"category,omitempty"`
-	InStock     *bool    `json:"in_stock,omitempty"`
+	Name        *string        `json:"name,omitempty"`
+	Description *string        `json:"description,omitempty"`
+	Price       *float64       `json:"price,omitempty"`
+	CategoryID  *uint          `json:"category_id,omitempty"`
+	Attributes  map[string]any `json:"attributes,omitempty"`
 }
 
 func (pp *PatchProductReq) ConvertToSvc() product.UpdateProduct {
 	return product.UpdateProduct{
-		StoreID:     pp.StoreID,
 		Name:        pp.Name,
 		Description: pp.Description,
 		Price:       pp.Price,
-		Category:    pp.Category,
-		InStock:     pp.InStock,
+		CategoryID:  pp.CategoryID,
+		Attributes:  pp.Attributes,
 	}
 }
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/inventory"
+)
+
+type OpeningHoursReq struct {
+	Weekday  *time.Weekday `json:"weekday" binding:"required"`
+	OpensAt  *entity.Clock `json:"opens_at" binding:"required"`
+	ClosesAt *entity.Clock `json:"closes_at" binding:"required"`
+}
+
+type PutHoursReq struct {
+	Timezone string            `json:"timezone"`
+	Hours    []OpeningHoursReq `json:"hours" binding:"dive"`
+}
+
+func (ph *PutHoursReq) ConvertToSvc(shopPointID uint) inventory.HoursSetting {
+	hours := make([]entity.OpeningHours, 0, len(ph.Hours))
+	for _, h := range ph.Hours {
+		hours = append(hours, entity.OpeningHours{
+			Weekday:  *h.Weekday,
+			OpensAt:  *h.OpensAt,
+			ClosesAt: *h.ClosesAt,
+		})
+	}
+
+	return inventory.HoursSetting{
+		ShopPointID: shopPointID,
+		Timezone:    ph.Timezone,
+		Hours:       hours,
+	}
+}
+
+// PutExceptionReq особый день точки продаж, без часов работы точка закрыта
+type PutExceptionReq struct {
+	OpensAt  *entity.Clock `json:"opens_at"`
+	ClosesAt *entity.Clock `json:"closes_at"`
+	Note     string        `json:"note"`
+}
+
+func (pe *PutExceptionReq) ConvertToSvc(date string) entity.ScheduleException {
+	return entity.ScheduleException{
+		Date:     date,
+		OpensAt:  pe.OpensAt,
+		ClosesAt: pe.ClosesAt,
+		Note:     pe.Note,
+	}
+}
@@ -0,0 +1,10 @@
+package dto
+
+type PostInvitationReq struct {
+	Email string `json:"email" binding:"required"`
+	Role  string `json:"role" binding:"required"`
+}
+
+type PostAcceptInvitationReq struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -0,0 +1,81 @@
+package dto
+
+import "github.com/zuzaaa-dev/stawberry/internal/domain/service/store"
+
+type PostStoreReq struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+func (ps *PostStoreReq) ConvertToSvc() store.Store {
+	return store.Store{
+		Name:        ps.Name,
+		Description: ps.Description,
+	}
+}
+
+type PatchStoreReq struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+func (ps *PatchStoreReq) ConvertToSvc() store.UpdateStore {
+	return store.UpdateStore{
+		Name:        ps.Name,
+		Description: ps.Description,
+	}
+}
+
+type PostShopPointReq struct {
+	Address   string   `json:"address" binding:"required"`
+	Phone     string   `json:"phone" binding:"required"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	Timezone  string   `json:"timezone"`
+}
+
+func (ps *PostShopPointReq) ConvertToSvc(storeID uint) store.ShopPoint {
+	return store.ShopPoint{
+		StoreID:   storeID,
+		Address:   ps.Address,
+		Phone:     ps.Phone,
+		Latitude:  ps.Latitude,
+		Longitude: ps.Longitude,
+		Timezone:  ps.Timezone,
+	}
+}
+
+type PatchShopPointReq struct {
+	Address   *string  `json:"address"`
+	Phone     *string  `json:"phone"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+func (ps *PatchShopPointReq) ConvertToSvc() store.UpdateShopPoint {
+	return store.UpdateShopPoint{
+		Address:   ps.Address,
+		Phone:     ps.Phone,
+		Latitude:  ps.Latitude,
+		Longitude: ps.Longitude,
+	}
+}
+
+// PutStoreSettingsReq правила торга магазина. Пустые max_rounds и max_discount снимают ограничения.
+type PutStoreSettingsReq struct {
+	OfferTTLMinutes int      `json:"offer_ttl_minutes" binding:"required"`
+	MaxRounds       *int     `json:"max_rounds"`
+	MaxDiscount     *float64 `json:"max_discount"`
+	AcceptsOffers   *bool    `json:"accepts_offers" binding:"required"`
+	Currency        string   `json:"currency" binding:"required"`
+}
+
+func (ps *PutStoreSettingsReq) ConvertToSvc() store.Settings {
+	return store.Settings{
+		OfferTTLMinutes: ps.OfferTTLMinutes,
+		MaxRounds:       ps.MaxRounds,
+		MaxDiscount:     ps.MaxDiscount,
+		AcceptsOffers:   *ps.AcceptsOffers,
+		Currency:        ps.Currency,
+	}
+}
@@ -23,6 +23,7 @@ func (ru *RegistrationUserReq) ConvertToSvc() user.User {
 		Name:     ru.Name,
 		Password: ru.Password,
 		Email:    ru.Email,
+		Phone:    ru.Phone,
 		IsStore:  ru.IsStore,
 	}
 }
@@ -52,3 +53,26 @@ type LogoutReq struct {
 	RefreshToken string `json:"refresh_token,omitempty"`
 	Fingerprint  string `json:"fingerprint" validate:"required"`
 }
+
+// PatchUserReq изменение профиля. current_password обязателен при смене почты и пароля.
+type PatchUserReq struct {
+	Name            *string `json:"name"`
+	Phone           *string `json:"phone"`
+	Email           *string `json:"email"`
+	Password        *string `json:"password"`
+	CurrentPassword string  `json:"current_password"`
+}
+
+func (pu *PatchUserReq) ConvertToSvc() user.UpdateUser {
+	return user.UpdateUser{
+		Name:            pu.Name,
+		Phone:           pu.Phone,
+		Email:           pu.Email,
+		Password:        pu.Password,
+		CurrentPassword: pu.CurrentPassword,
+	}
+}
+
+type VerifyEmailReq struct {
+	Token string `json:"token" binding:"required"`
+}
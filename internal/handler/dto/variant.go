@@ -0,0 +1,32 @@
+package dto
+
+import "github.com/zuzaaa-dev/stawberry/internal/domain/service/product"
+
+type PostVariantReq struct {
+	SKU     string            `json:"sku"`
+	Barcode string            `json:"barcode"`
+	Options map[string]string `json:"options"`
+}
+
+func (pv *PostVariantReq) ConvertToSvc(productID uint) product.Variant {
+	return product.Variant{
+		ProductID: productID,
+		SKU:       pv.SKU,
+		Barcode:   pv.Barcode,
+		Options:   pv.Options,
+	}
+}
+
+type PatchVariantReq struct {
+	SKU     *string           `json:"sku,omitempty"`
+	Barcode *string           `json:"barcode,omitempty"`
+	Options map[string]string `json:"options,omitempty"`
+}
+
+func (pv *PatchVariantReq) ConvertToSvc() product.UpdateVariant {
+	return product.UpdateVariant{
+		SKU:     pv.SKU,
+		Barcode: pv.Barcode,
+		Options: pv.Options,
+	}
+}
@@ -0,0 +1,39 @@
+package dto
+
+import "github.com/zuzaaa-dev/stawberry/internal/domain/service/store"
+
+type PutLegalDetailsReq struct {
+	LegalName    string `json:"legal_name" binding:"required"`
+	TaxID        string `json:"tax_id" binding:"required"`
+	LegalAddress string `json:"legal_address" binding:"required"`
+}
+
+func (pl *PutLegalDetailsReq) ConvertToSvc() store.LegalDetails {
+	return store.LegalDetails{
+		LegalName:    pl.LegalName,
+		TaxID:        pl.TaxID,
+		LegalAddress: pl.LegalAddress,
+	}
+}
+
+type PostDocumentUploadReq struct {
+	ContentType string `json:"content_type" binding:"required"`
+	Size        int64  `json:"size" binding:"required"`
+}
+
+type PostDocumentReq struct {
+	Kind string `json:"kind" binding:"required"`
+	Key  string `json:"key" binding:"required"`
+}
+
+type PostReviewReq struct {
+	Status  string `json:"status" binding:"required"`
+	Comment string `json:"comment"`
+}
+
+func (pr *PostReviewReq) ConvertToSvc() store.Review {
+	return store.Review{
+		Status:  pr.Status,
+		Comment: pr.Comment,
+	}
+}
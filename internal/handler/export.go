@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+type ExportService interface {
+	GetCatalog(ctx context.Context, shopID uint) (entity.Catalog, error)
+	WriteFeed(ctx context.Context, catalog entity.Catalog, format string, w io.Writer) error
+}
+
+type exportHandler struct {
+	exportService ExportService
+}
+
+func NewExportHandler(exportService ExportService) exportHandler {
+	return exportHandler{exportService: exportService}
+}
+
+// feedContentTypes форматы выгрузки и их типы содержимого
+var feedContentTypes = map[string]string{
+	entity.FeedFormatCSV: "text/csv; charset=utf-8",
+	entity.FeedFormatYML: "application/xml; charset=utf-8",
+}
+
+// GetExport отдает каталог магазина в CSV или YML. Выгрузка пишется в ответ
+// по мере чтения из базы, повторный запрос с If-Modified-Since получает 304,
+// если ассортимент не менялся.
+func (h *exportHandler) GetExport(c *gin.Context) {
+	shopID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid store id",
+		})
+		return
+	}
+
+	format := c.DefaultQuery("format", entity.FeedFormatYML)
+	contentType, ok := feedContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Format must be csv or yml",
+		})
+		return
+	}
+
+	catalog, err := h.exportService.GetCatalog(context.Background(), uint(shopID))
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	// Last-Modified передается с точностью до секунды
+	modified := catalog.UpdatedAt.UTC().Truncate(time.Second)
+	if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !modified.After(since) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Last-Modified", modified.Format(http.TimeFormat))
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="store-%d.%s"`, shopID, format))
+	c.Status(http.StatusOK)
+
+	if err := h.exportService.WriteFeed(c.Request.Context(), catalog, format, c.Writer); err != nil {
+		// заголовки уже отправлены, поэтому соединение закрывается без завершения ответа,
+		// чтобы клиент не принял обрезанную выгрузку за полную
+		log.Printf("failed to export catalog of store %d: %v", shopID, err)
+		if conn, _, err := c.Writer.Hijack(); err == nil {
+			_ = conn.Close()
+		}
+	}
+}
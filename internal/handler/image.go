@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/handler/dto"
+)
+
+type ImageService interface {
+	CreateUpload(
+		ctx context.Context,
+		userID, productID uint,
+		contentType string,
+		size int64,
+	) (entity.ImageUpload, error)
+	CreateReplaceUpload(
+		ctx context.Context,
+		userID, productID, imageID uint,
+		contentType string,
+		size int64,
+	) (entity.ImageUpload, error)
+	ConfirmUpload(ctx context.Context, userID, productID uint, key string) (entity.ProductImage, error)
+	GetProductImages(ctx context.Context, productID uint) ([]entity.ProductImage, error)
+	ReorderImages(ctx context.Context, userID, productID uint, imageIDs []uint) ([]entity.ProductImage, error)
+	DeleteImage(ctx context.Context, userID, productID, imageID uint) error
+}
+
+type imageHandler struct {
+	imageService ImageService
+}
+
+func NewImageHandler(imageService ImageService) imageHandler {
+	return imageHandler{imageService: imageService}
+}
+
+// PostImageUpload выдает подписанную ссылку, по которой клиент
+// сам загружает изображение в хранилище
+func (h *imageHandler) PostImageUpload(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid product id",
+		})
+		return
+	}
+
+	var req dto.PostImageUploadReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid upload data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	upload, err := h.imageService.CreateUpload(
+		context.Background(),
+		user.ID,
+		uint(productID),
+		req.ContentType,
+		req.Size,
+	)
+	if err != nil {
+		handleImageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, upload)
+}
+
+// PostImageReplaceUpload выдает подписанную ссылку для замены оригинала изображения,
+// после подтверждения уменьшенные копии строятся заново
+func (h *imageHandler) PostImageReplaceUpload(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid product id",
+		})
+		return
+	}
+
+	imageID, err := strconv.ParseUint(c.Param("imageID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid image id",
+		})
+		return
+	}
+
+	var req dto.PostImageUploadReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid upload data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	upload, err := h.imageService.CreateReplaceUpload(
+		context.Background(),
+		user.ID,
+		uint(productID),
+		uint(imageID),
+		req.ContentType,
+		req.Size,
+	)
+	if err != nil {
+		handleImageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, upload)
+}
+
+// PostImage подтверждает загрузку и добавляет изображение к товару
+func (h *imageHandler) PostImage(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid product id",
+		})
+		return
+	}
+
+	var req dto.PostImageReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid image data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	image, err := h.imageService.ConfirmUpload(context.Background(), user.ID, uint(productID), req.Key)
+	if err != nil {
+		handleImageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, image)
+}
+
+func (h *imageHandler) GetImages(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid product id",
+		})
+		return
+	}
+
+	images, err := h.imageService.GetProductImages(context.Background(), uint(productID))
+	if err != nil {
+		handleImageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": images})
+}
+
+// PutImageOrder задает порядок изображений товара
+func (h *imageHandler) PutImageOrder(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid product id",
+		})
+		return
+	}
+
+	var req dto.PutImageOrderReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid image order",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	images, err := h.imageService.ReorderImages(context.Background(), user.ID, uint(productID), req.ImageIDs)
+	if err != nil {
+		handleImageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": images})
+}
+
+func (h *imageHandler) DeleteImage(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid product id",
+		})
+		return
+	}
+
+	imageID, err := strconv.ParseUint(c.Param("imageID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid image id",
+		})
+		return
+	}
+
+	if err := h.imageService.DeleteImage(context.Background(), user.ID, uint(productID), uint(imageID)); err != nil {
+		handleImageError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
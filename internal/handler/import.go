@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/importjob"
+	"github.com/zuzaaa-dev/stawberry/internal/handler/dto"
+)
+
+type ImportService interface {
+	CreateJob(ctx context.Context, job importjob.Job, file io.Reader, size int64) (entity.ImportJob, error)
+	GetJob(ctx context.Context, userID, jobID uint) (entity.ImportJob, error)
+	GetJobErrors(ctx context.Context, userID, jobID uint) ([]entity.ImportRowError, error)
+}
+
+type importHandler struct {
+	importService ImportService
+	maxSize       int64
+}
+
+func NewImportHandler(importService ImportService, maxSize int64) importHandler {
+	return importHandler{importService: importService, maxSize: maxSize}
+}
+
+// PostImport принимает файл CSV или JSON lines и ставит его импорт в очередь
+func (h *importHandler) PostImport(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	shopID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid store id",
+		})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": fmt.Sprintf("File is required and must not exceed %d bytes", h.maxSize),
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var req dto.PostImportReq
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid import data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	job, err := req.ConvertToSvc(uint(shopID), user.ID, fileHeader.Filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Mapping must be a JSON object of field to column names",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Failed to read uploaded file",
+		})
+		return
+	}
+	defer file.Close()
+
+	created, err := h.importService.CreateJob(context.Background(), job, file, fileHeader.Size)
+	if err != nil {
+		handleImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, created)
+}
+
+// GetImport возвращает статус и счетчики задания импорта
+func (h *importHandler) GetImport(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid import id",
+		})
+		return
+	}
+
+	job, err := h.importService.GetJob(context.Background(), user.ID, uint(jobID))
+	if err != nil {
+		handleImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// GetImportErrors отдает отчет об ошибках строк в CSV
+func (h *importHandler) GetImportErrors(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid import id",
+		})
+		return
+	}
+
+	rowErrors, err := h.importService.GetJobErrors(context.Background(), user.ID, uint(jobID))
+	if err != nil {
+		handleImportError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="import-%d-errors.csv"`, jobID))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"row", "field", "message"})
+	for _, rowErr := range rowErrors {
+		_ = w.Write([]string{strconv.Itoa(rowErr.Row), rowErr.Field, rowErr.Message})
+	}
+	w.Flush()
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/inventory"
+	"github.com/zuzaaa-dev/stawberry/internal/handler/dto"
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
+)
+
+type InventoryService interface {
+	SetStock(ctx context.Context, userID uint, change inventory.StockChange) (entity.InventoryItem, error)
+	AdjustStock(ctx context.Context, userID uint, change inventory.StockChange) (entity.InventoryItem, error)
+	GetPointInventory(
+		ctx context.Context,
+		userID, shopPointID uint,
+		params pagination.Params,
+	) ([]entity.InventoryItem, pagination.Page, error)
+	GetMovements(
+		ctx context.Context,
+		userID uint,
+		filter inventory.MovementFilter,
+		params pagination.Params,
+	) ([]entity.InventoryMovement, pagination.Page, error)
+	GetThresholds(ctx context.Context, userID, shopID uint) ([]entity.LowStockThreshold, error)
+	SetThreshold(ctx context.Context, userID uint, setting inventory.ThresholdSetting) (entity.LowStockThreshold, error)
+	DeleteThreshold(ctx context.Context, userID, shopID, thresholdID uint) error
+	GetLowStockReport(ctx context.Context, userID, shopID uint) (entity.LowStockReport, error)
+	FindNearbyPoints(ctx context.Context, filter inventory.NearbyFilter) ([]entity.NearbyShopPoint, error)
+	GetSchedule(ctx context.Context, shopPointID uint) (entity.Schedule, error)
+	SetHours(ctx context.Context, userID uint, setting inventory.HoursSetting) (entity.Schedule, error)
+	SetException(
+		ctx context.Context,
+		userID, shopPointID uint,
+		exception entity.ScheduleException,
+	) (entity.Schedule, error)
+	DeleteException(ctx context.Context, userID, shopPointID uint, date string) error
+}
+
+type inventoryHandler struct {
+	inventoryService InventoryService
+}
+
+func NewInventoryHandler(inventoryService InventoryService) inventoryHandler {
+	return inventoryHandler{inventoryService: inventoryService}
+}
+
+// GetInventory отдает позиции точки продаж
+func (h *inventoryHandler) GetInventory(c *gin.Context) {
+	user, shopPointID, ok := h.parsePointRequest(c)
+	if !ok {
+		return
+	}
+
+	params, ok := parseListParams(c, inventorySorts, "name")
+	if !ok {
+		return
+	}
+
+	items, page, err := h.inventoryService.GetPointInventory(context.Background(), user.ID, shopPointID, params)
+	if err != nil {
+		handleInventoryError(c, err)
+		return
+	}
+
+	writeList(c, items, params, page)
+}
+
+// PutInventoryItem задает цену и остаток варианта товара на точке продаж
+func (h *inventoryHandler) PutInventoryItem(c *gin.Context) {
+	user, shopPointID, ok := h.parsePointRequest(c)
+	if !ok {
+		return
+	}
+
+	variantID, ok := parseVariantID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PutInventoryReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid inventory data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	item, err := h.inventoryService.SetStock(context.Background(), user.ID, req.ConvertToSvc(shopPointID, variantID))
+	if err != nil {
+		handleInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": item})
+}
+
+// PostAdjustment сдвигает остаток варианта на точке продаж с указанием причины
+func (h *inventoryHandler) PostAdjustment(c *gin.Context) {
+	user, shopPointID, ok := h.parsePointRequest(c)
+	if !ok {
+		return
+	}
+
+	variantID, ok := parseVariantID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PostAdjustmentReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid adjustment data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	item, err := h.inventoryService.AdjustStock(context.Background(), user.ID, req.ConvertToSvc(shopPointID, variantID))
+	if err != nil {
+		handleInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": item})
+}
+
+// GetMovements отдает журнал движения остатков точки продаж, variant_id оставляет один вариант
+func (h *inventoryHandler) GetMovements(c *gin.Context) {
+	user, shopPointID, ok := h.parsePointRequest(c)
+	if !ok {
+		return
+	}
+
+	filter := inventory.MovementFilter{ShopPointID: shopPointID}
+	if filter.VariantID, ok = optionalIDQuery(c, "variant_id"); !ok {
+		return
+	}
+
+	params, ok := parseListParams(c, movementSorts, "-created_at")
+	if !ok {
+		return
+	}
+
+	movements, page, err := h.inventoryService.GetMovements(context.Background(), user.ID, filter, params)
+	if err != nil {
+		handleInventoryError(c, err)
+		return
+	}
+
+	writeList(c, movements, params, page)
+}
+
+// parsePointRequest достает пользователя и айди точки продаж из пути.
+// При ошибке ответ уже записан и возвращается false.
+func (h *inventoryHandler) parsePointRequest(c *gin.Context) (entity.User, uint, bool) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return entity.User{}, 0, false
+	}
+
+	shopPointID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid shop point id",
+		})
+		return entity.User{}, 0, false
+	}
+
+	return user, uint(shopPointID), true
+}
+
+func parseVariantID(c *gin.Context) (uint, bool) {
+	variantID, err := strconv.ParseUint(c.Param("variantID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid variant id",
+		})
+		return 0, false
+	}
+
+	return uint(variantID), true
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/handler/dto"
+)
+
+// GetThresholds отдает пороги остатка магазина
+func (h *inventoryHandler) GetThresholds(c *gin.Context) {
+	user, shopID, ok := parseStoreRequest(c)
+	if !ok {
+		return
+	}
+
+	thresholds, err := h.inventoryService.GetThresholds(context.Background(), user.ID, shopID)
+	if err != nil {
+		handleInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": thresholds})
+}
+
+// PutThreshold задает порог остатка для товара или категории
+func (h *inventoryHandler) PutThreshold(c *gin.Context) {
+	user, shopID, ok := parseStoreRequest(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PutThresholdReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid threshold data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	threshold, err := h.inventoryService.SetThreshold(context.Background(), user.ID, req.ConvertToSvc(shopID))
+	if err != nil {
+		handleInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": threshold})
+}
+
+func (h *inventoryHandler) DeleteThreshold(c *gin.Context) {
+	user, shopID, ok := parseStoreRequest(c)
+	if !ok {
+		return
+	}
+
+	thresholdID, err := strconv.ParseUint(c.Param("thresholdID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid threshold id",
+		})
+		return
+	}
+
+	if err := h.inventoryService.DeleteThreshold(context.Background(), user.ID, shopID, uint(thresholdID)); err != nil {
+		handleInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Threshold deleted successfully"})
+}
+
+// GetLowStockReport отдает по точкам продаж позиции, остаток которых не больше порога
+func (h *inventoryHandler) GetLowStockReport(c *gin.Context) {
+	user, shopID, ok := parseStoreRequest(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.inventoryService.GetLowStockReport(context.Background(), user.ID, shopID)
+	if err != nil {
+		handleInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// parseStoreRequest достает пользователя и айди магазина из пути.
+// При ошибке ответ уже записан и возвращается false.
+func parseStoreRequest(c *gin.Context) (entity.User, uint, bool) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return entity.User{}, 0, false
+	}
+
+	shopID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid store id",
+		})
+		return entity.User{}, 0, false
+	}
+
+	return user, uint(shopID), true
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+// AdminOnly пропускает только администраторов.
+// Должен подключаться после AuthMiddleware.
+func AdminOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := c.Get("user")
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"code":    apperror.Unauthorized,
+				"message": "User is not authenticated",
+			})
+			c.Abort()
+			return
+		}
+
+		if u, ok := user.(entity.User); !ok || !u.IsAdmin {
+			c.JSON(http.StatusForbidden, gin.H{
+				"code":    apperror.Forbidden,
+				"message": "Admin rights required",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
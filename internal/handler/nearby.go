@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/inventory"
+	"github.com/zuzaaa-dev/stawberry/pkg/geo"
+)
+
+// GetNearbyPoints ищет точки продаж в радиусе radius километров от lat, lon, ближайшие первыми.
+// product_id и variant_id оставляют точки, где товар есть в наличии.
+func (h *inventoryHandler) GetNearbyPoints(c *gin.Context) {
+	area, ok := parseArea(c)
+	if !ok {
+		return
+	}
+	if area == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "lat and lon are required",
+		})
+		return
+	}
+
+	filter := inventory.NearbyFilter{Area: *area}
+	if filter.ProductID, ok = optionalIDQuery(c, "product_id"); !ok {
+		return
+	}
+	if filter.VariantID, ok = optionalIDQuery(c, "variant_id"); !ok {
+		return
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    apperror.BadRequest,
+				"message": "Invalid limit value",
+			})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	points, err := h.inventoryService.FindNearbyPoints(context.Background(), filter)
+	if err != nil {
+		handleInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": points})
+}
+
+// parseArea разбирает область поиска из параметров lat, lon и radius (в километрах).
+// Без lat и lon возвращает nil. При ошибке ответ уже записан и возвращается false.
+func parseArea(c *gin.Context) (*geo.Circle, bool) {
+	rawLat, rawLon := c.Query("lat"), c.Query("lon")
+	if rawLat == "" && rawLon == "" {
+		return nil, true
+	}
+
+	var area geo.Circle
+	var errLat, errLon, errRadius error
+	area.Center.Lat, errLat = strconv.ParseFloat(rawLat, 64)
+	area.Center.Lon, errLon = strconv.ParseFloat(rawLon, 64)
+	if raw := c.Query("radius"); raw != "" {
+		area.RadiusKm, errRadius = strconv.ParseFloat(raw, 64)
+	}
+	if errLat != nil || errLon != nil || errRadius != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid lat, lon or radius (should be numbers, lat and lon are passed together)",
+		})
+		return nil, false
+	}
+
+	return &area, true
+}
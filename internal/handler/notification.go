@@ -1,16 +1,17 @@
 package handler
 
 import (
-	"math"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
 	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
 )
 
 type NotificationService interface {
-	GetNotification(id string, offset int, limit int) ([]entity.Notification, int, error)
+	GetNotification(id string, params pagination.Params) ([]entity.Notification, pagination.Page, error)
 }
 
 type notificationHandler struct {
@@ -24,50 +25,25 @@ func NewNotificationHandler(notificationService NotificationService) notificatio
 // GetNotification обработчик уведомлений
 // получает все уведомления (одобрение или неодобрение заявки) авторизированного пользователя
 func (h *notificationHandler) GetNotification(c *gin.Context) {
-	// сделать user, ok := c.Get("user")
-	// сейчас в контекст кладется userID, в будущем структура user
-	userID, ok := c.Get("userID")
+	user, ok := getUserFromContext(c)
 	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid userID from context"})
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
 		return
 	}
 
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page number"})
-		return
-	}
-
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if err != nil || limit < 1 || limit > 100 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit value"})
-		return
-	}
-
-	offset := (page - 1) * limit
-
-	// исправить вызов h.offerService.GetNotification()
-	// сейчас в контекст кладется userID, в будущем структура user
-	uid, ok := userID.(uint)
+	params, ok := parseListParams(c, notificationSorts, "-created_at")
 	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
 		return
 	}
-	notifications, total, err := h.offerService.GetNotification(strconv.FormatUint(uint64(uid), 10), offset, limit)
+
+	notifications, page, err := h.offerService.GetNotification(strconv.FormatUint(uint64(user.ID), 10), params)
 	if err != nil {
 		handleNotificationError(c, err)
 		return
 	}
 
-	totalPages := int(math.Ceil(float64(total) / float64(limit)))
-
-	c.JSON(http.StatusOK, gin.H{
-		"data": notifications,
-		"meta": gin.H{
-			"current_page": page,
-			"per_page":     limit,
-			"total_items":  total,
-			"total_pages":  totalPages,
-		},
-	})
+	writeList(c, notifications, params, page)
 }
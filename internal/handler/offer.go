@@ -2,24 +2,25 @@ package handler
 
 import (
 	"context"
-	"math"
 	"net/http"
 	"strconv"
-	"time"
 
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
 	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
 	"github.com/zuzaaa-dev/stawberry/internal/domain/service/offer"
 
 	"github.com/gin-gonic/gin"
 	"github.com/zuzaaa-dev/stawberry/internal/handler/dto"
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
 )
 
 type OfferService interface {
 	CreateOffer(ctx context.Context, offer offer.Offer) (uint, error)
-	GetUserOffers(ctx context.Context, userID uint, limit, offset int) ([]entity.Offer, int64, error)
-	GetOffer(ctx context.Context, offerID uint) (entity.Offer, error)
-	UpdateOfferStatus(ctx context.Context, offerID uint, status string) (entity.Offer, error)
-	DeleteOffer(ctx context.Context, offerID uint) (entity.Offer, error)
+	GetUserOffers(ctx context.Context, userID uint, params pagination.Params) ([]entity.Offer, pagination.Page, error)
+	GetOffer(ctx context.Context, userID, offerID uint) (entity.Offer, error)
+	UpdateOfferStatus(ctx context.Context, userID, offerID uint, status string) (entity.Offer, error)
+	RateOffer(ctx context.Context, userID, offerID uint, rating int) (entity.Offer, error)
+	DeleteOffer(ctx context.Context, userID, offerID uint) (entity.Offer, error)
 }
 
 type offerHandler struct {
@@ -31,7 +32,14 @@ func NewOfferHandler(offerService OfferService) offerHandler {
 }
 
 func (h *offerHandler) PostOffer(c *gin.Context) {
-	userID, _ := c.Get("userID")
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
 
 	var offer dto.PostOfferReq
 	if err := c.ShouldBindJSON(&offer); err != nil {
@@ -39,13 +47,18 @@ func (h *offerHandler) PostOffer(c *gin.Context) {
 		return
 	}
 
-	offer.UserID = userID.(uint)
-	offer.Status = "pending"
-	offer.ExpiresAt = time.Now().Add(24 * time.Hour)
+	offer.UserID = user.ID
+	offer.Status = entity.OfferStatusPending
 
-	var response dto.PostOfferResp
-	var err error
-	if response.ID, err = h.offerService.CreateOffer(context.Background(), offer.ConvertToSvc()); err != nil {
+	offerID, err := h.offerService.CreateOffer(context.Background(), offer.ConvertToSvc())
+	if err != nil {
+		handleOfferError(c, err)
+		return
+	}
+
+	// срок действия считается сервисом, поэтому в ответ отдается созданное предложение
+	created, err := h.offerService.GetOffer(context.Background(), user.ID, offerID)
+	if err != nil {
 		handleOfferError(c, err)
 		return
 	}
@@ -58,57 +71,50 @@ func (h *offerHandler) PostOffer(c *gin.Context) {
 	// }
 	// h.notifyRepo.Create(&notification)
 
-	c.JSON(http.StatusCreated, offer)
+	c.JSON(http.StatusCreated, created)
 }
 
 func (h *offerHandler) GetUserOffers(c *gin.Context) {
-	userID, ok := c.Get("userID")
+	user, ok := getUserFromContext(c)
 	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UserID"})
-		return
-	}
-
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page number"})
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
 		return
 	}
 
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if err != nil || limit < 1 || limit > 100 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit value"})
+	params, ok := parseListParams(c, offerSorts, "-created_at")
+	if !ok {
 		return
 	}
 
-	offset := (page - 1) * limit
-
-	offers, total, err := h.offerService.GetUserOffers(context.Background(), userID.(uint), offset, limit)
+	offers, page, err := h.offerService.GetUserOffers(context.Background(), user.ID, params)
 	if err != nil {
 		handleOfferError(c, err)
 		return
 	}
 
-	totalPages := int(math.Ceil(float64(total) / float64(limit)))
-
-	c.JSON(http.StatusOK, gin.H{
-		"data": offers,
-		"meta": gin.H{
-			"current_page": page,
-			"per_page":     limit,
-			"total_items":  total,
-			"total_pages":  totalPages,
-		},
-	})
+	writeList(c, offers, params, page)
 }
 
 func (h *offerHandler) GetOffer(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid non digit offer id"})
 		return
 	}
 
-	offer, err := h.offerService.GetOffer(context.Background(), uint(id))
+	offer, err := h.offerService.GetOffer(context.Background(), user.ID, uint(id))
 	if err != nil {
 		handleOfferError(c, err)
 		return
@@ -120,6 +126,15 @@ func (h *offerHandler) GetOffer(c *gin.Context) {
 }
 
 func (h *offerHandler) PatchOfferStatus(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid nondigit offer id"})
@@ -133,7 +148,7 @@ func (h *offerHandler) PatchOfferStatus(c *gin.Context) {
 		return
 	}
 
-	offer, err := h.offerService.UpdateOfferStatus(context.Background(), uint(id), req.Status)
+	offer, err := h.offerService.UpdateOfferStatus(context.Background(), user.ID, uint(id), req.Status)
 	if err != nil {
 		handleOfferError(c, err)
 		return
@@ -150,16 +165,65 @@ func (h *offerHandler) PatchOfferStatus(c *gin.Context) {
 	c.JSON(http.StatusCreated, offer)
 }
 
+// PutOfferRating записывает оценку магазина покупателем после принятого предложения
+func (h *offerHandler) PutOfferRating(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid offer id",
+		})
+		return
+	}
+
+	var req dto.PutOfferRatingReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid rating",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	offer, err := h.offerService.RateOffer(context.Background(), user.ID, uint(id), req.Rating)
+	if err != nil {
+		handleOfferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": offer})
+}
+
 func (h *offerHandler) DeleteOffer(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid nondigit offer id"})
 		return
 	}
 
-	offer, err := h.offerService.DeleteOffer(context.Background(), uint(id))
+	offer, err := h.offerService.DeleteOffer(context.Background(), user.ID, uint(id))
 	if err != nil {
 		handleOfferError(c, err)
+		return
 	}
 
 	// Create notification for store
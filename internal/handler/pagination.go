@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
+)
+
+const (
+	defaultPageLimit = 10
+	maxPageLimit     = 100
+)
+
+// Поля сортировки списков. Минус перед полем в параметре sort задает обратный порядок.
+var (
+	productSorts      = []string{"created_at", "name", "price"}
+	offerSorts        = []string{"created_at", "price", "status"}
+	notificationSorts = []string{"created_at"}
+	inventorySorts    = []string{"name", "quantity", "price"}
+	movementSorts     = []string{"created_at"}
+	storeSorts        = []string{"created_at", "name"}
+)
+
+// parseListParams разбирает параметры списка: limit, sort и либо page, либо cursor.
+// Курсор хранит сортировку, с которой он получен, поэтому sort с ним можно не передавать.
+// При ошибке ответ уже записан и возвращается false.
+func parseListParams(c *gin.Context, sorts []string, defaultSort string) (pagination.Params, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPageLimit)))
+	if err != nil || limit < 1 || limit > maxPageLimit {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid limit value (should be between 1 and 100)",
+		})
+		return pagination.Params{}, false
+	}
+	params := pagination.Params{Limit: limit}
+
+	sort := c.Query("sort")
+	if sort != "" {
+		params.Sort, params.Desc = strings.CutPrefix(sort, "-")
+		if !slices.Contains(sorts, params.Sort) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    apperror.BadRequest,
+				"message": "Invalid sort field (should be one of " + strings.Join(sorts, ", ") + ")",
+			})
+			return pagination.Params{}, false
+		}
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		if _, ok := c.GetQuery("page"); ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    apperror.BadRequest,
+				"message": "Page and cursor cannot be used together",
+			})
+			return pagination.Params{}, false
+		}
+
+		cursor, err := pagination.DecodeCursor(raw)
+		if err != nil || !slices.Contains(sorts, cursor.Sort) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    apperror.BadRequest,
+				"message": "Invalid cursor",
+			})
+			return pagination.Params{}, false
+		}
+		if sort != "" && (cursor.Sort != params.Sort || cursor.Desc != params.Desc) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    apperror.BadRequest,
+				"message": "Sort does not match the cursor",
+			})
+			return pagination.Params{}, false
+		}
+
+		params.Sort, params.Desc = cursor.Sort, cursor.Desc
+		params.Cursor = &cursor
+		return params, true
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid page number",
+		})
+		return pagination.Params{}, false
+	}
+	params.Offset = (page - 1) * limit
+
+	if sort == "" {
+		params.Sort, params.Desc = strings.CutPrefix(defaultSort, "-")
+	}
+
+	return params, true
+}
+
+// writeList отвечает общим для всех списков конвертом и ставит заголовок Link
+// со ссылками на следующую и предыдущую страницы.
+func writeList(c *gin.Context, data any, params pagination.Params, page pagination.Page) {
+	sort := params.Sort
+	if params.Desc {
+		sort = "-" + sort
+	}
+
+	meta := gin.H{
+		"per_page": params.Limit,
+		"sort":     sort,
+	}
+	if page.Next != nil {
+		meta["next_cursor"] = page.Next.Encode()
+	}
+	if page.Prev != nil {
+		meta["prev_cursor"] = page.Prev.Encode()
+	}
+
+	var links []string
+	if params.Keyset() {
+		if page.Next != nil {
+			links = append(links, pageLink(c, "next", "cursor", page.Next.Encode()))
+		}
+		if page.Prev != nil {
+			links = append(links, pageLink(c, "prev", "cursor", page.Prev.Encode()))
+		}
+	} else {
+		currentPage := params.Offset/params.Limit + 1
+		totalPages := 0
+		if page.Total != nil {
+			totalPages = int(math.Ceil(float64(*page.Total) / float64(params.Limit)))
+			meta["total_items"] = *page.Total
+		}
+		meta["current_page"] = currentPage
+		meta["total_pages"] = totalPages
+
+		if currentPage < totalPages {
+			links = append(links, pageLink(c, "next", "page", strconv.Itoa(currentPage+1)))
+		}
+		if currentPage > 1 {
+			links = append(links, pageLink(c, "prev", "page", strconv.Itoa(min(currentPage-1, max(totalPages, 1)))))
+		}
+	}
+
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": data,
+		"meta": meta,
+	})
+}
+
+// pageLink строит ссылку на соседнюю страницу из адреса текущего запроса.
+// Ссылка с курсором не содержит page и sort: сортировка уже записана в курсоре.
+func pageLink(c *gin.Context, rel, param, value string) string {
+	u := *c.Request.URL
+	query := u.Query()
+	query.Del("page")
+	query.Del("cursor")
+	if param == "cursor" {
+		query.Del("sort")
+	}
+	query.Set(param, value)
+	u.RawQuery = query.Encode()
+
+	return "<" + u.String() + `>; rel="` + rel + `"`
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/pricehistory"
+)
+
+type PriceHistoryService interface {
+	GetPriceHistory(ctx context.Context, filter pricehistory.Filter, windows []int) (entity.PriceHistory, error)
+}
+
+type priceHistoryHandler struct {
+	priceHistoryService PriceHistoryService
+}
+
+func NewPriceHistoryHandler(priceHistoryService PriceHistoryService) priceHistoryHandler {
+	return priceHistoryHandler{priceHistoryService: priceHistoryService}
+}
+
+// GetPriceHistory отдает статистику цен товара за окна из параметра windows
+// (дни через запятую) и историю изменений за самое длинное окно
+func (h *priceHistoryHandler) GetPriceHistory(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid product id",
+		})
+		return
+	}
+	filter := pricehistory.Filter{ProductID: uint(productID)}
+
+	var windows []int
+	if raw := c.Query("windows"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			days, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"code":    apperror.BadRequest,
+					"message": "Invalid windows value (should be a comma-separated list of days)",
+				})
+				return
+			}
+			windows = append(windows, days)
+		}
+	}
+
+	var ok bool
+	if filter.VariantID, ok = optionalIDQuery(c, "variant_id"); !ok {
+		return
+	}
+	if filter.ShopPointID, ok = optionalIDQuery(c, "shop_point_id"); !ok {
+		return
+	}
+
+	history, err := h.priceHistoryService.GetPriceHistory(context.Background(), filter, windows)
+	if err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": history})
+}
+
+// optionalIDQuery разбирает необязательный айди из параметра запроса.
+// При ошибке ответ уже записан и возвращается false.
+func optionalIDQuery(c *gin.Context, name string) (*uint, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, true
+	}
+
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid " + name,
+		})
+		return nil, false
+	}
+
+	value := uint(id)
+	return &value, true
+}
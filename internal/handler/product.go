@@ -2,7 +2,6 @@ package handler
 
 import (
 	"context"
-	"math"
 	"net/http"
 	"strconv"
 
@@ -13,14 +12,30 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/zuzaaa-dev/stawberry/internal/handler/dto"
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
 )
 
 type ProductService interface {
-	CreateProduct(ctx context.Context, product product.Product) (uint, error)
+	CreateProduct(ctx context.Context, userID uint, product product.Product) (uint, error)
 	GetProductByID(ctx context.Context, id string) (entity.Product, error)
-	GetProducts(ctx context.Context, offset, limit int) ([]entity.Product, int, error)
-	GetStoreProducts(ctx context.Context, id string, offset, limit int) ([]entity.Product, int, error)
-	UpdateProduct(ctx context.Context, id string, updateProduct product.UpdateProduct) error
+	GetProducts(
+		ctx context.Context,
+		filter product.Filter,
+		params pagination.Params,
+	) ([]entity.Product, pagination.Page, error)
+	GetStoreProducts(ctx context.Context, id string, params pagination.Params) ([]entity.Product, pagination.Page, error)
+	UpdateProduct(ctx context.Context, userID uint, id string, updateProduct product.UpdateProduct) error
+	ArchiveProduct(ctx context.Context, userID, id uint) error
+	RestoreProduct(ctx context.Context, userID, id uint) error
+	DeleteProduct(ctx context.Context, userID, id uint) error
+	GetProductVariants(ctx context.Context, productID uint) ([]entity.ProductVariant, error)
+	CreateVariant(ctx context.Context, userID uint, variant product.Variant) (entity.ProductVariant, error)
+	UpdateVariant(
+		ctx context.Context,
+		userID, productID, variantID uint,
+		update product.UpdateVariant,
+	) (entity.ProductVariant, error)
+	DeleteVariant(ctx context.Context, userID, productID, variantID uint) error
 }
 
 type productHandler struct {
@@ -32,6 +47,15 @@ func NewProductHandler(productService ProductService) productHandler {
 }
 
 func (h *productHandler) PostProduct(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
 	var postProductReq dto.PostProductReq
 
 	if err := c.ShouldBindJSON(&postProductReq); err != nil {
@@ -45,7 +69,8 @@ func (h *productHandler) PostProduct(c *gin.Context) {
 
 	var response dto.PostProductResp
 	var err error
-	if response.ID, err = h.productService.CreateProduct(context.Background(), postProductReq.ConvertToSvc()); err != nil {
+	response.ID, err = h.productService.CreateProduct(context.Background(), user.ID, postProductReq.ConvertToSvc())
+	if err != nil {
 		handleProductError(c, err)
 		return
 	}
@@ -65,89 +90,73 @@ func (h *productHandler) GetProduct(c *gin.Context) {
 	c.JSON(http.StatusOK, product)
 }
 
+// GetProducts отдает каталог. in_stock=true оставляет товары в наличии,
+// shop_id и shop_point_id — товары в наличии в магазине или на точке продаж,
+// lat, lon и radius — в наличии на точках поблизости.
 func (h *productHandler) GetProducts(c *gin.Context) {
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    apperror.BadRequest,
-			"message": "Invalid page number",
-		})
+	params, ok := parseListParams(c, productSorts, "-created_at")
+	if !ok {
 		return
 	}
 
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if err != nil || limit < 1 || limit > 100 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    apperror.BadRequest,
-			"message": "Invalid limit value (should be between 1 and 100)",
-		})
+	var filter product.Filter
+	if raw := c.Query("in_stock"); raw != "" {
+		inStock, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    apperror.BadRequest,
+				"message": "Invalid in_stock value",
+			})
+			return
+		}
+		filter.InStock = inStock
+	}
+	if filter.ShopID, ok = optionalIDQuery(c, "shop_id"); !ok {
+		return
+	}
+	if filter.ShopPointID, ok = optionalIDQuery(c, "shop_point_id"); !ok {
+		return
+	}
+	if filter.Near, ok = parseArea(c); !ok {
 		return
 	}
 
-	offset := (page - 1) * limit
-
-	products, total, err := h.productService.GetProducts(context.Background(), offset, limit)
+	products, page, err := h.productService.GetProducts(context.Background(), filter, params)
 	if err != nil {
 		handleProductError(c, err)
 		return
 	}
 
-	totalPages := int(math.Ceil(float64(total) / float64(limit)))
-
-	c.JSON(http.StatusOK, gin.H{
-		"data": products,
-		"meta": gin.H{
-			"current_page": page,
-			"per_page":     limit,
-			"total_items":  total,
-			"total_pages":  totalPages,
-		},
-	})
+	writeList(c, products, params, page)
 }
 
 func (h *productHandler) GetStoreProducts(c *gin.Context) {
 	id := c.Param("id")
 
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    apperror.BadRequest,
-			"message": "Invalid page number",
-		})
-		return
-	}
-
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if err != nil || limit < 1 || limit > 100 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    apperror.BadRequest,
-			"message": "Invalid limit value (should be between 1 and 100)",
-		})
+	params, ok := parseListParams(c, productSorts, "-created_at")
+	if !ok {
 		return
 	}
 
-	offset := (page - 1) * limit
-
-	products, total, err := h.productService.GetStoreProducts(context.Background(), id, offset, limit)
+	products, page, err := h.productService.GetStoreProducts(context.Background(), id, params)
 	if err != nil {
 		handleProductError(c, err)
 		return
 	}
 
-	totalPages := int(math.Ceil(float64(total) / float64(limit)))
-
-	c.JSON(http.StatusOK, gin.H{
-		"data": products,
-		"meta": gin.H{
-			"current_page": page,
-			"per_page":     limit,
-			"total_items":  total,
-			"total_pages":  totalPages,
-		},
-	})
+	writeList(c, products, params, page)
 }
 
 func (h *productHandler) PatchProduct(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
 	id := c.Param("id")
 
 	var update dto.PatchProductReq
@@ -160,10 +169,56 @@ func (h *productHandler) PatchProduct(c *gin.Context) {
 		return
 	}
 
-	if err := h.productService.UpdateProduct(context.Background(), id, update.ConvertToSvc()); err != nil {
+	if err := h.productService.UpdateProduct(context.Background(), user.ID, id, update.ConvertToSvc()); err != nil {
 		handleProductError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Product updated successfully"})
 }
+
+// ArchiveProduct скрывает товар из каталога
+func (h *productHandler) ArchiveProduct(c *gin.Context) {
+	h.changeProductState(c, h.productService.ArchiveProduct, "Product archived successfully")
+}
+
+// RestoreProduct возвращает архивный товар в каталог
+func (h *productHandler) RestoreProduct(c *gin.Context) {
+	h.changeProductState(c, h.productService.RestoreProduct, "Product restored successfully")
+}
+
+// DeleteProduct удаляет товар без возможности восстановления
+func (h *productHandler) DeleteProduct(c *gin.Context) {
+	h.changeProductState(c, h.productService.DeleteProduct, "Product deleted successfully")
+}
+
+func (h *productHandler) changeProductState(
+	c *gin.Context,
+	change func(ctx context.Context, userID, id uint) error,
+	message string,
+) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid product id",
+		})
+		return
+	}
+
+	if err := change(context.Background(), user.ID, uint(id)); err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/handler/dto"
+)
+
+// GetSchedule отдает расписание точки продаж, работает ли она сейчас и когда откроется
+func (h *inventoryHandler) GetSchedule(c *gin.Context) {
+	shopPointID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid shop point id",
+		})
+		return
+	}
+
+	schedule, err := h.inventoryService.GetSchedule(context.Background(), uint(shopPointID))
+	if err != nil {
+		handleInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": schedule})
+}
+
+// PutHours заменяет часовой пояс и недельное расписание точки продаж
+func (h *inventoryHandler) PutHours(c *gin.Context) {
+	user, shopPointID, ok := h.parsePointRequest(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PutHoursReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid opening hours",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	schedule, err := h.inventoryService.SetHours(context.Background(), user.ID, req.ConvertToSvc(shopPointID))
+	if err != nil {
+		handleInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": schedule})
+}
+
+// PutException задает особый день точки продаж на дату из пути
+func (h *inventoryHandler) PutException(c *gin.Context) {
+	user, shopPointID, ok := h.parsePointRequest(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PutExceptionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid special hours",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	schedule, err := h.inventoryService.SetException(
+		context.Background(),
+		user.ID,
+		shopPointID,
+		req.ConvertToSvc(c.Param("date")),
+	)
+	if err != nil {
+		handleInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": schedule})
+}
+
+func (h *inventoryHandler) DeleteException(c *gin.Context) {
+	user, shopPointID, ok := h.parsePointRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.inventoryService.DeleteException(
+		context.Background(),
+		user.ID,
+		shopPointID,
+		c.Param("date"),
+	); err != nil {
+		handleInventoryError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/handler/dto"
+)
+
+// GetSettings отдает правила торга магазина его владельцу
+func (h *storeHandler) GetSettings(c *gin.Context) {
+	user, storeID, ok := h.parseStoreRequest(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.storeService.GetStoreSettings(context.Background(), user.ID, storeID)
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": settings})
+}
+
+func (h *storeHandler) PutSettings(c *gin.Context) {
+	user, storeID, ok := h.parseStoreRequest(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PutStoreSettingsReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid store settings",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	settings, err := h.storeService.UpdateStoreSettings(context.Background(), user.ID, storeID, req.ConvertToSvc())
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": settings})
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/handler/dto"
+)
+
+type StaffService interface {
+	GetMembers(ctx context.Context, userID, storeID uint) ([]entity.StoreMember, error)
+	InviteMember(ctx context.Context, userID, storeID uint, email, role string) (entity.StoreInvitation, error)
+	GetInvitations(ctx context.Context, userID, storeID uint) ([]entity.StoreInvitation, error)
+	RevokeInvitation(ctx context.Context, userID, storeID, invitationID uint) error
+	AcceptInvitation(ctx context.Context, user entity.User, token string) (entity.StoreMember, error)
+	RemoveMember(ctx context.Context, userID, storeID, memberID uint) error
+}
+
+type staffHandler struct {
+	staffService StaffService
+}
+
+func NewStaffHandler(staffService StaffService) staffHandler {
+	return staffHandler{staffService: staffService}
+}
+
+// GetMembers отдает сотрудников магазина с их ролями
+func (h *staffHandler) GetMembers(c *gin.Context) {
+	user, storeID, ok := parseStaffRequest(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.staffService.GetMembers(context.Background(), user.ID, storeID)
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": members})
+}
+
+// DeleteMember убирает сотрудника из магазина, в том числе когда сотрудник уходит сам
+func (h *staffHandler) DeleteMember(c *gin.Context) {
+	user, storeID, ok := parseStaffRequest(c)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseUint(c.Param("userID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid user id",
+		})
+		return
+	}
+
+	if err := h.staffService.RemoveMember(context.Background(), user.ID, storeID, uint(memberID)); err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *staffHandler) GetInvitations(c *gin.Context) {
+	user, storeID, ok := parseStaffRequest(c)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.staffService.GetInvitations(context.Background(), user.ID, storeID)
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": invitations})
+}
+
+// PostInvitation отправляет приглашение в сотрудники на почту
+func (h *staffHandler) PostInvitation(c *gin.Context) {
+	user, storeID, ok := parseStaffRequest(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PostInvitationReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid invitation data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	invitation, err := h.staffService.InviteMember(context.Background(), user.ID, storeID, req.Email, req.Role)
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": invitation})
+}
+
+func (h *staffHandler) DeleteInvitation(c *gin.Context) {
+	user, storeID, ok := parseStaffRequest(c)
+	if !ok {
+		return
+	}
+
+	invitationID, err := strconv.ParseUint(c.Param("invitationID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid invitation id",
+		})
+		return
+	}
+
+	if err := h.staffService.RevokeInvitation(
+		context.Background(),
+		user.ID,
+		storeID,
+		uint(invitationID),
+	); err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PostAcceptInvitation принимает приглашение по токену из письма
+func (h *staffHandler) PostAcceptInvitation(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	var req dto.PostAcceptInvitationReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid invitation token",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	member, err := h.staffService.AcceptInvitation(context.Background(), user, req.Token)
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": member})
+}
+
+// parseStaffRequest достает пользователя и айди магазина из пути.
+// При ошибке ответ уже записан и возвращается false.
+func parseStaffRequest(c *gin.Context) (entity.User, uint, bool) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return entity.User{}, 0, false
+	}
+
+	storeID, ok := parseStoreID(c)
+	if !ok {
+		return entity.User{}, 0, false
+	}
+
+	return user, storeID, true
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/pkg/objectstorage"
+)
+
+// SignedObjectStorage хранилище, объекты которого раздает само приложение
+type SignedObjectStorage interface {
+	GetObject(ctx context.Context, objectKey string) (io.ReadCloser, objectstorage.ObjectInfo, error)
+	PutObject(ctx context.Context, objectKey string, body io.Reader, size int64, contentType string) error
+	VerifySignature(method, objectKey, contentType string, contentLength int64, expires, signature string) error
+}
+
+type storageHandler struct {
+	storage SignedObjectStorage
+}
+
+// NewStorageHandler создает обработчик для локального хранилища.
+// Если storage равен nil, маршруты хранилища не регистрируются.
+func NewStorageHandler(storage SignedObjectStorage) storageHandler {
+	return storageHandler{storage: storage}
+}
+
+// publicObjectPrefix префикс изображений товаров, которые каталог отдает по прямым ссылкам
+const publicObjectPrefix = "products/"
+
+// GetObject отдает объект. Без подписи доступны только изображения товаров,
+// документы магазинов, файлы импорта и прочие объекты отдаются лишь по подписанной ссылке.
+func (h *storageHandler) GetObject(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	signature := c.Query("signature")
+	if signature != "" || !isPublicObject(key) {
+		err := h.storage.VerifySignature(http.MethodGet, key, "", 0, c.Query("expires"), signature)
+		if err != nil {
+			c.JSON(http.StatusForbidden, gin.H{
+				"code":    apperror.Forbidden,
+				"message": "Invalid or expired signature",
+			})
+			return
+		}
+	}
+
+	body, info, err := h.storage.GetObject(context.Background(), key)
+	if err != nil {
+		handleStorageError(c, err)
+		return
+	}
+	defer body.Close()
+
+	c.Header("ETag", info.ETag)
+	c.DataFromReader(http.StatusOK, info.Size, info.ContentType, body, nil)
+}
+
+// PutObject принимает загрузку по ссылке, выданной PresignPutObject
+func (h *storageHandler) PutObject(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	contentType := c.GetHeader("Content-Type")
+	contentLength, err := strconv.ParseInt(c.GetHeader("Content-Length"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusLengthRequired, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Content-Length header is required",
+		})
+		return
+	}
+
+	err = h.storage.VerifySignature(
+		http.MethodPut,
+		key,
+		contentType,
+		contentLength,
+		c.Query("expires"),
+		c.Query("signature"),
+	)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"code":    apperror.Forbidden,
+			"message": "Invalid or expired signature",
+		})
+		return
+	}
+
+	if err := h.storage.PutObject(
+		context.Background(),
+		key,
+		c.Request.Body,
+		contentLength,
+		contentType,
+	); err != nil {
+		handleStorageError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// isPublicObject проверяет, что объект можно отдать без подписи.
+// Ключи с переходом по каталогам публичными не считаются.
+func isPublicObject(key string) bool {
+	return strings.HasPrefix(key, publicObjectPrefix) && !strings.Contains(key, "..")
+}
+
+func handleStorageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, objectstorage.ErrObjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    apperror.NotFound,
+			"message": "object not found",
+		})
+	case errors.Is(err, objectstorage.ErrInvalidKey):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "invalid object key",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    apperror.InternalError,
+			"message": "An unexpected error occurred",
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/store"
+	"github.com/zuzaaa-dev/stawberry/internal/handler/dto"
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
+)
+
+type StoreService interface {
+	CreateStore(ctx context.Context, user entity.User, store store.Store) (entity.Store, error)
+	GetStore(ctx context.Context, storeID uint) (entity.Store, error)
+	GetStoreProfile(ctx context.Context, storeID uint) (entity.StoreProfile, error)
+	GetStoreSettings(ctx context.Context, userID, storeID uint) (entity.StoreSettings, error)
+	UpdateStoreSettings(
+		ctx context.Context,
+		userID, storeID uint,
+		settings store.Settings,
+	) (entity.StoreSettings, error)
+	GetStores(ctx context.Context, filter store.Filter, params pagination.Params) ([]entity.Store, pagination.Page, error)
+	UpdateStore(ctx context.Context, userID, storeID uint, update store.UpdateStore) (entity.Store, error)
+	GetShopPoints(ctx context.Context, storeID uint) ([]entity.ShopPoint, error)
+	CreateShopPoint(ctx context.Context, userID uint, point store.ShopPoint) (entity.ShopPoint, error)
+	UpdateShopPoint(
+		ctx context.Context,
+		userID, shopPointID uint,
+		update store.UpdateShopPoint,
+	) (entity.ShopPoint, error)
+	DeleteShopPoint(ctx context.Context, userID, shopPointID uint) error
+	GetUserStores(ctx context.Context, userID uint, params pagination.Params) ([]entity.Store, pagination.Page, error)
+	GetStoresForReview(
+		ctx context.Context,
+		status string,
+		params pagination.Params,
+	) ([]entity.Store, pagination.Page, error)
+	GetVerification(ctx context.Context, user entity.User, storeID uint) (entity.StoreVerification, error)
+	UpdateLegalDetails(
+		ctx context.Context,
+		userID, storeID uint,
+		details store.LegalDetails,
+	) (entity.StoreVerification, error)
+	CreateDocumentUpload(
+		ctx context.Context,
+		userID, storeID uint,
+		contentType string,
+		size int64,
+	) (entity.DocumentUpload, error)
+	AddDocument(ctx context.Context, userID, storeID uint, kind, key string) (entity.StoreDocument, error)
+	DeleteDocument(ctx context.Context, userID, storeID, documentID uint) error
+	SubmitVerification(ctx context.Context, userID, storeID uint) (entity.StoreVerification, error)
+	ReviewVerification(ctx context.Context, storeID uint, review store.Review) (entity.StoreVerification, error)
+}
+
+type storeHandler struct {
+	storeService StoreService
+}
+
+func NewStoreHandler(storeService StoreService) storeHandler {
+	return storeHandler{storeService: storeService}
+}
+
+// GetStores отдает список магазинов, user_id оставляет магазины одного владельца
+func (h *storeHandler) GetStores(c *gin.Context) {
+	params, ok := parseListParams(c, storeSorts, "name")
+	if !ok {
+		return
+	}
+
+	var filter store.Filter
+	if filter.UserID, ok = optionalIDQuery(c, "user_id"); !ok {
+		return
+	}
+
+	stores, page, err := h.storeService.GetStores(context.Background(), filter, params)
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	writeList(c, stores, params, page)
+}
+
+// GetMyStores отдает магазины пользователя на любом этапе проверки
+func (h *storeHandler) GetMyStores(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	params, ok := parseListParams(c, storeSorts, "name")
+	if !ok {
+		return
+	}
+
+	stores, page, err := h.storeService.GetUserStores(context.Background(), user.ID, params)
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	writeList(c, stores, params, page)
+}
+
+// GetStore отдает магазин вместе с точками продаж
+func (h *storeHandler) GetStore(c *gin.Context) {
+	storeID, ok := parseStoreID(c)
+	if !ok {
+		return
+	}
+
+	store, err := h.storeService.GetStore(context.Background(), storeID)
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": store})
+}
+
+// GetStoreProfile отдает покупателям профиль магазина со статистикой по предложениям
+func (h *storeHandler) GetStoreProfile(c *gin.Context) {
+	storeID, ok := parseStoreID(c)
+	if !ok {
+		return
+	}
+
+	profile, err := h.storeService.GetStoreProfile(context.Background(), storeID)
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": profile})
+}
+
+func (h *storeHandler) PostStore(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	var req dto.PostStoreReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid store data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	store, err := h.storeService.CreateStore(context.Background(), user, req.ConvertToSvc())
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": store})
+}
+
+func (h *storeHandler) PatchStore(c *gin.Context) {
+	user, storeID, ok := h.parseStoreRequest(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PatchStoreReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid store data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	store, err := h.storeService.UpdateStore(context.Background(), user.ID, storeID, req.ConvertToSvc())
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": store})
+}
+
+// GetShopPoints отдает точки продаж магазина с состоянием работы
+func (h *storeHandler) GetShopPoints(c *gin.Context) {
+	storeID, ok := parseStoreID(c)
+	if !ok {
+		return
+	}
+
+	points, err := h.storeService.GetShopPoints(context.Background(), storeID)
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": points})
+}
+
+func (h *storeHandler) PostShopPoint(c *gin.Context) {
+	user, storeID, ok := h.parseStoreRequest(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PostShopPointReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid shop point data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	point, err := h.storeService.CreateShopPoint(context.Background(), user.ID, req.ConvertToSvc(storeID))
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": point})
+}
+
+func (h *storeHandler) PatchShopPoint(c *gin.Context) {
+	user, shopPointID, ok := h.parseShopPointRequest(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PatchShopPointReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid shop point data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	point, err := h.storeService.UpdateShopPoint(context.Background(), user.ID, shopPointID, req.ConvertToSvc())
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": point})
+}
+
+// DeleteShopPoint удаляет точку продаж, на которой не осталось товара
+func (h *storeHandler) DeleteShopPoint(c *gin.Context) {
+	user, shopPointID, ok := h.parseShopPointRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.storeService.DeleteShopPoint(context.Background(), user.ID, shopPointID); err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseStoreID разбирает айди магазина из пути.
+// При ошибке ответ уже записан и возвращается false.
+func parseStoreID(c *gin.Context) (uint, bool) {
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid store id",
+		})
+		return 0, false
+	}
+
+	return uint(storeID), true
+}
+
+// parseStoreRequest достает пользователя и айди магазина из пути.
+// При ошибке ответ уже записан и возвращается false.
+func (h *storeHandler) parseStoreRequest(c *gin.Context) (entity.User, uint, bool) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return entity.User{}, 0, false
+	}
+
+	storeID, ok := parseStoreID(c)
+	if !ok {
+		return entity.User{}, 0, false
+	}
+
+	return user, storeID, true
+}
+
+// parseShopPointRequest достает пользователя и айди точки продаж из пути.
+// При ошибке ответ уже записан и возвращается false.
+func (h *storeHandler) parseShopPointRequest(c *gin.Context) (entity.User, uint, bool) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return entity.User{}, 0, false
+	}
+
+	shopPointID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid shop point id",
+		})
+		return entity.User{}, 0, false
+	}
+
+	return user, uint(shopPointID), true
+}
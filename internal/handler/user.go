@@ -18,7 +18,9 @@ type UserService interface {
 	Refresh(ctx context.Context, refreshToken, fingerprint string) (string, string, error)
 	Logout(ctx context.Context, refreshToken, fingerprint string) error
 	GetUserByID(ctx context.Context, id uint) (entity.User, error)
-	UpdateUser(ctx context.Context, id uint, updateUser user.UpdateUser) error
+	UpdateUser(ctx context.Context, id uint, updateUser user.UpdateUser) (entity.User, error)
+	ResendVerification(ctx context.Context, id uint) error
+	VerifyEmail(ctx context.Context, token string) (entity.User, error)
 }
 
 type userHandler struct {
@@ -184,6 +186,78 @@ func (h *userHandler) Logout(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
+// PatchMe меняет профиль текущего пользователя. После смены пароля refresh токены
+// отзываются, и пользователю нужно войти заново.
+func (h *userHandler) PatchMe(c *gin.Context) {
+	current, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	var req dto.PatchUserReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid user data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	updated, err := h.userService.UpdateUser(context.Background(), current.ID, req.ConvertToSvc())
+	if err != nil {
+		handleUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": updated})
+}
+
+// PostResendVerification отправляет текущему пользователю новую ссылку подтверждения почты
+func (h *userHandler) PostResendVerification(c *gin.Context) {
+	current, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	if err := h.userService.ResendVerification(context.Background(), current.ID); err != nil {
+		handleUserError(c, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// VerifyEmail подтверждает почту по токену из письма. Входить для этого не нужно,
+// ссылка может быть открыта на другом устройстве.
+func (h *userHandler) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid verification token",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	verified, err := h.userService.VerifyEmail(context.Background(), req.Token)
+	if err != nil {
+		handleUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": verified})
+}
+
 func setRefreshCookie(c *gin.Context, refreshToken, basePath, domain string, maxAge int) {
 	jwtCookie := http.Cookie{
 		Name:     "refresh_token",
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/handler/dto"
+)
+
+func (h *productHandler) GetVariants(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid product id",
+		})
+		return
+	}
+
+	variants, err := h.productService.GetProductVariants(context.Background(), uint(productID))
+	if err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": variants})
+}
+
+func (h *productHandler) PostVariant(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid product id",
+		})
+		return
+	}
+
+	var req dto.PostVariantReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid variant data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	variant, err := h.productService.CreateVariant(context.Background(), user.ID, req.ConvertToSvc(uint(productID)))
+	if err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, variant)
+}
+
+func (h *productHandler) PatchVariant(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	productID, variantID, ok := parseVariantPath(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PatchVariantReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid update data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	variant, err := h.productService.UpdateVariant(
+		context.Background(),
+		user.ID,
+		productID,
+		variantID,
+		req.ConvertToSvc(),
+	)
+	if err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, variant)
+}
+
+func (h *productHandler) DeleteVariant(c *gin.Context) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    apperror.Unauthorized,
+			"message": "User is not authenticated",
+		})
+		return
+	}
+
+	productID, variantID, ok := parseVariantPath(c)
+	if !ok {
+		return
+	}
+
+	if err := h.productService.DeleteVariant(context.Background(), user.ID, productID, variantID); err != nil {
+		handleProductError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseVariantPath разбирает айди товара и варианта из пути запроса
+func parseVariantPath(c *gin.Context) (uint, uint, bool) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid product id",
+		})
+		return 0, 0, false
+	}
+
+	variantID, err := strconv.ParseUint(c.Param("variantID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid variant id",
+		})
+		return 0, 0, false
+	}
+
+	return uint(productID), uint(variantID), true
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/handler/dto"
+)
+
+// GetVerification отдает заявку на проверку магазина владельцу или администратору
+func (h *storeHandler) GetVerification(c *gin.Context) {
+	user, storeID, ok := h.parseStoreRequest(c)
+	if !ok {
+		return
+	}
+
+	verification, err := h.storeService.GetVerification(context.Background(), user, storeID)
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": verification})
+}
+
+func (h *storeHandler) PutLegalDetails(c *gin.Context) {
+	user, storeID, ok := h.parseStoreRequest(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PutLegalDetailsReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid legal details",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	verification, err := h.storeService.UpdateLegalDetails(
+		context.Background(),
+		user.ID,
+		storeID,
+		req.ConvertToSvc(),
+	)
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": verification})
+}
+
+func (h *storeHandler) PostDocumentUpload(c *gin.Context) {
+	user, storeID, ok := h.parseStoreRequest(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PostDocumentUploadReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid upload data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	upload, err := h.storeService.CreateDocumentUpload(
+		context.Background(),
+		user.ID,
+		storeID,
+		req.ContentType,
+		req.Size,
+	)
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": upload})
+}
+
+// PostDocument прикладывает к заявке документ, загруженный по ссылке из PostDocumentUpload
+func (h *storeHandler) PostDocument(c *gin.Context) {
+	user, storeID, ok := h.parseStoreRequest(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PostDocumentReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid document data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	document, err := h.storeService.AddDocument(context.Background(), user.ID, storeID, req.Kind, req.Key)
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": document})
+}
+
+func (h *storeHandler) DeleteDocument(c *gin.Context) {
+	user, storeID, ok := h.parseStoreRequest(c)
+	if !ok {
+		return
+	}
+
+	documentID, err := strconv.ParseUint(c.Param("documentID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid document id",
+		})
+		return
+	}
+
+	if err := h.storeService.DeleteDocument(context.Background(), user.ID, storeID, uint(documentID)); err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PostSubmitVerification отправляет заявку на проверку администратору
+func (h *storeHandler) PostSubmitVerification(c *gin.Context) {
+	user, storeID, ok := h.parseStoreRequest(c)
+	if !ok {
+		return
+	}
+
+	verification, err := h.storeService.SubmitVerification(context.Background(), user.ID, storeID)
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": verification})
+}
+
+// GetStoresForReview отдает администратору магазины на одном этапе проверки, по умолчанию ожидающие решения
+func (h *storeHandler) GetStoresForReview(c *gin.Context) {
+	params, ok := parseListParams(c, storeSorts, "created_at")
+	if !ok {
+		return
+	}
+
+	stores, page, err := h.storeService.GetStoresForReview(
+		context.Background(),
+		c.DefaultQuery("status", entity.VerificationSubmitted),
+		params,
+	)
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	writeList(c, stores, params, page)
+}
+
+// PostReview записывает решение администратора по заявке
+func (h *storeHandler) PostReview(c *gin.Context) {
+	storeID, ok := parseStoreID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PostReviewReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    apperror.BadRequest,
+			"message": "Invalid review data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	verification, err := h.storeService.ReviewVerification(context.Background(), storeID, req.ConvertToSvc())
+	if err != nil {
+		handleStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": verification})
+}
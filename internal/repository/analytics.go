@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/analytics"
+	"gorm.io/gorm"
+)
+
+// seriesQuery считает предложения по дате создания и выдачи товара. Второй подзапрос
+// нужен, потому что предложение может быть создано в одном промежутке, а выдано в другом.
+const seriesQuery = `
+SELECT
+	start,
+	SUM(received) AS received,
+	SUM(accepted) AS accepted,
+	SUM(rejected) AS rejected,
+	SUM(completed) AS completed,
+	SUM(revenue) AS revenue
+FROM (
+	SELECT
+		date_trunc(@bucket, created_at) AS start,
+		COUNT(*) AS received,
+		COUNT(*) FILTER (WHERE status IN @accepted) AS accepted,
+		COUNT(*) FILTER (WHERE status = @rejected) AS rejected,
+		0 AS completed,
+		0 AS revenue
+	FROM offers
+	WHERE store_id = @store AND created_at >= @from AND created_at < @to
+	GROUP BY 1
+	UNION ALL
+	SELECT
+		date_trunc(@bucket, completed_at),
+		0, 0, 0,
+		COUNT(*),
+		SUM(price)
+	FROM offers
+	WHERE store_id = @store AND completed_at >= @from AND completed_at < @to
+	GROUP BY 1
+) buckets
+GROUP BY start
+ORDER BY start`
+
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) *analyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+func (r *analyticsRepository) SelectSeries(
+	ctx context.Context,
+	filter analytics.Filter,
+) ([]entity.AnalyticsBucket, error) {
+	var buckets []entity.AnalyticsBucket
+	if err := r.db.WithContext(ctx).Raw(seriesQuery, map[string]any{
+		"bucket":   filter.Bucket,
+		"accepted": acceptedStatuses,
+		"rejected": entity.OfferStatusRejected,
+		"store":    filter.StoreID,
+		"from":     filter.From,
+		"to":       filter.To,
+	}).Scan(&buckets).Error; err != nil {
+		return nil, analyticsError(err)
+	}
+
+	return buckets, nil
+}
+
+// GetAvgDiscount считает скидку только по предложениям, для которых известна цена магазина.
+// Предложения дороже цены магазина считаются без скидки.
+func (r *analyticsRepository) GetAvgDiscount(ctx context.Context, filter analytics.Filter) (*float64, error) {
+	var avg sql.NullFloat64
+	if err := offersInRange(r.db.WithContext(ctx), filter).
+		Where("status IN ? AND list_price > 0", acceptedStatuses).
+		Select("AVG(GREATEST((list_price - price) / list_price, 0))").
+		Row().Scan(&avg); err != nil {
+		return nil, analyticsError(err)
+	}
+	if !avg.Valid {
+		return nil, nil
+	}
+
+	return &avg.Float64, nil
+}
+
+func (r *analyticsRepository) SelectTopProducts(
+	ctx context.Context,
+	filter analytics.Filter,
+	limit int,
+) ([]entity.ProductAnalytics, error) {
+	var products []entity.ProductAnalytics
+	if err := offersInRange(r.db.WithContext(ctx), filter).
+		Select("o.product_id, p.name, COUNT(*) AS offers, "+
+			"COUNT(*) FILTER (WHERE o.status IN ?) AS accepted", acceptedStatuses).
+		Joins("JOIN products p ON p.id = o.product_id").
+		Group("o.product_id, p.name").
+		Order("offers DESC, o.product_id").
+		Limit(limit).
+		Scan(&products).Error; err != nil {
+		return nil, analyticsError(err)
+	}
+
+	return products, nil
+}
+
+// SelectPoints возвращает все точки магазина с числом предложений и выдач за период
+func (r *analyticsRepository) SelectPoints(
+	ctx context.Context,
+	filter analytics.Filter,
+) ([]entity.PointAnalytics, error) {
+	var points []entity.PointAnalytics
+	if err := r.db.WithContext(ctx).
+		Table("shop_points sp").
+		Select("sp.id AS shop_point_id, sp.address, COUNT(o.id) AS offers, "+
+			"COUNT(o.id) FILTER (WHERE o.status = ?) AS completed", entity.OfferStatusCompleted).
+		Joins("LEFT JOIN offers o ON o.shop_point_id = sp.id AND o.created_at >= ? AND o.created_at < ?",
+			filter.From, filter.To).
+		Where("sp.shop_id = ?", filter.StoreID).
+		Group("sp.id, sp.address").
+		Order("sp.id").
+		Scan(&points).Error; err != nil {
+		return nil, analyticsError(err)
+	}
+
+	return points, nil
+}
+
+// offersInRange предложения магазина, созданные за период
+func offersInRange(db *gorm.DB, filter analytics.Filter) *gorm.DB {
+	return db.Table("offers o").
+		Where("o.store_id = ? AND o.created_at >= ? AND o.created_at < ?", filter.StoreID, filter.From, filter.To)
+}
+
+func analyticsError(err error) error {
+	return &apperror.StoreError{
+		Code:    apperror.DatabaseError,
+		Message: "failed to build store analytics",
+		Err:     err,
+	}
+}
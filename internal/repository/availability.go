@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/product"
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+// SelectProductsAvailability получает ассортимент проверенных магазинов с остатками на точках
+// продаж одним запросом на все товары. Остатки вариантов на точке суммируются, цены берутся
+// только у вариантов в наличии.
+func (r *productRepository) SelectProductsAvailability(
+	ctx context.Context,
+	productIDs []uint,
+) (map[uint][]entity.ShopAvailability, error) {
+	availability := make(map[uint][]entity.ShopAvailability, len(productIDs))
+	if len(productIDs) == 0 {
+		return availability, nil
+	}
+
+	var rowModels []model.AvailabilityRow
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			si.product_id,
+			si.shop_id,
+			s.name AS shop_name,
+			si.is_available,
+			sp.id AS shop_point_id,
+			COALESCE(sp.address, '') AS address,
+			COALESCE(SUM(spi.quantity), 0) AS quantity,
+			(MIN(spi.price) FILTER (WHERE spi.quantity > 0))::float8 AS min_price,
+			(MAX(spi.price) FILTER (WHERE spi.quantity > 0))::float8 AS max_price
+		FROM shop_inventory si
+		JOIN shops s ON s.id = si.shop_id AND `+verifiedShop+`
+		LEFT JOIN (
+			shop_points sp
+			JOIN shop_point_inventory spi ON spi.shop_point_id = sp.id
+		) ON sp.shop_id = si.shop_id AND spi.product_id = si.product_id
+		WHERE si.product_id IN ?
+		GROUP BY si.product_id, si.shop_id, s.name, si.is_available, sp.id, sp.address
+		ORDER BY si.product_id, si.shop_id, sp.id`,
+		productIDs,
+	).Scan(&rowModels).Error; err != nil {
+		return nil, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch product availability",
+			Err:     err,
+		}
+	}
+
+	for _, rowModel := range rowModels {
+		shops := availability[rowModel.ProductID]
+		if len(shops) == 0 || shops[len(shops)-1].ShopID != rowModel.ShopID {
+			shops = append(shops, model.ConvertAvailabilityRowToShop(rowModel))
+		}
+		if point, ok := model.ConvertAvailabilityRowToPoint(rowModel); ok {
+			shop := &shops[len(shops)-1]
+			shop.Points = append(shop.Points, point)
+		}
+		availability[rowModel.ProductID] = shops
+	}
+
+	return availability, nil
+}
+
+// availableProducts оставляет товары в наличии: проверенный магазин не снял товар с продажи
+// и на его точке есть остаток, с учетом отбора по магазину, точке и области поиска
+func availableProducts(query *gorm.DB, filter product.Filter) *gorm.DB {
+	if !filter.Available() {
+		return query
+	}
+
+	stock := query.Session(&gorm.Session{NewDB: true}).
+		Table("shop_inventory si").
+		Select("1").
+		Joins("JOIN shops s ON s.id = si.shop_id AND " + verifiedShop).
+		Joins("JOIN shop_points sp ON sp.shop_id = si.shop_id").
+		Joins("JOIN shop_point_inventory spi ON spi.shop_point_id = sp.id AND spi.product_id = si.product_id").
+		Where("si.product_id = products.id AND si.is_available AND spi.quantity > 0")
+	if filter.ShopID != nil {
+		stock = stock.Where("si.shop_id = ?", *filter.ShopID)
+	}
+	if filter.ShopPointID != nil {
+		stock = stock.Where("sp.id = ?", *filter.ShopPointID)
+	}
+	if filter.Near != nil {
+		stock = withinArea(stock, *filter.Near)
+	}
+
+	return query.Where("EXISTS (?)", stock)
+}
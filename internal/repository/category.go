@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/category"
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// selectSchemaQuery поднимается от категории к корню по parent_id
+// и собирает атрибуты всех предков, ближайшие категории идут первыми.
+const selectSchemaQuery = `
+WITH RECURSIVE ancestors AS (
+    SELECT id, parent_id, 0 AS depth FROM categories WHERE id = ?
+    UNION ALL
+    SELECT c.id, c.parent_id, a.depth + 1
+    FROM categories c
+    JOIN ancestors a ON c.id = a.parent_id
+)
+SELECT ca.*
+FROM category_attributes ca
+JOIN ancestors a ON a.id = ca.category_id
+ORDER BY a.depth, ca.name`
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) *categoryRepository {
+	return &categoryRepository{db: db}
+}
+
+// CategoryExists проверяет наличие категории по айди
+func (r *categoryRepository) CategoryExists(
+	ctx context.Context,
+	categoryID uint,
+) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Table("categories").
+		Where("id = ?", categoryID).
+		Count(&count).Error; err != nil {
+		return false, &apperror.CategoryError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to check category",
+			Err:     err,
+		}
+	}
+
+	return count > 0, nil
+}
+
+// SelectCategorySchema получает схему атрибутов категории с учетом наследования.
+// Атрибут дочерней категории перекрывает одноименный атрибут предка.
+func (r *categoryRepository) SelectCategorySchema(
+	ctx context.Context,
+	categoryID uint,
+) ([]entity.CategoryAttribute, error) {
+	var attributesModel []model.CategoryAttribute
+	if err := r.db.WithContext(ctx).Raw(selectSchemaQuery, categoryID).Scan(&attributesModel).Error; err != nil {
+		return nil, &apperror.CategoryError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch category schema",
+			Err:     err,
+		}
+	}
+
+	seen := make(map[string]struct{}, len(attributesModel))
+	schema := make([]entity.CategoryAttribute, 0, len(attributesModel))
+	for _, attr := range attributesModel {
+		if _, ok := seen[attr.Name]; ok {
+			continue
+		}
+		seen[attr.Name] = struct{}{}
+		schema = append(schema, model.ConvertCategoryAttributeToEntity(attr))
+	}
+
+	return schema, nil
+}
+
+// UpsertCategoryAttribute создает атрибут категории или обновляет существующий с тем же именем
+func (r *categoryRepository) UpsertCategoryAttribute(
+	ctx context.Context,
+	attribute category.Attribute,
+) (entity.CategoryAttribute, error) {
+	attributeModel := model.ConvertCategoryAttributeFromSvc(attribute)
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "category_id"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"type", "unit", "required", "allowed_values"}),
+	}).Create(&attributeModel).Error; err != nil {
+		return entity.CategoryAttribute{}, &apperror.CategoryError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to save category attribute",
+			Err:     err,
+		}
+	}
+
+	return model.ConvertCategoryAttributeToEntity(attributeModel), nil
+}
+
+// DeleteCategoryAttribute удаляет атрибут из схемы категории
+func (r *categoryRepository) DeleteCategoryAttribute(
+	ctx context.Context,
+	categoryID uint,
+	name string,
+) error {
+	tx := r.db.WithContext(ctx).
+		Where("category_id = ? AND name = ?", categoryID, name).
+		Delete(&model.CategoryAttribute{})
+	if tx.Error != nil {
+		return &apperror.CategoryError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to delete category attribute",
+			Err:     tx.Error,
+		}
+	}
+
+	if tx.RowsAffected == 0 {
+		return apperror.ErrCategoryAttributeNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/user"
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InsertEmailVerification заменяет неиспользованные ссылки пользователя новой
+func (r *userRepository) InsertEmailVerification(
+	ctx context.Context,
+	verification user.EmailVerification,
+) (entity.EmailVerification, error) {
+	verificationModel := model.ConvertEmailVerificationFromSvc(verification)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND used_at IS NULL", verification.UserID).
+			Delete(&model.EmailVerification{}).Error; err != nil {
+			return verificationLinkError(err)
+		}
+
+		if err := tx.Omit("created_at").Create(&verificationModel).Error; err != nil {
+			return verificationLinkError(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.EmailVerification{}, err
+	}
+
+	return model.ConvertEmailVerificationToEntity(verificationModel), nil
+}
+
+// HasRecentEmailVerification проверяет, отправлялось ли пользователю письмо с подтверждением
+// за последние within. Время сравнивается в базе, где оно и записано.
+func (r *userRepository) HasRecentEmailVerification(
+	ctx context.Context,
+	userID uint,
+	within time.Duration,
+) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&model.EmailVerification{}).
+		Where("user_id = ? AND created_at > now() - make_interval(secs => ?)", userID, within.Seconds()).
+		Count(&count).Error; err != nil {
+		return false, verificationLinkError(err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *userRepository) GetEmailVerificationByToken(
+	ctx context.Context,
+	tokenHash string,
+) (entity.EmailVerification, error) {
+	var verificationModel model.EmailVerification
+	if err := r.db.WithContext(ctx).
+		Where("token_hash = ?", tokenHash).
+		Take(&verificationModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.EmailVerification{}, apperror.ErrEmailVerificationNotFound
+		}
+		return entity.EmailVerification{}, verificationLinkError(err)
+	}
+
+	return model.ConvertEmailVerificationToEntity(verificationModel), nil
+}
+
+// VerifyEmail подтверждает почту по неиспользованной ссылке одной транзакцией. Ссылка блокируется,
+// чтобы ее нельзя было использовать дважды, и не действует, если пользователь уже сменил почту.
+func (r *userRepository) VerifyEmail(ctx context.Context, verificationID uint) (entity.User, error) {
+	var verificationModel model.EmailVerification
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND used_at IS NULL AND expires_at > now()", verificationID).
+			Take(&verificationModel).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrEmailVerificationNotFound
+			}
+			return verificationLinkError(err)
+		}
+
+		result := tx.Model(&model.User{}).
+			Where("id = ? AND email = ?", verificationModel.UserID, verificationModel.Email).
+			Update("email_verified_at", gorm.Expr("now()"))
+		if result.Error != nil {
+			return verificationLinkError(result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return apperror.ErrEmailVerificationNotFound
+		}
+
+		if err := tx.Model(&verificationModel).
+			Update("used_at", gorm.Expr("now()")).Error; err != nil {
+			return verificationLinkError(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	return r.GetUserByID(ctx, verificationModel.UserID)
+}
+
+func verificationLinkError(err error) error {
+	return &apperror.UserError{
+		Code:    apperror.DatabaseError,
+		Message: "failed to access email verification",
+		Err:     err,
+	}
+}
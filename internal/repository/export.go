@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type exportRepository struct {
+	db *gorm.DB
+}
+
+func NewExportRepository(db *gorm.DB) *exportRepository {
+	return &exportRepository{db: db}
+}
+
+// GetCatalog получает название магазина и время последнего изменения его ассортимента.
+// Каталог непроверенного магазина не выгружается.
+func (r *exportRepository) GetCatalog(ctx context.Context, shopID uint) (entity.Catalog, error) {
+	var catalogModel model.Catalog
+	if err := r.db.WithContext(ctx).
+		Table("shops s").
+		Select("s.id, s.name, ss.currency, GREATEST(s.catalog_updated_at, ss.updated_at) AS catalog_updated_at").
+		Joins("JOIN shop_settings ss ON ss.shop_id = s.id").
+		Where("s.id = ? AND "+verifiedShop, shopID).
+		Take(&catalogModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Catalog{}, apperror.ErrStoreNotFound
+		}
+		return entity.Catalog{}, &apperror.StoreError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch store catalog",
+			Err:     err,
+		}
+	}
+
+	return model.ConvertCatalogToEntity(catalogModel), nil
+}
+
+// SelectCatalogCategories получает категории товаров магазина вместе с их предками
+func (r *exportRepository) SelectCatalogCategories(
+	ctx context.Context,
+	shopID uint,
+) ([]entity.CatalogCategory, error) {
+	var categoryModels []model.CatalogCategory
+	if err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE used AS (
+			SELECT c.id, c.name, c.parent_id
+			FROM categories c
+			WHERE c.id IN (
+				SELECT p.category_id FROM products p
+				JOIN shop_inventory si ON si.product_id = p.id
+				WHERE si.shop_id = ? AND p.deleted_at IS NULL
+			)
+			UNION
+			SELECT c.id, c.name, c.parent_id
+			FROM categories c
+			JOIN used u ON u.parent_id = c.id
+		)
+		SELECT id, name, parent_id FROM used ORDER BY id`,
+		shopID,
+	).Scan(&categoryModels).Error; err != nil {
+		return nil, &apperror.StoreError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch catalog categories",
+			Err:     err,
+		}
+	}
+
+	categories := make([]entity.CatalogCategory, 0, len(categoryModels))
+	for _, categoryModel := range categoryModels {
+		categories = append(categories, model.ConvertCatalogCategoryToEntity(categoryModel))
+	}
+
+	return categories, nil
+}
+
+// StreamCatalogProducts читает товары магазина курсором. Строки приходят по одной
+// на позицию варианта товара на точке продаж и собираются в товар по смене айди.
+func (r *exportRepository) StreamCatalogProducts(
+	ctx context.Context,
+	shopID uint,
+	fn func(entity.CatalogProduct) error,
+) error {
+	db := r.db.WithContext(ctx)
+	rows, err := db.Raw(`
+		SELECT
+			p.id AS product_id,
+			p.name,
+			COALESCE(p.description, '') AS description,
+			p.category_id,
+			COALESCE(pa.attributes::text, '') AS attributes,
+			COALESCE(img.keys, '[]') AS image_keys,
+			si.is_available,
+			pv.id AS variant_id,
+			COALESCE(pv.sku, '') AS sku,
+			COALESCE(pv.barcode, '') AS barcode,
+			COALESCE(pv.options::text, '') AS options,
+			spi.shop_point_id,
+			COALESCE(sp.address, '') AS address,
+			spi.price::float8 AS price,
+			spi.quantity
+		FROM shop_inventory si
+		JOIN products p ON p.id = si.product_id
+		LEFT JOIN LATERAL (
+			SELECT attributes FROM product_attributes WHERE product_id = p.id LIMIT 1
+		) pa ON TRUE
+		LEFT JOIN LATERAL (
+			SELECT json_agg(image_key ORDER BY position, id)::text AS keys
+			FROM image_keys WHERE product_id = p.id
+		) img ON TRUE
+		LEFT JOIN (
+			shop_point_inventory spi
+			JOIN shop_points sp ON sp.id = spi.shop_point_id
+			JOIN product_variants pv ON pv.id = spi.variant_id
+		) ON spi.product_id = p.id AND sp.shop_id = si.shop_id
+		WHERE si.shop_id = ? AND p.deleted_at IS NULL
+		ORDER BY p.id, pv.position, pv.id, spi.shop_point_id`,
+		shopID,
+	).Rows()
+	if err != nil {
+		return catalogStreamError(err)
+	}
+	defer rows.Close()
+
+	var current *entity.CatalogProduct
+	for rows.Next() {
+		var row model.CatalogRow
+		if err := db.ScanRows(rows, &row); err != nil {
+			return catalogStreamError(err)
+		}
+
+		if current != nil && current.ID != row.ProductID {
+			if err := fn(*current); err != nil {
+				return err
+			}
+			current = nil
+		}
+
+		if current == nil {
+			product, err := model.ConvertCatalogRowToEntity(row)
+			if err != nil {
+				return catalogStreamError(err)
+			}
+			current = &product
+		}
+
+		stock, ok, err := model.ConvertCatalogRowToStock(row)
+		if err != nil {
+			return catalogStreamError(err)
+		}
+		if ok {
+			current.Stock = append(current.Stock, stock)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return catalogStreamError(err)
+	}
+
+	if current != nil {
+		return fn(*current)
+	}
+
+	return nil
+}
+
+func catalogStreamError(err error) error {
+	return &apperror.StoreError{
+		Code:    apperror.DatabaseError,
+		Message: "failed to read store catalog",
+		Err:     err,
+	}
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/inventory"
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"github.com/zuzaaa-dev/stawberry/pkg/geo"
+	"gorm.io/gorm"
+)
+
+// distanceExpr расстояние в километрах от места поиска до точки продаж sp по формуле гаверсинуса.
+// Параметры: широта, широта и долгота места поиска. LEAST защищает ASIN от погрешности округления.
+const distanceExpr = `2 * 6371.0 * ASIN(LEAST(1, SQRT(
+	POWER(SIN(RADIANS(sp.latitude - ?) / 2), 2) +
+	COS(RADIANS(?)) * COS(RADIANS(sp.latitude)) * POWER(SIN(RADIANS(sp.longitude - ?) / 2), 2)
+)))`
+
+// distanceArgs параметры distanceExpr для места поиска
+func distanceArgs(center geo.Point) []any {
+	return []any{center.Lat, center.Lat, center.Lon}
+}
+
+// withinArea оставляет точки продаж sp в круге. Ограничивающий прямоугольник отсекает
+// далекие точки по индексу, точное расстояние считается только для оставшихся.
+func withinArea(query *gorm.DB, area geo.Circle) *gorm.DB {
+	box := area.BoundingBox()
+	return query.
+		Where("sp.latitude BETWEEN ? AND ? AND sp.longitude BETWEEN ? AND ?",
+			box.MinLat, box.MaxLat, box.MinLon, box.MaxLon).
+		Where(distanceExpr+" <= ?", append(distanceArgs(area.Center), area.RadiusKm)...)
+}
+
+// SelectNearbyPoints получает точки продаж проверенных магазинов в круге, ближайшие первыми. При поиске по товару
+// остаются точки, где магазин не снял товар с продажи и остаток больше нуля.
+func (r *inventoryRepository) SelectNearbyPoints(
+	ctx context.Context,
+	filter inventory.NearbyFilter,
+) ([]entity.NearbyShopPoint, error) {
+	db := r.db.WithContext(ctx)
+
+	columns := "sp.id, sp.shop_id, sp.address, sp.phone, sp.latitude, sp.longitude, sp.timezone, s.name AS shop_name, " +
+		distanceExpr + " AS distance_km"
+	query := db.Table("shop_points sp").
+		Joins("JOIN shops s ON s.id = sp.shop_id AND " + verifiedShop)
+
+	if filter.ProductID != nil || filter.VariantID != nil {
+		stock := db.Session(&gorm.Session{NewDB: true}).
+			Table("shop_point_inventory spi").
+			Select("spi.shop_point_id, SUM(spi.quantity) AS quantity").
+			Joins("JOIN shop_points ssp ON ssp.id = spi.shop_point_id").
+			Joins("JOIN shop_inventory si ON si.shop_id = ssp.shop_id AND si.product_id = spi.product_id").
+			Joins("JOIN products p ON p.id = spi.product_id AND p.deleted_at IS NULL").
+			Where("si.is_available AND spi.quantity > 0").
+			Group("spi.shop_point_id")
+		if filter.ProductID != nil {
+			stock = stock.Where("spi.product_id = ?", *filter.ProductID)
+		}
+		if filter.VariantID != nil {
+			stock = stock.Where("spi.variant_id = ?", *filter.VariantID)
+		}
+
+		columns += ", stock.quantity"
+		query = query.Joins("JOIN (?) stock ON stock.shop_point_id = sp.id", stock)
+	}
+
+	var pointModels []model.NearbyShopPoint
+	if err := withinArea(query.Select(columns, distanceArgs(filter.Area.Center)...), filter.Area).
+		Order("distance_km, sp.id").
+		Limit(filter.Limit).
+		Scan(&pointModels).Error; err != nil {
+		return nil, &apperror.InventoryError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to search shop points",
+			Err:     err,
+		}
+	}
+
+	points := make([]entity.NearbyShopPoint, 0, len(pointModels))
+	for _, pointModel := range pointModels {
+		points = append(points, model.ConvertNearbyShopPointToEntity(pointModel))
+	}
+
+	return points, nil
+}
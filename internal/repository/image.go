@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/image"
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type imageRepository struct {
+	db *gorm.DB
+}
+
+func NewImageRepository(db *gorm.DB) *imageRepository {
+	return &imageRepository{db: db}
+}
+
+// InsertImage сохраняет ключ изображения, ставя его в конец списка изображений товара
+func (r *imageRepository) InsertImage(
+	ctx context.Context,
+	img image.Image,
+) (entity.ProductImage, error) {
+	imageModel := model.ConvertImageFromSvc(img)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var position int
+		if err := tx.Model(&model.ImageKey{}).
+			Where("product_id = ?", img.ProductID).
+			Select("COALESCE(MAX(position), -1) + 1").
+			Scan(&position).Error; err != nil {
+			return err
+		}
+		imageModel.Position = position
+
+		return tx.Create(&imageModel).Error
+	})
+	if err != nil {
+		if isDuplicateError(err) {
+			return entity.ProductImage{}, &apperror.ImageError{
+				Code:    apperror.DuplicateError,
+				Message: "image with this key already exists",
+				Err:     err,
+			}
+		}
+		return entity.ProductImage{}, &apperror.ImageError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to save image key",
+			Err:     err,
+		}
+	}
+
+	return model.ConvertImageToEntity(imageModel), nil
+}
+
+// GetImage получает изображение товара по айди
+func (r *imageRepository) GetImage(
+	ctx context.Context,
+	productID, imageID uint,
+) (entity.ProductImage, error) {
+	var imageModel model.ImageKey
+	if err := r.db.WithContext(ctx).
+		Where("id = ? AND product_id = ?", imageID, productID).
+		First(&imageModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.ProductImage{}, apperror.ErrImageNotFound
+		}
+		return entity.ProductImage{}, &apperror.ImageError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch image",
+			Err:     err,
+		}
+	}
+
+	return model.ConvertImageToEntity(imageModel), nil
+}
+
+// GetImageByKey получает изображение товара по ключу в хранилище
+func (r *imageRepository) GetImageByKey(
+	ctx context.Context,
+	productID uint,
+	key string,
+) (entity.ProductImage, error) {
+	var imageModel model.ImageKey
+	if err := r.db.WithContext(ctx).
+		Where("image_key = ? AND product_id = ?", key, productID).
+		First(&imageModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.ProductImage{}, apperror.ErrImageNotFound
+		}
+		return entity.ProductImage{}, &apperror.ImageError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch image",
+			Err:     err,
+		}
+	}
+
+	return model.ConvertImageToEntity(imageModel), nil
+}
+
+// ReplaceImage обновляет метаданные изображения после замены оригинала
+func (r *imageRepository) ReplaceImage(
+	ctx context.Context,
+	imageID uint,
+	img image.Image,
+) (entity.ProductImage, error) {
+	tx := r.db.WithContext(ctx).
+		Model(&model.ImageKey{}).
+		Where("id = ? AND product_id = ?", imageID, img.ProductID).
+		Updates(map[string]any{
+			"content_type": img.ContentType,
+			"size":         img.Size,
+			"etag":         img.ETag,
+		})
+	if tx.Error != nil {
+		return entity.ProductImage{}, &apperror.ImageError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to update image",
+			Err:     tx.Error,
+		}
+	}
+
+	if tx.RowsAffected == 0 {
+		return entity.ProductImage{}, apperror.ErrImageNotFound
+	}
+
+	return r.GetImage(ctx, img.ProductID, imageID)
+}
+
+// UpdateVariantsETag запоминает, для какой версии оригинала построены уменьшенные копии
+func (r *imageRepository) UpdateVariantsETag(
+	ctx context.Context,
+	imageID uint,
+	etag string,
+) error {
+	if err := r.db.WithContext(ctx).
+		Model(&model.ImageKey{}).
+		Where("id = ?", imageID).
+		Update("variants_etag", etag).Error; err != nil {
+		return &apperror.ImageError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to update image variants",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// SelectStaleImages получает изображения, копии которых не соответствуют текущему оригиналу
+func (r *imageRepository) SelectStaleImages(
+	ctx context.Context,
+	limit int,
+) ([]entity.ProductImage, error) {
+	var imagesModel []model.ImageKey
+	if err := r.db.WithContext(ctx).
+		Where("variants_etag <> etag").
+		Order("id").
+		Limit(limit).
+		Find(&imagesModel).Error; err != nil {
+		return nil, &apperror.ImageError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch stale images",
+			Err:     err,
+		}
+	}
+
+	images := make([]entity.ProductImage, 0, len(imagesModel))
+	for _, img := range imagesModel {
+		images = append(images, model.ConvertImageToEntity(img))
+	}
+
+	return images, nil
+}
+
+// SelectProductsImages получает изображения нескольких товаров, сгруппированные по товару
+func (r *imageRepository) SelectProductsImages(
+	ctx context.Context,
+	productIDs []uint,
+) (map[uint][]entity.ProductImage, error) {
+	images := make(map[uint][]entity.ProductImage, len(productIDs))
+	if len(productIDs) == 0 {
+		return images, nil
+	}
+
+	var imagesModel []model.ImageKey
+	if err := r.db.WithContext(ctx).
+		Where("product_id IN ?", productIDs).
+		Order("product_id, position, id").
+		Find(&imagesModel).Error; err != nil {
+		return nil, &apperror.ImageError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch product images",
+			Err:     err,
+		}
+	}
+
+	for _, img := range imagesModel {
+		images[img.ProductID] = append(images[img.ProductID], model.ConvertImageToEntity(img))
+	}
+
+	return images, nil
+}
+
+// UpdateImagePositions выставляет порядок изображений товара согласно порядку imageIDs
+func (r *imageRepository) UpdateImagePositions(
+	ctx context.Context,
+	productID uint,
+	imageIDs []uint,
+) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for position, imageID := range imageIDs {
+			if err := tx.Model(&model.ImageKey{}).
+				Where("id = ? AND product_id = ?", imageID, productID).
+				Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return &apperror.ImageError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to reorder images",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// DeleteImage удаляет ключ изображения товара
+func (r *imageRepository) DeleteImage(
+	ctx context.Context,
+	productID, imageID uint,
+) error {
+	tx := r.db.WithContext(ctx).
+		Where("id = ? AND product_id = ?", imageID, productID).
+		Delete(&model.ImageKey{})
+	if tx.Error != nil {
+		return &apperror.ImageError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to delete image",
+			Err:     tx.Error,
+		}
+	}
+
+	if tx.RowsAffected == 0 {
+		return apperror.ErrImageNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,478 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/importjob"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/inventory"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/product"
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type importRepository struct {
+	db *gorm.DB
+}
+
+func NewImportRepository(db *gorm.DB) *importRepository {
+	return &importRepository{db: db}
+}
+
+// InsertJob ставит задание импорта в очередь
+func (r *importRepository) InsertJob(ctx context.Context, job importjob.Job) (entity.ImportJob, error) {
+	jobModel, err := model.ConvertImportJobFromSvc(job)
+	if err != nil {
+		return entity.ImportJob{}, &apperror.ImportError{
+			Code:    apperror.BadRequest,
+			Message: "invalid column mapping",
+			Err:     err,
+		}
+	}
+
+	if err := r.db.WithContext(ctx).Create(&jobModel).Error; err != nil {
+		return entity.ImportJob{}, &apperror.ImportError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to create import job",
+			Err:     err,
+		}
+	}
+
+	return model.ConvertImportJobToEntity(jobModel), nil
+}
+
+// GetJob получает задание импорта по айди
+func (r *importRepository) GetJob(ctx context.Context, jobID uint) (entity.ImportJob, error) {
+	var jobModel model.ImportJob
+	if err := r.db.WithContext(ctx).First(&jobModel, jobID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.ImportJob{}, apperror.ErrImportJobNotFound
+		}
+		return entity.ImportJob{}, &apperror.ImportError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch import job",
+			Err:     err,
+		}
+	}
+
+	return model.ConvertImportJobToEntity(jobModel), nil
+}
+
+// ClaimPendingJob переводит самое старое ожидающее задание в обработку.
+// SKIP LOCKED не дает двум экземплярам приложения взять одно задание.
+func (r *importRepository) ClaimPendingJob(ctx context.Context) (entity.ImportJob, bool, error) {
+	var jobModel model.ImportJob
+	claimed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", entity.ImportStatusPending).
+			Order("id").
+			Limit(1).
+			Find(&jobModel)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		now := time.Now()
+		jobModel.Status = entity.ImportStatusProcessing
+		jobModel.StartedAt = &now
+		claimed = true
+
+		return tx.Model(&jobModel).Updates(map[string]any{
+			"status":     jobModel.Status,
+			"started_at": now,
+			"updated_at": now,
+		}).Error
+	})
+	if err != nil {
+		return entity.ImportJob{}, false, &apperror.ImportError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to claim import job",
+			Err:     err,
+		}
+	}
+
+	return model.ConvertImportJobToEntity(jobModel), claimed, nil
+}
+
+// ResetStaleJobs возвращает в очередь задания, брошенные упавшим процессом. Брошенным
+// считается задание, прогресс которого не обновлялся с updatedBefore: долгий импорт,
+// который еще идет на другом экземпляре, регулярно обновляет updated_at и не сбрасывается.
+func (r *importRepository) ResetStaleJobs(ctx context.Context, updatedBefore time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		stale := tx.Model(&model.ImportJob{}).
+			Select("id").
+			Where("status = ? AND updated_at < ?", entity.ImportStatusProcessing, updatedBefore)
+
+		// строки будут прочитаны заново, поэтому старый отчет об ошибках не нужен
+		if err := tx.Where("job_id IN (?)", stale).Delete(&model.ImportJobError{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.ImportJob{}).
+			Where("status = ? AND updated_at < ?", entity.ImportStatusProcessing, updatedBefore).
+			Updates(map[string]any{
+				"status":        entity.ImportStatusPending,
+				"started_at":    nil,
+				"total_rows":    0,
+				"imported_rows": 0,
+				"failed_rows":   0,
+			}).Error
+	})
+	if err != nil {
+		return &apperror.ImportError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to reset stale import jobs",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// UpdateJobProgress сохраняет счетчики строк обрабатываемого задания
+func (r *importRepository) UpdateJobProgress(
+	ctx context.Context,
+	jobID uint,
+	progress importjob.Progress,
+) error {
+	if err := r.db.WithContext(ctx).
+		Model(&model.ImportJob{}).
+		Where("id = ?", jobID).
+		Updates(progressColumns(progress)).Error; err != nil {
+		return &apperror.ImportError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to update import progress",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// FinishJob фиксирует итог задания
+func (r *importRepository) FinishJob(
+	ctx context.Context,
+	jobID uint,
+	status string,
+	progress importjob.Progress,
+	message string,
+) error {
+	columns := progressColumns(progress)
+	columns["status"] = status
+	columns["error"] = message
+	columns["finished_at"] = time.Now()
+
+	if err := r.db.WithContext(ctx).
+		Model(&model.ImportJob{}).
+		Where("id = ?", jobID).
+		Updates(columns).Error; err != nil {
+		return &apperror.ImportError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to finish import job",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// InsertRowErrors дописывает ошибки строк в отчет задания
+func (r *importRepository) InsertRowErrors(
+	ctx context.Context,
+	jobID uint,
+	rowErrors []entity.ImportRowError,
+) error {
+	if len(rowErrors) == 0 {
+		return nil
+	}
+
+	errorModels := make([]model.ImportJobError, 0, len(rowErrors))
+	for _, rowErr := range rowErrors {
+		errorModels = append(errorModels, model.ConvertImportRowErrorFromEntity(jobID, rowErr))
+	}
+
+	if err := r.db.WithContext(ctx).CreateInBatches(&errorModels, 500).Error; err != nil {
+		return &apperror.ImportError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to save import errors",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// SelectRowErrors получает отчет об ошибках задания
+func (r *importRepository) SelectRowErrors(ctx context.Context, jobID uint) ([]entity.ImportRowError, error) {
+	var errorModels []model.ImportJobError
+	if err := r.db.WithContext(ctx).
+		Where("job_id = ?", jobID).
+		Order("row_number, id").
+		Find(&errorModels).Error; err != nil {
+		return nil, &apperror.ImportError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch import errors",
+			Err:     err,
+		}
+	}
+
+	rowErrors := make([]entity.ImportRowError, 0, len(errorModels))
+	for _, errorModel := range errorModels {
+		rowErrors = append(rowErrors, model.ConvertImportRowErrorToEntity(errorModel))
+	}
+
+	return rowErrors, nil
+}
+
+// UpsertProducts записывает пачку строк одной транзакцией. Каждая строка пишется в своей
+// точке сохранения: ошибка откатывает только эту строку и возвращается в отчете об ошибках,
+// остальные строки пачки сохраняются. Ошибка самой транзакции возвращается как error.
+func (r *importRepository) UpsertProducts(
+	ctx context.Context,
+	shopID uint,
+	rows []importjob.Row,
+) ([]entity.ImportRowError, error) {
+	var rowErrors []entity.ImportRowError
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			if err := tx.SavePoint(importRowSavePoint).Error; err != nil {
+				return err
+			}
+
+			if err := upsertImportedRow(tx, shopID, row); err != nil {
+				if err := tx.RollbackTo(importRowSavePoint).Error; err != nil {
+					return err
+				}
+				rowErrors = append(rowErrors, entity.ImportRowError{
+					Row:     row.Number,
+					Message: err.Error(),
+				})
+				continue
+			}
+
+			if err := tx.Exec("RELEASE SAVEPOINT " + importRowSavePoint).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, &apperror.ImportError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to save imported products",
+			Err:     err,
+		}
+	}
+
+	return rowErrors, nil
+}
+
+// importRowSavePoint точка сохранения перед записью очередной строки импорта
+const importRowSavePoint = "import_row"
+
+// upsertImportedRow записывает строку импорта: товар по имени из ассортимента магазина,
+// его вариант, подобранный upsertImportedVariant, и позицию на точке продаж.
+func upsertImportedRow(tx *gorm.DB, shopID uint, row importjob.Row) error {
+	productID, err := upsertImportedProduct(tx, shopID, row)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Exec(`
+		INSERT INTO shop_inventory (product_id, shop_id, is_available)
+		VALUES (?, ?, TRUE)
+		ON CONFLICT (product_id, shop_id) DO UPDATE SET is_available = TRUE`,
+		productID, shopID,
+	).Error; err != nil {
+		return err
+	}
+
+	variantID, err := upsertImportedVariant(tx, productID, row)
+	if err != nil {
+		return err
+	}
+
+	if row.ShopPointID == nil || (row.Price == nil && row.Quantity == nil) {
+		return nil
+	}
+
+	// при создании позиции недостающие значения считаются нулевыми,
+	// при обновлении меняются только переданные
+	_, err = changePointStock(tx, productID, inventory.StockChange{
+		ShopPointID: *row.ShopPointID,
+		VariantID:   variantID,
+		Price:       row.Price,
+		Quantity:    row.Quantity,
+		Reason:      inventory.ReasonImport,
+	})
+
+	return err
+}
+
+func upsertImportedProduct(tx *gorm.DB, shopID uint, row importjob.Row) (uint, error) {
+	var productID uint
+	if err := tx.Raw(`
+		SELECT p.id FROM products p
+		JOIN shop_inventory si ON si.product_id = p.id
+		WHERE si.shop_id = ? AND lower(p.name) = lower(?) AND p.deleted_at IS NULL
+		ORDER BY p.id
+		LIMIT 1
+		FOR UPDATE OF p`,
+		shopID, row.Name,
+	).Scan(&productID).Error; err != nil {
+		return 0, err
+	}
+
+	if productID == 0 {
+		if err := tx.Raw(`
+			INSERT INTO products (name, description, category_id)
+			VALUES (?, ?, ?)
+			RETURNING id`,
+			row.Name, row.Description, row.CategoryID,
+		).Scan(&productID).Error; err != nil {
+			return 0, err
+		}
+	} else {
+		if err := tx.Exec(`
+			UPDATE products SET
+				description = CASE WHEN ? = '' THEN description ELSE ? END,
+				category_id = COALESCE(?, category_id)
+			WHERE id = ?`,
+			row.Description, row.Description, row.CategoryID, productID,
+		).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	if row.Attributes == nil {
+		return productID, nil
+	}
+
+	if err := tx.Where("product_id = ?", productID).Delete(&model.ProductAttributes{}).Error; err != nil {
+		return 0, err
+	}
+
+	return productID, insertProductAttributes(tx, productID, row.Attributes)
+}
+
+// upsertImportedVariant находит вариант строки по артикулу, а без него по набору опций.
+// Строка без артикула и опций относится к первому варианту товара.
+// Не найденный вариант создается, у найденного обновляются переданные штрихкод и опции.
+func upsertImportedVariant(tx *gorm.DB, productID uint, row importjob.Row) (uint, error) {
+	var options []byte
+	if row.Options != nil {
+		var err error
+		if options, err = json.Marshal(row.Options); err != nil {
+			return 0, err
+		}
+	}
+
+	query := tx.Table("product_variants").Select("id").Where("product_id = ?", productID)
+	switch {
+	case row.SKU != "":
+		query = query.Where("sku = ?", row.SKU)
+	case options != nil:
+		query = query.Where("options = ?::jsonb", string(options))
+	}
+
+	var ids []uint
+	if err := query.Order("position, id").Limit(1).Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+
+	if len(ids) == 0 {
+		variants, err := insertProductVariants(tx, productID, []product.Variant{{
+			SKU:     row.SKU,
+			Barcode: row.Barcode,
+			Options: row.Options,
+		}})
+		if err != nil {
+			return 0, err
+		}
+		return variants[0].ID, nil
+	}
+
+	columns := make(map[string]any)
+	if row.Barcode != "" {
+		columns["barcode"] = row.Barcode
+	}
+	if row.SKU != "" && options != nil {
+		columns["options"] = options
+	}
+	if len(columns) > 0 {
+		if err := tx.Model(&model.ProductVariant{}).Where("id = ?", ids[0]).Updates(columns).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	return ids[0], nil
+}
+
+// FindCategoryByPath проходит путь категорий от первого имени к последнему: первое имя
+// ищется среди корневых категорий, каждое следующее — среди дочерних категорий предыдущего
+func (r *importRepository) FindCategoryByPath(ctx context.Context, names []string) (uint, bool, error) {
+	var parentID *uint
+	for _, name := range names {
+		query := r.db.WithContext(ctx).
+			Table("categories").
+			Select("id").
+			Where("lower(name) = lower(?)", name)
+		if parentID != nil {
+			query = query.Where("parent_id = ?", *parentID)
+		} else {
+			query = query.Where("parent_id IS NULL")
+		}
+
+		var ids []uint
+		if err := query.Limit(1).Pluck("id", &ids).Error; err != nil {
+			return 0, false, &apperror.ImportError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to find category",
+				Err:     err,
+			}
+		}
+		if len(ids) == 0 {
+			return 0, false, nil
+		}
+		parentID = &ids[0]
+	}
+
+	if parentID == nil {
+		return 0, false, nil
+	}
+
+	return *parentID, true, nil
+}
+
+// SelectShopPointIDs получает айди точек продаж магазина
+func (r *importRepository) SelectShopPointIDs(ctx context.Context, shopID uint) ([]uint, error) {
+	var ids []uint
+	if err := r.db.WithContext(ctx).
+		Table("shop_points").
+		Where("shop_id = ?", shopID).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, &apperror.ImportError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch shop points",
+			Err:     err,
+		}
+	}
+
+	return ids, nil
+}
+
+func progressColumns(progress importjob.Progress) map[string]any {
+	return map[string]any{
+		"total_rows":    progress.TotalRows,
+		"imported_rows": progress.ImportedRows,
+		"failed_rows":   progress.FailedRows,
+		"updated_at":    gorm.Expr("now()"),
+	}
+}
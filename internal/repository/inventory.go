@@ -0,0 +1,365 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/inventory"
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type inventoryRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) *inventoryRepository {
+	return &inventoryRepository{db: db}
+}
+
+// GetShopPointRole возвращает роль пользователя в магазине, которому принадлежит точка продаж,
+// пустую строку если он не сотрудник
+func (r *inventoryRepository) GetShopPointRole(ctx context.Context, shopPointID, userID uint) (string, error) {
+	var roles []*string
+	if err := r.db.WithContext(ctx).
+		Table("shop_points sp").
+		Joins("LEFT JOIN shop_members sm ON sm.shop_id = sp.shop_id AND sm.user_id = ?", userID).
+		Where("sp.id = ?", shopPointID).
+		Pluck("sm.role", &roles).Error; err != nil {
+		return "", &apperror.InventoryError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch shop point",
+			Err:     err,
+		}
+	}
+	if len(roles) == 0 {
+		return "", apperror.ErrShopPointNotFound
+	}
+	if roles[0] == nil {
+		return "", nil
+	}
+
+	return *roles[0], nil
+}
+
+// ChangeStock меняет позицию варианта на точке продаж и записывает изменение в журнал
+func (r *inventoryRepository) ChangeStock(
+	ctx context.Context,
+	change inventory.StockChange,
+) (entity.InventoryItem, error) {
+	var itemModel model.InventoryItem
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var productIDs []uint
+		if err := tx.Table("product_variants").
+			Where("id = ?", change.VariantID).
+			Pluck("product_id", &productIDs).Error; err != nil {
+			return &apperror.InventoryError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch product variant",
+				Err:     err,
+			}
+		}
+		if len(productIDs) == 0 {
+			return &apperror.InventoryError{
+				Code:    apperror.NotFound,
+				Message: "product variant not found",
+			}
+		}
+
+		if _, err := changePointStock(tx, productIDs[0], change); err != nil {
+			return err
+		}
+
+		if err := inventoryItems(tx).
+			Where("spi.shop_point_id = ? AND spi.variant_id = ?", change.ShopPointID, change.VariantID).
+			Take(&itemModel).Error; err != nil {
+			return &apperror.InventoryError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch inventory item",
+				Err:     err,
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.InventoryItem{}, err
+	}
+
+	return model.ConvertInventoryItemToEntity(itemModel), nil
+}
+
+// inventorySortColumns поля сортировки позиций точки продаж
+var inventorySortColumns = map[string]sortColumn{
+	"name":     {expr: "p.name", cast: "text"},
+	"quantity": {expr: "spi.quantity", cast: "integer"},
+	"price":    {expr: "spi.price", cast: "numeric"},
+}
+
+func (r *inventoryRepository) SelectPointInventory(
+	ctx context.Context,
+	shopPointID uint,
+	params pagination.Params,
+) ([]entity.InventoryItem, pagination.Page, error) {
+	column, ok := inventorySortColumns[params.Sort]
+	if !ok {
+		return nil, pagination.Page{}, &apperror.InventoryError{
+			Code:    apperror.BadRequest,
+			Message: "unsupported sort field " + params.Sort,
+		}
+	}
+
+	query := inventoryItems(r.db.WithContext(ctx)).Where("spi.shop_point_id = ?", shopPointID)
+
+	var total *int
+	if !params.Keyset() {
+		var count int64
+		if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+			return nil, pagination.Page{}, &apperror.InventoryError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to count inventory items",
+				Err:     err,
+			}
+		}
+		n := int(count)
+		total = &n
+	}
+
+	var itemModels []model.InventoryItem
+	if err := paginate(query, column, "spi.variant_id", params).Find(&itemModels).Error; err != nil {
+		return nil, pagination.Page{}, &apperror.InventoryError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch inventory items",
+			Err:     err,
+		}
+	}
+
+	items := make([]entity.InventoryItem, 0, len(itemModels))
+	for _, itemModel := range itemModels {
+		items = append(items, model.ConvertInventoryItemToEntity(itemModel))
+	}
+
+	items, page := pagination.Finish(items, params, total, func(i entity.InventoryItem) (string, uint) {
+		switch params.Sort {
+		case "quantity":
+			return strconv.Itoa(i.Quantity), i.VariantID
+		case "price":
+			return cursorFloat(i.Price), i.VariantID
+		default:
+			return i.ProductName, i.VariantID
+		}
+	})
+
+	return items, page, nil
+}
+
+// movementSortColumns поля сортировки журнала движения остатков
+var movementSortColumns = map[string]sortColumn{
+	"created_at": {expr: "inventory_movements.created_at", cast: "timestamp"},
+}
+
+func (r *inventoryRepository) SelectMovements(
+	ctx context.Context,
+	filter inventory.MovementFilter,
+	params pagination.Params,
+) ([]entity.InventoryMovement, pagination.Page, error) {
+	column, ok := movementSortColumns[params.Sort]
+	if !ok {
+		return nil, pagination.Page{}, &apperror.InventoryError{
+			Code:    apperror.BadRequest,
+			Message: "unsupported sort field " + params.Sort,
+		}
+	}
+
+	query := r.db.WithContext(ctx).
+		Model(&model.InventoryMovement{}).
+		Where("shop_point_id = ?", filter.ShopPointID)
+	if filter.VariantID != nil {
+		query = query.Where("variant_id = ?", *filter.VariantID)
+	}
+
+	var total *int
+	if !params.Keyset() {
+		var count int64
+		if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+			return nil, pagination.Page{}, &apperror.InventoryError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to count inventory movements",
+				Err:     err,
+			}
+		}
+		n := int(count)
+		total = &n
+	}
+
+	var movementModels []model.InventoryMovement
+	if err := paginate(query, column, "inventory_movements.id", params).
+		Find(&movementModels).Error; err != nil {
+		return nil, pagination.Page{}, &apperror.InventoryError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch inventory movements",
+			Err:     err,
+		}
+	}
+
+	movements := make([]entity.InventoryMovement, 0, len(movementModels))
+	for _, movementModel := range movementModels {
+		movements = append(movements, model.ConvertInventoryMovementToEntity(movementModel))
+	}
+
+	movements, page := pagination.Finish(movements, params, total, func(m entity.InventoryMovement) (string, uint) {
+		return cursorTime(m.CreatedAt), m.ID
+	})
+
+	return movements, page, nil
+}
+
+// changePointStock применяет изменение к позиции варианта на точке продаж внутри транзакции
+// и записывает его в журнал. Новая позиция создается с нулевыми значениями вместо незаданных
+// и только для товара из ассортимента магазина точки, сдвинуть остаток можно только
+// у существующей. Изменение без эффекта в журнал не попадает.
+func changePointStock(tx *gorm.DB, productID uint, change inventory.StockChange) (model.PointInventory, error) {
+	stockModel, found, err := lockPointStock(tx, change)
+	if err != nil {
+		return model.PointInventory{}, err
+	}
+
+	created := false
+	if !found {
+		if change.Delta != nil {
+			return model.PointInventory{}, apperror.ErrInventoryItemNotFound
+		}
+		if err := checkPointProduct(tx, change.ShopPointID, productID); err != nil {
+			return model.PointInventory{}, err
+		}
+
+		// параллельный запрос мог создать позицию раньше, тогда изменение применяется к ней
+		result := tx.Exec(`
+			INSERT INTO shop_point_inventory (shop_point_id, product_id, variant_id, price, quantity, updated_at)
+			VALUES (?, ?, ?, 0, 0, now())
+			ON CONFLICT (shop_point_id, variant_id) DO NOTHING`,
+			change.ShopPointID, productID, change.VariantID,
+		)
+		if result.Error != nil {
+			return model.PointInventory{}, stockError(result.Error)
+		}
+		created = result.RowsAffected > 0
+
+		if stockModel, _, err = lockPointStock(tx, change); err != nil {
+			return model.PointInventory{}, err
+		}
+	}
+
+	previous := stockModel
+	if change.Price != nil {
+		stockModel.Price = *change.Price
+	}
+	if change.Quantity != nil {
+		stockModel.Quantity = *change.Quantity
+	}
+	if change.Delta != nil {
+		stockModel.Quantity += *change.Delta
+	}
+	if stockModel.Quantity < 0 {
+		return model.PointInventory{}, &apperror.InventoryError{
+			Code:    apperror.Conflict,
+			Message: "not enough stock at the shop point",
+		}
+	}
+
+	if !created && stockModel.Price == previous.Price && stockModel.Quantity == previous.Quantity {
+		return stockModel, nil
+	}
+
+	if err := tx.Model(&model.PointInventory{}).
+		Where("shop_point_id = ? AND variant_id = ?", change.ShopPointID, change.VariantID).
+		Updates(map[string]any{
+			"price":      stockModel.Price,
+			"quantity":   stockModel.Quantity,
+			"updated_at": gorm.Expr("now()"),
+		}).Error; err != nil {
+		return model.PointInventory{}, stockError(err)
+	}
+
+	movementModel := model.InventoryMovement{
+		ShopPointID: change.ShopPointID,
+		ProductID:   stockModel.ProductID,
+		VariantID:   change.VariantID,
+		Delta:       stockModel.Quantity - previous.Quantity,
+		Quantity:    stockModel.Quantity,
+		Price:       stockModel.Price,
+		Reason:      change.Reason,
+		Note:        change.Note,
+		UserID:      change.UserID,
+	}
+	if err := tx.Create(&movementModel).Error; err != nil {
+		return model.PointInventory{}, &apperror.InventoryError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to record inventory movement",
+			Err:     err,
+		}
+	}
+
+	return stockModel, nil
+}
+
+// lockPointStock блокирует позицию варианта на точке продаж до конца транзакции
+func lockPointStock(tx *gorm.DB, change inventory.StockChange) (model.PointInventory, bool, error) {
+	var stockModel model.PointInventory
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("shop_point_id = ? AND variant_id = ?", change.ShopPointID, change.VariantID).
+		Take(&stockModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.PointInventory{}, false, nil
+		}
+		return model.PointInventory{}, false, stockError(err)
+	}
+
+	return stockModel, true, nil
+}
+
+// checkPointProduct проверяет, что товар входит в ассортимент магазина, которому принадлежит точка.
+// Ассортимент определяет, какие товары может менять магазин, поэтому позиция на точке его не расширяет.
+func checkPointProduct(tx *gorm.DB, shopPointID, productID uint) error {
+	var count int64
+	if err := tx.Table("shop_points sp").
+		Joins("JOIN shop_inventory si ON si.shop_id = sp.shop_id").
+		Where("sp.id = ? AND si.product_id = ?", shopPointID, productID).
+		Count(&count).Error; err != nil {
+		return &apperror.InventoryError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to check store assortment",
+			Err:     err,
+		}
+	}
+	if count == 0 {
+		return &apperror.InventoryError{
+			Code:    apperror.Forbidden,
+			Message: "product is not in the assortment of the shop point's store",
+		}
+	}
+
+	return nil
+}
+
+// inventoryItems позиции точек продаж с названием товара и артикулом варианта
+func inventoryItems(db *gorm.DB) *gorm.DB {
+	return db.Table("shop_point_inventory spi").
+		Select("spi.shop_point_id, spi.product_id, spi.variant_id, spi.price::float8 AS price, " +
+			"spi.quantity, spi.updated_at, p.name AS product_name, pv.sku").
+		Joins("JOIN products p ON p.id = spi.product_id").
+		Joins("JOIN product_variants pv ON pv.id = spi.variant_id")
+}
+
+func stockError(err error) error {
+	return &apperror.InventoryError{
+		Code:    apperror.DatabaseError,
+		Message: "failed to save inventory item",
+		Err:     err,
+	}
+}
@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/inventory"
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+// thresholdExpr действующий порог позиции: порог товара, а если его нет — порог ближайшей
+// из категорий товара и ее предков по parent_id. Ожидает в запросе точку продаж sp и товар p.
+const thresholdExpr = `COALESCE(
+	(SELECT t.threshold FROM low_stock_thresholds t WHERE t.shop_id = sp.shop_id AND t.product_id = p.id),
+	(
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 0 AS depth FROM categories WHERE id = p.category_id
+			UNION ALL
+			SELECT c.id, c.parent_id, a.depth + 1
+			FROM categories c
+			JOIN ancestors a ON c.id = a.parent_id
+		)
+		SELECT t.threshold FROM low_stock_thresholds t
+		JOIN ancestors a ON a.id = t.category_id
+		WHERE t.shop_id = sp.shop_id
+		ORDER BY a.depth
+		LIMIT 1
+	)
+)`
+
+// GetShopRole возвращает роль пользователя в магазине, пустую строку если он не сотрудник
+func (r *inventoryRepository) GetShopRole(ctx context.Context, shopID, userID uint) (string, error) {
+	var roles []*string
+	if err := r.db.WithContext(ctx).
+		Table("shops s").
+		Joins("LEFT JOIN shop_members sm ON sm.shop_id = s.id AND sm.user_id = ?", userID).
+		Where("s.id = ?", shopID).
+		Pluck("sm.role", &roles).Error; err != nil {
+		return "", &apperror.InventoryError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch store",
+			Err:     err,
+		}
+	}
+	if len(roles) == 0 {
+		return "", apperror.ErrInventoryStoreNotFound
+	}
+	if roles[0] == nil {
+		return "", nil
+	}
+
+	return *roles[0], nil
+}
+
+func (r *inventoryRepository) SelectThresholds(ctx context.Context, shopID uint) ([]entity.LowStockThreshold, error) {
+	var thresholdModels []model.LowStockThreshold
+	if err := r.db.WithContext(ctx).
+		Where("shop_id = ?", shopID).
+		Order("id").
+		Find(&thresholdModels).Error; err != nil {
+		return nil, &apperror.InventoryError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch low stock thresholds",
+			Err:     err,
+		}
+	}
+
+	thresholds := make([]entity.LowStockThreshold, 0, len(thresholdModels))
+	for _, thresholdModel := range thresholdModels {
+		thresholds = append(thresholds, model.ConvertLowStockThresholdToEntity(thresholdModel))
+	}
+
+	return thresholds, nil
+}
+
+// UpsertThreshold создает или меняет порог товара либо категории. Порог товара
+// можно задать только для товара из ассортимента магазина.
+func (r *inventoryRepository) UpsertThreshold(
+	ctx context.Context,
+	setting inventory.ThresholdSetting,
+) (entity.LowStockThreshold, error) {
+	db := r.db.WithContext(ctx)
+
+	target, check := "category_id", db.Table("categories").Where("id = ?", setting.CategoryID)
+	message := "category not found"
+	if setting.ProductID != nil {
+		target = "product_id"
+		check = db.Table("shop_inventory").Where("product_id = ? AND shop_id = ?", setting.ProductID, setting.ShopID)
+		message = "product is not in the store assortment"
+	}
+
+	var count int64
+	if err := check.Count(&count).Error; err != nil {
+		return entity.LowStockThreshold{}, thresholdError(err)
+	}
+	if count == 0 {
+		return entity.LowStockThreshold{}, &apperror.InventoryError{
+			Code:    apperror.BadRequest,
+			Message: message,
+		}
+	}
+
+	var thresholdModel model.LowStockThreshold
+	if err := db.Raw(`
+		INSERT INTO low_stock_thresholds (shop_id, product_id, category_id, threshold)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (shop_id, `+target+`) WHERE `+target+` IS NOT NULL DO UPDATE SET
+			threshold = EXCLUDED.threshold,
+			updated_at = now()
+		RETURNING *`,
+		setting.ShopID, setting.ProductID, setting.CategoryID, setting.Threshold,
+	).Scan(&thresholdModel).Error; err != nil {
+		return entity.LowStockThreshold{}, thresholdError(err)
+	}
+
+	return model.ConvertLowStockThresholdToEntity(thresholdModel), nil
+}
+
+func (r *inventoryRepository) DeleteThreshold(ctx context.Context, shopID, thresholdID uint) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND shop_id = ?", thresholdID, shopID).
+		Delete(&model.LowStockThreshold{})
+	if result.Error != nil {
+		return thresholdError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrThresholdNotFound
+	}
+
+	return nil
+}
+
+// SelectLowStock получает позиции точек магазина, остаток которых не больше действующего порога.
+// Архивные товары в отчет не попадают.
+func (r *inventoryRepository) SelectLowStock(ctx context.Context, shopID uint) ([]entity.LowStockPoint, error) {
+	var rowModels []model.LowStockRow
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT * FROM (
+			SELECT
+				sp.id AS shop_point_id,
+				sp.address,
+				p.id AS product_id,
+				p.name AS product_name,
+				spi.variant_id,
+				pv.sku,
+				spi.quantity,
+				`+thresholdExpr+` AS threshold
+			FROM shop_point_inventory spi
+			JOIN shop_points sp ON sp.id = spi.shop_point_id
+			JOIN products p ON p.id = spi.product_id AND p.deleted_at IS NULL
+			JOIN product_variants pv ON pv.id = spi.variant_id
+			WHERE sp.shop_id = ?
+		) items
+		WHERE threshold IS NOT NULL AND quantity <= threshold
+		ORDER BY shop_point_id, product_name, variant_id`,
+		shopID,
+	).Scan(&rowModels).Error; err != nil {
+		return nil, &apperror.InventoryError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to build low stock report",
+			Err:     err,
+		}
+	}
+
+	points := []entity.LowStockPoint{}
+	for _, rowModel := range rowModels {
+		if len(points) == 0 || points[len(points)-1].ShopPointID != rowModel.ShopPointID {
+			points = append(points, entity.LowStockPoint{
+				ShopPointID: rowModel.ShopPointID,
+				Address:     rowModel.Address,
+			})
+		}
+		point := &points[len(points)-1]
+		point.Items = append(point.Items, model.ConvertLowStockRowToEntity(rowModel))
+	}
+
+	return points, nil
+}
+
+// NotifyLowStock забирает непроверенные уменьшения остатка, уведомляет владельцев магазинов
+// о позициях, остаток которых опустился до порога, и отмечает уменьшения проверенными
+// одной транзакцией. SKIP LOCKED не дает двум экземплярам приложения уведомить дважды.
+func (r *inventoryRepository) NotifyLowStock(
+	ctx context.Context,
+	limit int,
+	message func(entity.LowStockAlert) string,
+) (int, error) {
+	var processed int
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var alertModels []model.LowStockAlert
+		if err := tx.Raw(`
+			SELECT
+				m.id,
+				s.user_id AS owner_id,
+				m.shop_point_id,
+				sp.address,
+				p.name AS product_name,
+				pv.sku,
+				m.quantity,
+				m.quantity - m.delta AS previous_quantity,
+				`+thresholdExpr+` AS threshold
+			FROM inventory_movements m
+			JOIN shop_points sp ON sp.id = m.shop_point_id
+			JOIN shops s ON s.id = sp.shop_id
+			JOIN products p ON p.id = m.product_id
+			JOIN product_variants pv ON pv.id = m.variant_id
+			WHERE m.delta < 0 AND m.alert_checked_at IS NULL
+			ORDER BY m.id
+			LIMIT ?
+			FOR UPDATE OF m SKIP LOCKED`,
+			limit,
+		).Scan(&alertModels).Error; err != nil {
+			return err
+		}
+		if len(alertModels) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(alertModels))
+		for _, alertModel := range alertModels {
+			ids = append(ids, alertModel.ID)
+
+			alert := model.ConvertLowStockAlertToEntity(alertModel)
+			if !alert.Crossed() {
+				continue
+			}
+
+			notificationModel := model.Notification{
+				UserID:  alert.OwnerID,
+				Message: message(alert),
+				SentAt:  time.Now(),
+			}
+			if err := tx.Create(&notificationModel).Error; err != nil {
+				return err
+			}
+		}
+
+		processed = len(ids)
+		return tx.Model(&model.InventoryMovement{}).
+			Where("id IN ?", ids).
+			Update("alert_checked_at", time.Now()).Error
+	})
+	if err != nil {
+		return 0, &apperror.NotificationError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to notify about low stock",
+			Err:     err,
+		}
+	}
+
+	return processed, nil
+}
+
+func thresholdError(err error) error {
+	return &apperror.InventoryError{
+		Code:    apperror.DatabaseError,
+		Message: "failed to save low stock threshold",
+		Err:     err,
+	}
+}
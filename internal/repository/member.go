@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/staff"
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetMemberRole возвращает роль пользователя в магазине, пустую строку если он не сотрудник
+func (r *storeRepository) GetMemberRole(ctx context.Context, shopID, userID uint) (string, error) {
+	var roles []string
+	if err := r.db.WithContext(ctx).
+		Model(&model.ShopMember{}).
+		Where("shop_id = ? AND user_id = ?", shopID, userID).
+		Pluck("role", &roles).Error; err != nil {
+		return "", memberError(err)
+	}
+	if len(roles) == 0 {
+		return "", nil
+	}
+
+	return roles[0], nil
+}
+
+// GetProductRoles возвращает роли пользователя во всех магазинах, в ассортименте которых есть товар
+func (r *storeRepository) GetProductRoles(ctx context.Context, productID, userID uint) ([]string, error) {
+	var roles []string
+	if err := r.db.WithContext(ctx).
+		Table("shop_inventory si").
+		Joins("JOIN shop_members sm ON sm.shop_id = si.shop_id").
+		Where("si.product_id = ? AND sm.user_id = ?", productID, userID).
+		Distinct().
+		Pluck("sm.role", &roles).Error; err != nil {
+		return nil, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to check product store role",
+			Err:     err,
+		}
+	}
+
+	return roles, nil
+}
+
+func (r *storeRepository) SelectMembers(ctx context.Context, shopID uint) ([]entity.StoreMember, error) {
+	var memberModels []model.StoreMember
+	if err := r.db.WithContext(ctx).
+		Table("shop_members sm").
+		Select("sm.*, u.name, u.email").
+		Joins("JOIN users u ON u.id = sm.user_id").
+		Where("sm.shop_id = ?", shopID).
+		Order("sm.created_at, sm.id").
+		Scan(&memberModels).Error; err != nil {
+		return nil, memberError(err)
+	}
+
+	members := make([]entity.StoreMember, 0, len(memberModels))
+	for _, memberModel := range memberModels {
+		members = append(members, model.ConvertStoreMemberToEntity(memberModel))
+	}
+
+	return members, nil
+}
+
+// DeleteMember убирает сотрудника и отправляет уведомление одной транзакцией.
+// Владельца так убрать нельзя.
+func (r *storeRepository) DeleteMember(ctx context.Context, shopID, userID uint, notice staff.Notice) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("shop_id = ? AND user_id = ? AND role <> ?", shopID, userID, entity.StoreRoleOwner).
+			Delete(&model.ShopMember{})
+		if result.Error != nil {
+			return memberError(result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return apperror.ErrStoreMemberNotFound
+		}
+
+		return insertNotice(tx, notice)
+	})
+}
+
+// InsertInvitation заменяет неиспользованное приглашение на тот же адрес новым
+func (r *storeRepository) InsertInvitation(
+	ctx context.Context,
+	invitation staff.Invitation,
+) (entity.StoreInvitation, error) {
+	invitationModel := model.ConvertInvitationFromSvc(invitation)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("shop_id = ? AND lower(email) = lower(?) AND accepted_at IS NULL",
+			invitation.StoreID, invitation.Email).
+			Delete(&model.ShopInvitation{}).Error; err != nil {
+			return memberError(err)
+		}
+
+		if err := tx.Create(&invitationModel).Error; err != nil {
+			if isDuplicateError(err) {
+				return &apperror.StoreError{
+					Code:    apperror.Conflict,
+					Message: "invitation is already being sent",
+					Err:     err,
+				}
+			}
+			return memberError(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.StoreInvitation{}, err
+	}
+
+	return model.ConvertInvitationToEntity(invitationModel), nil
+}
+
+// SelectInvitations возвращает неиспользованные приглашения, срок которых не истек
+func (r *storeRepository) SelectInvitations(ctx context.Context, shopID uint) ([]entity.StoreInvitation, error) {
+	var invitationModels []model.ShopInvitation
+	if err := r.db.WithContext(ctx).
+		Where("shop_id = ? AND accepted_at IS NULL AND expires_at > now()", shopID).
+		Order("created_at DESC, id DESC").
+		Find(&invitationModels).Error; err != nil {
+		return nil, memberError(err)
+	}
+
+	invitations := make([]entity.StoreInvitation, 0, len(invitationModels))
+	for _, invitationModel := range invitationModels {
+		invitations = append(invitations, model.ConvertInvitationToEntity(invitationModel))
+	}
+
+	return invitations, nil
+}
+
+// DeleteInvitation отзывает неиспользованное приглашение
+func (r *storeRepository) DeleteInvitation(ctx context.Context, shopID, invitationID uint) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND shop_id = ? AND accepted_at IS NULL", invitationID, shopID).
+		Delete(&model.ShopInvitation{})
+	if result.Error != nil {
+		return memberError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrStoreInvitationNotFound
+	}
+
+	return nil
+}
+
+func (r *storeRepository) GetInvitationByToken(ctx context.Context, tokenHash string) (entity.StoreInvitation, error) {
+	var invitationModel model.ShopInvitation
+	if err := r.db.WithContext(ctx).
+		Where("token_hash = ?", tokenHash).
+		Take(&invitationModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.StoreInvitation{}, apperror.ErrStoreInvitationNotFound
+		}
+		return entity.StoreInvitation{}, memberError(err)
+	}
+
+	return model.ConvertInvitationToEntity(invitationModel), nil
+}
+
+// AcceptInvitation добавляет пользователя в сотрудники по неиспользованному приглашению
+// и отправляет уведомление одной транзакцией. Приглашение блокируется, чтобы его
+// нельзя было принять дважды параллельными запросами.
+func (r *storeRepository) AcceptInvitation(
+	ctx context.Context,
+	invitationID, userID uint,
+	notice staff.Notice,
+) (entity.StoreMember, error) {
+	var memberModel model.ShopMember
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var invitationModel model.ShopInvitation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND accepted_at IS NULL AND expires_at > now()", invitationID).
+			Take(&invitationModel).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrStoreInvitationNotFound
+			}
+			return memberError(err)
+		}
+
+		memberModel = model.ShopMember{
+			ShopID: invitationModel.ShopID,
+			UserID: userID,
+			Role:   invitationModel.Role,
+		}
+		if err := tx.Omit("created_at").Create(&memberModel).Error; err != nil {
+			if isDuplicateError(err) {
+				return &apperror.StoreError{
+					Code:    apperror.Conflict,
+					Message: "user is already a member of the store",
+					Err:     err,
+				}
+			}
+			return memberError(err)
+		}
+
+		if err := tx.Model(&invitationModel).
+			Update("accepted_at", gorm.Expr("now()")).Error; err != nil {
+			return memberError(err)
+		}
+
+		return insertNotice(tx, notice)
+	})
+	if err != nil {
+		return entity.StoreMember{}, err
+	}
+
+	return r.getMember(ctx, memberModel.ShopID, userID)
+}
+
+func (r *storeRepository) getMember(ctx context.Context, shopID, userID uint) (entity.StoreMember, error) {
+	var memberModel model.StoreMember
+	if err := r.db.WithContext(ctx).
+		Table("shop_members sm").
+		Select("sm.*, u.name, u.email").
+		Joins("JOIN users u ON u.id = sm.user_id").
+		Where("sm.shop_id = ? AND sm.user_id = ?", shopID, userID).
+		Take(&memberModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.StoreMember{}, apperror.ErrStoreMemberNotFound
+		}
+		return entity.StoreMember{}, memberError(err)
+	}
+
+	return model.ConvertStoreMemberToEntity(memberModel), nil
+}
+
+func insertNotice(tx *gorm.DB, notice staff.Notice) error {
+	notificationModel := model.Notification{
+		UserID:  notice.UserID,
+		Message: notice.Message,
+		SentAt:  time.Now(),
+	}
+	if err := tx.Create(&notificationModel).Error; err != nil {
+		return memberError(err)
+	}
+
+	return nil
+}
+
+func memberError(err error) error {
+	return &apperror.StoreError{
+		Code:    apperror.DatabaseError,
+		Message: "failed to access store members",
+		Err:     err,
+	}
+}
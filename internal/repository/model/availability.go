@@ -0,0 +1,41 @@
+package model
+
+import "github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+
+// AvailabilityRow товар в ассортименте магазина и его остаток на одной точке продаж,
+// поля точки пустые, если товара нет ни на одной точке магазина
+type AvailabilityRow struct {
+	ProductID   uint     `gorm:"column:product_id"`
+	ShopID      uint     `gorm:"column:shop_id"`
+	ShopName    string   `gorm:"column:shop_name"`
+	IsAvailable bool     `gorm:"column:is_available"`
+	ShopPointID *uint    `gorm:"column:shop_point_id"`
+	Address     string   `gorm:"column:address"`
+	Quantity    int      `gorm:"column:quantity"`
+	MinPrice    *float64 `gorm:"column:min_price"`
+	MaxPrice    *float64 `gorm:"column:max_price"`
+}
+
+func ConvertAvailabilityRowToShop(r AvailabilityRow) entity.ShopAvailability {
+	return entity.ShopAvailability{
+		ShopID:      r.ShopID,
+		Name:        r.ShopName,
+		IsAvailable: r.IsAvailable,
+		Points:      []entity.PointAvailability{},
+	}
+}
+
+// ConvertAvailabilityRowToPoint возвращает false для магазина без точек с этим товаром
+func ConvertAvailabilityRowToPoint(r AvailabilityRow) (entity.PointAvailability, bool) {
+	if r.ShopPointID == nil {
+		return entity.PointAvailability{}, false
+	}
+
+	return entity.PointAvailability{
+		ShopPointID: *r.ShopPointID,
+		Address:     r.Address,
+		Quantity:    r.Quantity,
+		MinPrice:    r.MinPrice,
+		MaxPrice:    r.MaxPrice,
+	}, true
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+type Catalog struct {
+	ID               uint      `gorm:"column:id"`
+	Name             string    `gorm:"column:name"`
+	Currency         string    `gorm:"column:currency"`
+	CatalogUpdatedAt time.Time `gorm:"column:catalog_updated_at"`
+}
+
+type CatalogCategory struct {
+	ID       uint   `gorm:"column:id"`
+	Name     string `gorm:"column:name"`
+	ParentID *uint  `gorm:"column:parent_id"`
+}
+
+// CatalogRow строка выгрузки: товар и одна позиция его варианта на точке продаж,
+// поля позиции пустые, если товара нет ни на одной точке
+type CatalogRow struct {
+	ProductID   uint     `gorm:"column:product_id"`
+	Name        string   `gorm:"column:name"`
+	Description string   `gorm:"column:description"`
+	CategoryID  *uint    `gorm:"column:category_id"`
+	Attributes  string   `gorm:"column:attributes"`
+	ImageKeys   string   `gorm:"column:image_keys"`
+	IsAvailable bool     `gorm:"column:is_available"`
+	VariantID   *uint    `gorm:"column:variant_id"`
+	SKU         string   `gorm:"column:sku"`
+	Barcode     string   `gorm:"column:barcode"`
+	Options     string   `gorm:"column:options"`
+	ShopPointID *uint    `gorm:"column:shop_point_id"`
+	Address     string   `gorm:"column:address"`
+	Price       *float64 `gorm:"column:price"`
+	Quantity    *int     `gorm:"column:quantity"`
+}
+
+func ConvertCatalogToEntity(c Catalog) entity.Catalog {
+	return entity.Catalog{
+		ShopID:    c.ID,
+		ShopName:  c.Name,
+		Currency:  c.Currency,
+		UpdatedAt: c.CatalogUpdatedAt,
+	}
+}
+
+func ConvertCatalogCategoryToEntity(c CatalogCategory) entity.CatalogCategory {
+	return entity.CatalogCategory{
+		ID:       c.ID,
+		Name:     c.Name,
+		ParentID: c.ParentID,
+	}
+}
+
+func ConvertCatalogRowToEntity(r CatalogRow) (entity.CatalogProduct, error) {
+	product := entity.CatalogProduct{
+		ID:          r.ProductID,
+		Name:        r.Name,
+		Description: r.Description,
+		CategoryID:  r.CategoryID,
+		Available:   r.IsAvailable,
+	}
+
+	if r.Attributes != "" {
+		if err := json.Unmarshal([]byte(r.Attributes), &product.Attributes); err != nil {
+			return entity.CatalogProduct{}, err
+		}
+	}
+	if err := json.Unmarshal([]byte(r.ImageKeys), &product.ImageKeys); err != nil {
+		return entity.CatalogProduct{}, err
+	}
+
+	return product, nil
+}
+
+// ConvertCatalogRowToStock возвращает позицию строки, false если ее нет
+func ConvertCatalogRowToStock(r CatalogRow) (entity.CatalogStock, bool, error) {
+	if r.VariantID == nil || r.ShopPointID == nil || r.Price == nil || r.Quantity == nil {
+		return entity.CatalogStock{}, false, nil
+	}
+
+	stock := entity.CatalogStock{
+		VariantID:   *r.VariantID,
+		SKU:         r.SKU,
+		Barcode:     r.Barcode,
+		ShopPointID: *r.ShopPointID,
+		Address:     r.Address,
+		Price:       *r.Price,
+		Quantity:    *r.Quantity,
+	}
+	if r.Options != "" {
+		if err := json.Unmarshal([]byte(r.Options), &stock.Options); err != nil {
+			return entity.CatalogStock{}, false, err
+		}
+	}
+
+	return stock, true, nil
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"encoding/json"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/category"
+)
+
+type CategoryAttribute struct {
+	ID            uint            `gorm:"column:id;primaryKey;autoIncrement"`
+	CategoryID    uint            `gorm:"column:category_id"`
+	Name          string          `gorm:"column:name"`
+	Type          string          `gorm:"column:type"`
+	Unit          string          `gorm:"column:unit"`
+	Required      bool            `gorm:"column:required"`
+	AllowedValues json.RawMessage `gorm:"column:allowed_values;type:jsonb"`
+}
+
+func ConvertCategoryAttributeFromSvc(a category.Attribute) CategoryAttribute {
+	allowed := a.AllowedValues
+	if allowed == nil {
+		allowed = []string{}
+	}
+	// []string всегда сериализуется без ошибок
+	raw, _ := json.Marshal(allowed)
+
+	return CategoryAttribute{
+		CategoryID:    a.CategoryID,
+		Name:          a.Name,
+		Type:          a.Type,
+		Unit:          a.Unit,
+		Required:      a.Required,
+		AllowedValues: raw,
+	}
+}
+
+func ConvertCategoryAttributeToEntity(a CategoryAttribute) entity.CategoryAttribute {
+	var allowed []string
+	if len(a.AllowedValues) > 0 {
+		_ = json.Unmarshal(a.AllowedValues, &allowed)
+	}
+
+	return entity.CategoryAttribute{
+		ID:            a.ID,
+		CategoryID:    a.CategoryID,
+		Name:          a.Name,
+		Type:          a.Type,
+		Unit:          a.Unit,
+		Required:      a.Required,
+		AllowedValues: allowed,
+	}
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/user"
+)
+
+type EmailVerification struct {
+	ID        uint       `gorm:"column:id;primaryKey"`
+	UserID    uint       `gorm:"column:user_id"`
+	Email     string     `gorm:"column:email"`
+	TokenHash string     `gorm:"column:token_hash"`
+	ExpiresAt time.Time  `gorm:"column:expires_at"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at"`
+}
+
+func (EmailVerification) TableName() string {
+	return "email_verifications"
+}
+
+func ConvertEmailVerificationFromSvc(v user.EmailVerification) EmailVerification {
+	return EmailVerification{
+		UserID:    v.UserID,
+		Email:     v.Email,
+		TokenHash: v.TokenHash,
+		ExpiresAt: v.ExpiresAt,
+	}
+}
+
+func ConvertEmailVerificationToEntity(v EmailVerification) entity.EmailVerification {
+	return entity.EmailVerification{
+		ID:        v.ID,
+		UserID:    v.UserID,
+		Email:     v.Email,
+		ExpiresAt: v.ExpiresAt,
+		UsedAt:    v.UsedAt,
+		CreatedAt: v.CreatedAt,
+	}
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/image"
+)
+
+type ImageKey struct {
+	ID           uint      `gorm:"column:id;primaryKey;autoIncrement"`
+	ImageKey     string    `gorm:"column:image_key"`
+	ProductID    uint      `gorm:"column:product_id"`
+	Position     int       `gorm:"column:position"`
+	ContentType  string    `gorm:"column:content_type"`
+	Size         int64     `gorm:"column:size"`
+	ETag         string    `gorm:"column:etag"`
+	VariantsETag string    `gorm:"column:variants_etag"`
+	CreatedAt    time.Time `gorm:"column:created_at"`
+}
+
+func ConvertImageFromSvc(i image.Image) ImageKey {
+	return ImageKey{
+		ImageKey:    i.Key,
+		ProductID:   i.ProductID,
+		ContentType: i.ContentType,
+		Size:        i.Size,
+		ETag:        i.ETag,
+	}
+}
+
+func ConvertImageToEntity(i ImageKey) entity.ProductImage {
+	return entity.ProductImage{
+		ID:           i.ID,
+		ProductID:    i.ProductID,
+		Key:          i.ImageKey,
+		Position:     i.Position,
+		ContentType:  i.ContentType,
+		Size:         i.Size,
+		ETag:         i.ETag,
+		VariantsETag: i.VariantsETag,
+		CreatedAt:    i.CreatedAt,
+	}
+}
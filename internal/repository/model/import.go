@@ -0,0 +1,93 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/importjob"
+)
+
+type ImportJob struct {
+	ID           uint            `gorm:"column:id;primaryKey;autoIncrement"`
+	ShopID       uint            `gorm:"column:shop_id"`
+	UserID       uint            `gorm:"column:user_id"`
+	Format       string          `gorm:"column:format"`
+	Status       string          `gorm:"column:status"`
+	FileKey      string          `gorm:"column:file_key"`
+	Mapping      json.RawMessage `gorm:"column:mapping;type:jsonb"`
+	TotalRows    int             `gorm:"column:total_rows"`
+	ImportedRows int             `gorm:"column:imported_rows"`
+	FailedRows   int             `gorm:"column:failed_rows"`
+	Error        string          `gorm:"column:error"`
+	CreatedAt    time.Time       `gorm:"column:created_at"`
+	StartedAt    *time.Time      `gorm:"column:started_at"`
+	FinishedAt   *time.Time      `gorm:"column:finished_at"`
+	UpdatedAt    time.Time       `gorm:"column:updated_at"`
+}
+
+type ImportJobError struct {
+	ID        uint   `gorm:"column:id;primaryKey;autoIncrement"`
+	JobID     uint   `gorm:"column:job_id"`
+	RowNumber int    `gorm:"column:row_number"`
+	Field     string `gorm:"column:field"`
+	Message   string `gorm:"column:message"`
+}
+
+func ConvertImportJobFromSvc(j importjob.Job) (ImportJob, error) {
+	mapping, err := json.Marshal(j.Mapping)
+	if err != nil {
+		return ImportJob{}, err
+	}
+
+	return ImportJob{
+		ShopID:  j.ShopID,
+		UserID:  j.UserID,
+		Format:  j.Format,
+		Status:  entity.ImportStatusPending,
+		FileKey: j.FileKey,
+		Mapping: mapping,
+	}, nil
+}
+
+func ConvertImportJobToEntity(j ImportJob) entity.ImportJob {
+	mapping := make(map[string]string)
+	if len(j.Mapping) > 0 {
+		// отображение пишется только из Job, поэтому ошибки разбора не ожидаются
+		_ = json.Unmarshal(j.Mapping, &mapping)
+	}
+
+	return entity.ImportJob{
+		ID:           j.ID,
+		ShopID:       j.ShopID,
+		UserID:       j.UserID,
+		Format:       j.Format,
+		Status:       j.Status,
+		FileKey:      j.FileKey,
+		Mapping:      mapping,
+		TotalRows:    j.TotalRows,
+		ImportedRows: j.ImportedRows,
+		FailedRows:   j.FailedRows,
+		Error:        j.Error,
+		CreatedAt:    j.CreatedAt,
+		StartedAt:    j.StartedAt,
+		FinishedAt:   j.FinishedAt,
+	}
+}
+
+func ConvertImportRowErrorFromEntity(jobID uint, e entity.ImportRowError) ImportJobError {
+	return ImportJobError{
+		JobID:     jobID,
+		RowNumber: e.Row,
+		Field:     e.Field,
+		Message:   e.Message,
+	}
+}
+
+func ConvertImportRowErrorToEntity(e ImportJobError) entity.ImportRowError {
+	return entity.ImportRowError{
+		Row:     e.RowNumber,
+		Field:   e.Field,
+		Message: e.Message,
+	}
+}
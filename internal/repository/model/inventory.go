@@ -0,0 +1,141 @@
+package model
+
+import (
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+// PointInventory позиция варианта товара на точке продаж
+type PointInventory struct {
+	ShopPointID uint      `gorm:"column:shop_point_id"`
+	ProductID   uint      `gorm:"column:product_id"`
+	VariantID   uint      `gorm:"column:variant_id"`
+	Price       float64   `gorm:"column:price"`
+	Quantity    int       `gorm:"column:quantity"`
+	UpdatedAt   time.Time `gorm:"column:updated_at"`
+}
+
+func (PointInventory) TableName() string {
+	return "shop_point_inventory"
+}
+
+// InventoryItem позиция вместе с названием товара и артикулом варианта
+type InventoryItem struct {
+	PointInventory
+	ProductName string `gorm:"column:product_name"`
+	SKU         string `gorm:"column:sku"`
+}
+
+type InventoryMovement struct {
+	ID          uint      `gorm:"column:id;primaryKey;autoIncrement"`
+	ShopPointID uint      `gorm:"column:shop_point_id"`
+	ProductID   uint      `gorm:"column:product_id"`
+	VariantID   uint      `gorm:"column:variant_id"`
+	Delta       int       `gorm:"column:delta"`
+	Quantity    int       `gorm:"column:quantity"`
+	Price       float64   `gorm:"column:price"`
+	Reason      string    `gorm:"column:reason"`
+	Note        string    `gorm:"column:note"`
+	UserID      *uint     `gorm:"column:user_id"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+}
+
+func ConvertInventoryItemToEntity(i InventoryItem) entity.InventoryItem {
+	return entity.InventoryItem{
+		ShopPointID: i.ShopPointID,
+		ProductID:   i.ProductID,
+		VariantID:   i.VariantID,
+		ProductName: i.ProductName,
+		SKU:         i.SKU,
+		Price:       i.Price,
+		Quantity:    i.Quantity,
+		UpdatedAt:   i.UpdatedAt,
+	}
+}
+
+func ConvertInventoryMovementToEntity(m InventoryMovement) entity.InventoryMovement {
+	return entity.InventoryMovement{
+		ID:          m.ID,
+		ShopPointID: m.ShopPointID,
+		ProductID:   m.ProductID,
+		VariantID:   m.VariantID,
+		Delta:       m.Delta,
+		Quantity:    m.Quantity,
+		Price:       m.Price,
+		Reason:      m.Reason,
+		Note:        m.Note,
+		UserID:      m.UserID,
+		CreatedAt:   m.CreatedAt,
+	}
+}
+
+type LowStockThreshold struct {
+	ID         uint      `gorm:"column:id;primaryKey;autoIncrement"`
+	ShopID     uint      `gorm:"column:shop_id"`
+	ProductID  *uint     `gorm:"column:product_id"`
+	CategoryID *uint     `gorm:"column:category_id"`
+	Threshold  int       `gorm:"column:threshold"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+	UpdatedAt  time.Time `gorm:"column:updated_at"`
+}
+
+// LowStockRow заканчивающаяся позиция вместе с точкой продаж
+type LowStockRow struct {
+	ShopPointID uint   `gorm:"column:shop_point_id"`
+	Address     string `gorm:"column:address"`
+	ProductID   uint   `gorm:"column:product_id"`
+	ProductName string `gorm:"column:product_name"`
+	VariantID   uint   `gorm:"column:variant_id"`
+	SKU         string `gorm:"column:sku"`
+	Quantity    int    `gorm:"column:quantity"`
+	Threshold   int    `gorm:"column:threshold"`
+}
+
+type LowStockAlert struct {
+	ID               uint   `gorm:"column:id"`
+	OwnerID          uint   `gorm:"column:owner_id"`
+	ShopPointID      uint   `gorm:"column:shop_point_id"`
+	Address          string `gorm:"column:address"`
+	ProductName      string `gorm:"column:product_name"`
+	SKU              string `gorm:"column:sku"`
+	Quantity         int    `gorm:"column:quantity"`
+	PreviousQuantity int    `gorm:"column:previous_quantity"`
+	Threshold        *int   `gorm:"column:threshold"`
+}
+
+func ConvertLowStockThresholdToEntity(t LowStockThreshold) entity.LowStockThreshold {
+	return entity.LowStockThreshold{
+		ID:         t.ID,
+		ShopID:     t.ShopID,
+		ProductID:  t.ProductID,
+		CategoryID: t.CategoryID,
+		Threshold:  t.Threshold,
+		UpdatedAt:  t.UpdatedAt,
+	}
+}
+
+func ConvertLowStockRowToEntity(r LowStockRow) entity.LowStockItem {
+	return entity.LowStockItem{
+		ProductID:   r.ProductID,
+		ProductName: r.ProductName,
+		VariantID:   r.VariantID,
+		SKU:         r.SKU,
+		Quantity:    r.Quantity,
+		Threshold:   r.Threshold,
+	}
+}
+
+func ConvertLowStockAlertToEntity(a LowStockAlert) entity.LowStockAlert {
+	return entity.LowStockAlert{
+		MovementID:       a.ID,
+		OwnerID:          a.OwnerID,
+		ShopPointID:      a.ShopPointID,
+		Address:          a.Address,
+		ProductName:      a.ProductName,
+		SKU:              a.SKU,
+		Quantity:         a.Quantity,
+		PreviousQuantity: a.PreviousQuantity,
+		Threshold:        a.Threshold,
+	}
+}
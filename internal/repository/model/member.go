@@ -0,0 +1,78 @@
+package model
+
+import (
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/staff"
+)
+
+type ShopMember struct {
+	ID        uint      `gorm:"column:id;primaryKey"`
+	ShopID    uint      `gorm:"column:shop_id"`
+	UserID    uint      `gorm:"column:user_id"`
+	Role      string    `gorm:"column:role"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (ShopMember) TableName() string {
+	return "shop_members"
+}
+
+// StoreMember сотрудник магазина вместе с именем и почтой пользователя
+type StoreMember struct {
+	ShopMember
+	Name  string `gorm:"column:name"`
+	Email string `gorm:"column:email"`
+}
+
+type ShopInvitation struct {
+	ID         uint       `gorm:"column:id;primaryKey"`
+	ShopID     uint       `gorm:"column:shop_id"`
+	Email      string     `gorm:"column:email"`
+	Role       string     `gorm:"column:role"`
+	TokenHash  string     `gorm:"column:token_hash"`
+	InvitedBy  uint       `gorm:"column:invited_by"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at"`
+	AcceptedAt *time.Time `gorm:"column:accepted_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+}
+
+func (ShopInvitation) TableName() string {
+	return "shop_invitations"
+}
+
+func ConvertStoreMemberToEntity(m StoreMember) entity.StoreMember {
+	return entity.StoreMember{
+		StoreID:   m.ShopID,
+		UserID:    m.UserID,
+		Name:      m.Name,
+		Email:     m.Email,
+		Role:      m.Role,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+func ConvertInvitationFromSvc(i staff.Invitation) ShopInvitation {
+	return ShopInvitation{
+		ShopID:    i.StoreID,
+		Email:     i.Email,
+		Role:      i.Role,
+		TokenHash: i.TokenHash,
+		InvitedBy: i.InvitedBy,
+		ExpiresAt: i.ExpiresAt,
+	}
+}
+
+func ConvertInvitationToEntity(i ShopInvitation) entity.StoreInvitation {
+	return entity.StoreInvitation{
+		ID:         i.ID,
+		StoreID:    i.ShopID,
+		Email:      i.Email,
+		Role:       i.Role,
+		InvitedBy:  i.InvitedBy,
+		ExpiresAt:  i.ExpiresAt,
+		AcceptedAt: i.AcceptedAt,
+		CreatedAt:  i.CreatedAt,
+	}
+}
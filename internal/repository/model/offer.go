@@ -3,6 +3,7 @@ package model
 import (
 	"time"
 
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
 	"github.com/zuzaaa-dev/stawberry/internal/domain/service/offer"
 )
 
@@ -10,26 +11,64 @@ type Offer struct {
 	ID        uint `gorm:"primaryKey;autoIncrement"`
 	UserID    uint
 	ProductID uint
-	StoreID   uint
-	Price     float64
-	Status    string
-	ExpiresAt time.Time
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Product   Product `gorm:"foreignKey:ProductID"`
-	Store     Store   `gorm:"foreignKey:StoreID"`
+	VariantID uint
+	// StoreID пустой у старых предложений, для которых магазин не удалось определить
+	StoreID     *uint
+	ShopPointID *uint
+	Price       float64
+	ListPrice   *float64
+	Status      string
+	ExpiresAt   time.Time
+	RespondedAt *time.Time
+	Rating      *int
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Product     Product `gorm:"foreignKey:ProductID"`
+	Store       Store   `gorm:"foreignKey:StoreID"`
 }
 
 func ConvertOfferFromSvc(offer offer.Offer) Offer {
+	var storeID *uint
+	if offer.StoreID != 0 {
+		storeID = &offer.StoreID
+	}
+
 	return Offer{
-		ID:        offer.ID,
-		UserID:    offer.UserID,
-		ProductID: offer.ProductID,
-		StoreID:   offer.StoreID,
-		Price:     offer.Price,
-		Status:    offer.Status,
-		ExpiresAt: offer.ExpiresAt,
-		CreatedAt: offer.CreatedAt,
-		UpdatedAt: offer.UpdatedAt,
+		ID:          offer.ID,
+		UserID:      offer.UserID,
+		ProductID:   offer.ProductID,
+		StoreID:     storeID,
+		ShopPointID: offer.ShopPointID,
+		Price:       offer.Price,
+		Status:      offer.Status,
+		ExpiresAt:   offer.ExpiresAt,
+		CreatedAt:   offer.CreatedAt,
+		UpdatedAt:   offer.UpdatedAt,
+	}
+}
+
+func ConvertOfferToEntity(o Offer) entity.Offer {
+	var storeID uint
+	if o.StoreID != nil {
+		storeID = *o.StoreID
+	}
+
+	return entity.Offer{
+		ID:          o.ID,
+		UserID:      o.UserID,
+		ProductID:   o.ProductID,
+		VariantID:   o.VariantID,
+		StoreID:     storeID,
+		ShopPointID: o.ShopPointID,
+		Price:       o.Price,
+		ListPrice:   o.ListPrice,
+		Status:      o.Status,
+		ExpiresAt:   o.ExpiresAt,
+		RespondedAt: o.RespondedAt,
+		Rating:      o.Rating,
+		CompletedAt: o.CompletedAt,
+		CreatedAt:   o.CreatedAt,
+		UpdatedAt:   o.UpdatedAt,
 	}
 }
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+type PriceHistory struct {
+	ID            uint      `gorm:"column:id;primaryKey;autoIncrement"`
+	ProductID     uint      `gorm:"column:product_id"`
+	VariantID     *uint     `gorm:"column:variant_id"`
+	ShopPointID   *uint     `gorm:"column:shop_point_id"`
+	Price         float64   `gorm:"column:price"`
+	PreviousPrice *float64  `gorm:"column:previous_price"`
+	ChangedAt     time.Time `gorm:"column:changed_at"`
+}
+
+func (PriceHistory) TableName() string {
+	return "price_history"
+}
+
+type PriceStats struct {
+	Min     *float64 `gorm:"column:min"`
+	Max     *float64 `gorm:"column:max"`
+	Avg     *float64 `gorm:"column:avg"`
+	Changes int      `gorm:"column:changes"`
+}
+
+type PriceDrop struct {
+	ID            uint     `gorm:"column:id"`
+	ProductID     uint     `gorm:"column:product_id"`
+	ProductName   string   `gorm:"column:product_name"`
+	VariantID     *uint    `gorm:"column:variant_id"`
+	ShopPointID   *uint    `gorm:"column:shop_point_id"`
+	Address       string   `gorm:"column:address"`
+	Price         float64  `gorm:"column:price"`
+	PreviousPrice float64  `gorm:"column:previous_price"`
+	LowestPrice   *float64 `gorm:"column:lowest_price"`
+}
+
+func ConvertPriceHistoryToEntity(h PriceHistory) entity.PricePoint {
+	return entity.PricePoint{
+		VariantID:     h.VariantID,
+		ShopPointID:   h.ShopPointID,
+		Price:         h.Price,
+		PreviousPrice: h.PreviousPrice,
+		ChangedAt:     h.ChangedAt,
+	}
+}
+
+func ConvertPriceStatsToEntity(s PriceStats) entity.PriceStats {
+	return entity.PriceStats{
+		Min:     s.Min,
+		Max:     s.Max,
+		Avg:     s.Avg,
+		Changes: s.Changes,
+	}
+}
+
+func ConvertPriceDropToEntity(d PriceDrop) entity.PriceDrop {
+	return entity.PriceDrop{
+		ID:            d.ID,
+		ProductID:     d.ProductID,
+		ProductName:   d.ProductName,
+		VariantID:     d.VariantID,
+		ShopPointID:   d.ShopPointID,
+		Address:       d.Address,
+		Price:         d.Price,
+		PreviousPrice: d.PreviousPrice,
+		LowestPrice:   d.LowestPrice,
+	}
+}
@@ -1,43 +1,61 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
 	"github.com/zuzaaa-dev/stawberry/internal/domain/service/product"
+	"gorm.io/gorm"
 )
 
+// Product товар каталога. Магазины, которые его продают, связаны с ним через shop_inventory.
 type Product struct {
 	ID          uint `gorm:"primaryKey;autoIncrement"`
-	StoreID     uint
 	Name        string
 	Description string
 	Price       float64
-	Category    string
-	InStock     bool
+	CategoryID  *uint `gorm:"column:category_id"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
-	Store       Store `gorm:"foreignKey:StoreID"`
+	// DeletedAt время архивации, архивные товары скрыты из каталога
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index"`
 }
 
 type UpdateProduct struct {
-	StoreID     *uint    `gorm:"column:store_id"`
 	Name        *string  `gorm:"column:name"`
 	Description *string  `gorm:"column:description"`
 	Price       *float64 `gorm:"column:price"`
-	Category    *string  `gorm:"column:category"`
-	InStock     *bool    `gorm:"column:in_stock"`
+	CategoryID  *uint    `gorm:"column:category_id"`
+}
+
+// ShopInventory товар в ассортименте магазина
+type ShopInventory struct {
+	ProductID   uint `gorm:"column:product_id;primaryKey"`
+	ShopID      uint `gorm:"column:shop_id;primaryKey"`
+	IsAvailable bool `gorm:"column:is_available"`
+}
+
+func (ShopInventory) TableName() string {
+	return "shop_inventory"
+}
+
+type ProductAttributes struct {
+	ProductID  uint            `gorm:"column:product_id"`
+	Attributes json.RawMessage `gorm:"column:attributes;type:jsonb"`
+}
+
+func (ProductAttributes) TableName() string {
+	return "product_attributes"
 }
 
 func ConvertProductFromSvc(p product.Product) Product {
 	return Product{
 		ID:          p.ID,
-		StoreID:     p.StoreID,
 		Name:        p.Name,
 		Description: p.Description,
 		Price:       p.Price,
-		Category:    p.Category,
-		InStock:     p.InStock,
+		CategoryID:  p.CategoryID,
 		CreatedAt:   p.CreatedAt,
 		UpdatedAt:   p.UpdatedAt,
 	}
@@ -46,12 +64,10 @@ func ConvertProductFromSvc(p product.Product) Product {
 func ConvertProductToEntity(p Product) entity.Product {
 	return entity.Product{
 		ID:          p.ID,
-		StoreID:     p.StoreID,
 		Name:        p.Name,
 		Description: p.Description,
 		Price:       p.Price,
-		Category:    p.Category,
-		InStock:     p.InStock,
+		CategoryID:  p.CategoryID,
 		CreatedAt:   p.CreatedAt,
 		UpdatedAt:   p.UpdatedAt,
 	}
@@ -59,11 +75,27 @@ func ConvertProductToEntity(p Product) entity.Product {
 
 func ConvertUpdateProductFromSvc(up product.UpdateProduct) UpdateProduct {
 	return UpdateProduct{
-		StoreID:     up.StoreID,
 		Name:        up.Name,
 		Description: up.Description,
 		Price:       up.Price,
-		Category:    up.Category,
-		InStock:     up.InStock,
+		CategoryID:  up.CategoryID,
 	}
 }
+
+func ConvertProductAttributesFromSvc(productID uint, attributes map[string]any) (ProductAttributes, error) {
+	raw, err := json.Marshal(attributes)
+	if err != nil {
+		return ProductAttributes{}, err
+	}
+
+	return ProductAttributes{ProductID: productID, Attributes: raw}, nil
+}
+
+func ConvertProductAttributesToEntity(pa ProductAttributes) (map[string]any, error) {
+	attributes := make(map[string]any)
+	if err := json.Unmarshal(pa.Attributes, &attributes); err != nil {
+		return nil, err
+	}
+
+	return attributes, nil
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+// ShopPointHours период работы точки продаж в день недели. Время хранится в колонке TIME
+// и читается строкой вида "09:30:00".
+type ShopPointHours struct {
+	ID          uint   `gorm:"column:id;primaryKey"`
+	ShopPointID uint   `gorm:"column:shop_point_id"`
+	Weekday     int    `gorm:"column:weekday"`
+	OpensAt     string `gorm:"column:opens_at"`
+	ClosesAt    string `gorm:"column:closes_at"`
+}
+
+func (ShopPointHours) TableName() string {
+	return "shop_point_hours"
+}
+
+// ShopPointException особый день точки продаж
+type ShopPointException struct {
+	ID          uint      `gorm:"column:id;primaryKey"`
+	ShopPointID uint      `gorm:"column:shop_point_id"`
+	Date        time.Time `gorm:"column:date"`
+	OpensAt     *string   `gorm:"column:opens_at"`
+	ClosesAt    *string   `gorm:"column:closes_at"`
+	Note        string    `gorm:"column:note"`
+}
+
+func (ShopPointException) TableName() string {
+	return "shop_point_exceptions"
+}
+
+func ConvertShopPointHoursFromEntity(shopPointID uint, h entity.OpeningHours) ShopPointHours {
+	return ShopPointHours{
+		ShopPointID: shopPointID,
+		Weekday:     int(h.Weekday),
+		OpensAt:     h.OpensAt.String(),
+		ClosesAt:    h.ClosesAt.String(),
+	}
+}
+
+// ConvertShopPointHoursToEntity переводит период в сущность. Время в базе
+// всегда корректно, поэтому ошибки разбора не возвращаются.
+func ConvertShopPointHoursToEntity(m ShopPointHours) entity.OpeningHours {
+	opens, _ := entity.ParseClock(m.OpensAt)
+	closes, _ := entity.ParseClock(m.ClosesAt)
+	return entity.OpeningHours{
+		Weekday:  time.Weekday(m.Weekday),
+		OpensAt:  opens,
+		ClosesAt: closes,
+	}
+}
+
+func ConvertShopPointExceptionFromEntity(
+	shopPointID uint,
+	date time.Time,
+	e entity.ScheduleException,
+) ShopPointException {
+	exceptionModel := ShopPointException{
+		ShopPointID: shopPointID,
+		Date:        date,
+		Note:        e.Note,
+	}
+	if e.OpensAt != nil && e.ClosesAt != nil {
+		opens, closes := e.OpensAt.String(), e.ClosesAt.String()
+		exceptionModel.OpensAt = &opens
+		exceptionModel.ClosesAt = &closes
+	}
+
+	return exceptionModel
+}
+
+func ConvertShopPointExceptionToEntity(m ShopPointException) entity.ScheduleException {
+	exception := entity.ScheduleException{
+		Date: m.Date.Format(time.DateOnly),
+		Note: m.Note,
+	}
+	if m.OpensAt != nil && m.ClosesAt != nil {
+		opens, _ := entity.ParseClock(*m.OpensAt)
+		closes, _ := entity.ParseClock(*m.ClosesAt)
+		exception.OpensAt = &opens
+		exception.ClosesAt = &closes
+	}
+
+	return exception
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/store"
+)
+
+type ShopPoint struct {
+	ID        uint     `gorm:"column:id;primaryKey"`
+	ShopID    uint     `gorm:"column:shop_id"`
+	Address   string   `gorm:"column:address"`
+	Phone     string   `gorm:"column:phone"`
+	Latitude  *float64 `gorm:"column:latitude"`
+	Longitude *float64 `gorm:"column:longitude"`
+	Timezone  string   `gorm:"column:timezone"`
+}
+
+func (ShopPoint) TableName() string {
+	return "shop_points"
+}
+
+// NearbyShopPoint точка продаж с названием магазина и расстоянием до места поиска
+type NearbyShopPoint struct {
+	ShopPoint
+	ShopName   string  `gorm:"column:shop_name"`
+	DistanceKm float64 `gorm:"column:distance_km"`
+	Quantity   *int    `gorm:"column:quantity"`
+}
+
+func ConvertShopPointFromSvc(p store.ShopPoint) ShopPoint {
+	return ShopPoint{
+		ShopID:    p.StoreID,
+		Address:   p.Address,
+		Phone:     p.Phone,
+		Latitude:  p.Latitude,
+		Longitude: p.Longitude,
+		Timezone:  p.Timezone,
+	}
+}
+
+func ConvertShopPointToEntity(m ShopPoint) entity.ShopPoint {
+	return entity.ShopPoint{
+		ID:        m.ID,
+		ShopID:    m.ShopID,
+		Address:   m.Address,
+		Phone:     m.Phone,
+		Latitude:  m.Latitude,
+		Longitude: m.Longitude,
+		Timezone:  m.Timezone,
+	}
+}
+
+func ConvertNearbyShopPointToEntity(m NearbyShopPoint) entity.NearbyShopPoint {
+	return entity.NearbyShopPoint{
+		ShopPoint:  ConvertShopPointToEntity(m.ShopPoint),
+		ShopName:   m.ShopName,
+		DistanceKm: m.DistanceKm,
+		Quantity:   m.Quantity,
+	}
+}
@@ -2,13 +2,44 @@ package model
 
 import (
 	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/store"
 )
 
 type Store struct {
-	ID          uint      `json:"id" gorm:"primaryKey"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Products    []Product `gorm:"foreignKey:StoreID"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint   `gorm:"column:id;primaryKey"`
+	UserID      uint   `gorm:"column:user_id"`
+	Name        string `gorm:"column:name"`
+	Description string `gorm:"column:description"`
+	// VerificationStatus меняется только переходами проверки, новый магазин получает draft из умолчания колонки
+	VerificationStatus string    `gorm:"column:verification_status;->"`
+	CatalogUpdatedAt   time.Time `gorm:"column:catalog_updated_at;->"`
+	CreatedAt          time.Time `gorm:"column:created_at"`
+	UpdatedAt          time.Time `gorm:"column:updated_at"`
+}
+
+func (Store) TableName() string {
+	return "shops"
+}
+
+func ConvertStoreFromSvc(s store.Store) Store {
+	return Store{
+		UserID:      s.UserID,
+		Name:        s.Name,
+		Description: s.Description,
+	}
+}
+
+func ConvertStoreToEntity(s Store) entity.Store {
+	return entity.Store{
+		ID:                 s.ID,
+		UserID:             s.UserID,
+		Name:               s.Name,
+		Description:        s.Description,
+		VerificationStatus: s.VerificationStatus,
+		CatalogUpdatedAt:   s.CatalogUpdatedAt,
+		CreatedAt:          s.CreatedAt,
+		UpdatedAt:          s.UpdatedAt,
+	}
 }
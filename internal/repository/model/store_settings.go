@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+type ShopSettings struct {
+	ShopID          uint      `gorm:"column:shop_id;primaryKey"`
+	OfferTTLMinutes int       `gorm:"column:offer_ttl_minutes"`
+	MaxRounds       *int      `gorm:"column:max_rounds"`
+	MaxDiscount     *float64  `gorm:"column:max_discount"`
+	AcceptsOffers   bool      `gorm:"column:accepts_offers"`
+	Currency        string    `gorm:"column:currency"`
+	UpdatedAt       time.Time `gorm:"column:updated_at"`
+}
+
+func (ShopSettings) TableName() string {
+	return "shop_settings"
+}
+
+func ConvertShopSettingsToEntity(s ShopSettings) entity.StoreSettings {
+	return entity.StoreSettings{
+		StoreID:         s.ShopID,
+		OfferTTLMinutes: s.OfferTTLMinutes,
+		MaxRounds:       s.MaxRounds,
+		MaxDiscount:     s.MaxDiscount,
+		AcceptsOffers:   s.AcceptsOffers,
+		Currency:        s.Currency,
+		UpdatedAt:       s.UpdatedAt,
+	}
+}
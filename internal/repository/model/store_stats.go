@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+)
+
+type ShopStats struct {
+	ShopID             uint      `gorm:"column:shop_id;primaryKey"`
+	OfferCount         int       `gorm:"column:offer_count"`
+	ClosedCount        int       `gorm:"column:closed_count"`
+	AcceptedCount      int       `gorm:"column:accepted_count"`
+	AvgResponseSeconds *float64  `gorm:"column:avg_response_seconds"`
+	AvgRating          *float64  `gorm:"column:avg_rating"`
+	RatingCount        int       `gorm:"column:rating_count"`
+	RefreshedAt        time.Time `gorm:"column:refreshed_at"`
+}
+
+func (ShopStats) TableName() string {
+	return "shop_stats"
+}
+
+func ConvertShopStatsToEntity(s ShopStats) entity.StoreStats {
+	var acceptanceRate *float64
+	if s.ClosedCount > 0 {
+		rate := float64(s.AcceptedCount) / float64(s.ClosedCount)
+		acceptanceRate = &rate
+	}
+
+	return entity.StoreStats{
+		OfferCount:         s.OfferCount,
+		AvgResponseSeconds: s.AvgResponseSeconds,
+		AcceptanceRate:     acceptanceRate,
+		AvgRating:          s.AvgRating,
+		RatingCount:        s.RatingCount,
+		RefreshedAt:        &s.RefreshedAt,
+	}
+}
@@ -1,18 +1,22 @@
 package model
 
 import (
+	"time"
+
 	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
 	"github.com/zuzaaa-dev/stawberry/internal/domain/service/user"
 )
 
 type User struct {
-	ID            uint   `gorm:"column:id"`
-	Name          string `gorm:"column:name"`
-	Email         string `gorm:"column:email"`
-	Phone         string `gorm:"column:phone"`
-	Password      string `gorm:"column:password"`
-	IsStore       bool   `gorm:"column:is_store"`
-	Notifications []Notification
+	ID              uint       `gorm:"column:id"`
+	Name            string     `gorm:"column:name"`
+	Email           string     `gorm:"column:email"`
+	Phone           string     `gorm:"column:phone_number"`
+	Password        string     `gorm:"column:password_hash"`
+	IsStore         bool       `gorm:"column:is_store"`
+	IsAdmin         bool       `gorm:"column:is_admin"`
+	EmailVerifiedAt *time.Time `gorm:"column:email_verified_at"`
+	Notifications   []Notification
 }
 
 func ConvertUserFromSvc(u user.User) User {
@@ -27,11 +31,13 @@ func ConvertUserFromSvc(u user.User) User {
 
 func ConvertUserToEntity(u User) entity.User {
 	return entity.User{
-		ID:       u.ID,
-		Name:     u.Name,
-		Email:    u.Email,
-		Phone:    u.Phone,
-		Password: u.Password,
-		IsStore:  u.IsStore,
+		ID:              u.ID,
+		Name:            u.Name,
+		Email:           u.Email,
+		Phone:           u.Phone,
+		Password:        u.Password,
+		IsStore:         u.IsStore,
+		IsAdmin:         u.IsAdmin,
+		EmailVerifiedAt: u.EmailVerifiedAt,
 	}
 }
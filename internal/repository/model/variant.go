@@ -0,0 +1,57 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/product"
+)
+
+type ProductVariant struct {
+	ID        uint            `gorm:"column:id;primaryKey;autoIncrement"`
+	ProductID uint            `gorm:"column:product_id"`
+	SKU       string          `gorm:"column:sku"`
+	Barcode   string          `gorm:"column:barcode"`
+	Options   json.RawMessage `gorm:"column:options;type:jsonb"`
+	Position  int             `gorm:"column:position"`
+	CreatedAt time.Time       `gorm:"column:created_at"`
+}
+
+func ConvertVariantFromSvc(v product.Variant) (ProductVariant, error) {
+	options := v.Options
+	if options == nil {
+		options = map[string]string{}
+	}
+
+	raw, err := json.Marshal(options)
+	if err != nil {
+		return ProductVariant{}, err
+	}
+
+	return ProductVariant{
+		ProductID: v.ProductID,
+		SKU:       v.SKU,
+		Barcode:   v.Barcode,
+		Options:   raw,
+	}, nil
+}
+
+func ConvertVariantToEntity(v ProductVariant) (entity.ProductVariant, error) {
+	options := make(map[string]string)
+	if len(v.Options) > 0 {
+		if err := json.Unmarshal(v.Options, &options); err != nil {
+			return entity.ProductVariant{}, err
+		}
+	}
+
+	return entity.ProductVariant{
+		ID:        v.ID,
+		ProductID: v.ProductID,
+		SKU:       v.SKU,
+		Barcode:   v.Barcode,
+		Options:   options,
+		Position:  v.Position,
+		CreatedAt: v.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/store"
+)
+
+// StoreVerification заявка на проверку магазина, хранится в колонках shops
+type StoreVerification struct {
+	ID                  uint       `gorm:"column:id;primaryKey"`
+	UserID              uint       `gorm:"column:user_id"`
+	VerificationStatus  string     `gorm:"column:verification_status"`
+	LegalName           string     `gorm:"column:legal_name"`
+	TaxID               string     `gorm:"column:tax_id"`
+	LegalAddress        string     `gorm:"column:legal_address"`
+	VerificationComment string     `gorm:"column:verification_comment"`
+	SubmittedAt         *time.Time `gorm:"column:submitted_at"`
+	ReviewedAt          *time.Time `gorm:"column:reviewed_at"`
+}
+
+func (StoreVerification) TableName() string {
+	return "shops"
+}
+
+type ShopDocument struct {
+	ID          uint      `gorm:"column:id;primaryKey"`
+	ShopID      uint      `gorm:"column:shop_id"`
+	Kind        string    `gorm:"column:kind"`
+	Key         string    `gorm:"column:key"`
+	ContentType string    `gorm:"column:content_type"`
+	Size        int64     `gorm:"column:size"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+}
+
+func (ShopDocument) TableName() string {
+	return "shop_documents"
+}
+
+func ConvertStoreVerificationToEntity(v StoreVerification, documents []ShopDocument) entity.StoreVerification {
+	verification := entity.StoreVerification{
+		StoreID:      v.ID,
+		OwnerID:      v.UserID,
+		Status:       v.VerificationStatus,
+		LegalName:    v.LegalName,
+		TaxID:        v.TaxID,
+		LegalAddress: v.LegalAddress,
+		Comment:      v.VerificationComment,
+		SubmittedAt:  v.SubmittedAt,
+		ReviewedAt:   v.ReviewedAt,
+		Documents:    make([]entity.StoreDocument, 0, len(documents)),
+	}
+	for _, document := range documents {
+		verification.Documents = append(verification.Documents, ConvertShopDocumentToEntity(document))
+	}
+
+	return verification
+}
+
+func ConvertShopDocumentFromSvc(d store.Document) ShopDocument {
+	return ShopDocument{
+		ShopID:      d.StoreID,
+		Kind:        d.Kind,
+		Key:         d.Key,
+		ContentType: d.ContentType,
+		Size:        d.Size,
+	}
+}
+
+func ConvertShopDocumentToEntity(d ShopDocument) entity.StoreDocument {
+	return entity.StoreDocument{
+		ID:          d.ID,
+		StoreID:     d.ShopID,
+		Kind:        d.Kind,
+		Key:         d.Key,
+		ContentType: d.ContentType,
+		Size:        d.Size,
+		CreatedAt:   d.CreatedAt,
+	}
+}
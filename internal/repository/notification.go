@@ -6,6 +6,7 @@ import (
 	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
 
 	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
 	"gorm.io/gorm"
 )
 
@@ -17,27 +18,51 @@ func NewNotificationRepository(db *gorm.DB) *notificationRepository {
 	return &notificationRepository{db: db}
 }
 
+// notificationSortColumns поля сортировки уведомлений, created_at — время отправки
+var notificationSortColumns = map[string]sortColumn{
+	"created_at": {expr: "notifications.sent_at", cast: "timestamp"},
+}
+
 func (r *notificationRepository) SelectUserNotifications(
 	id string,
-	offset, limit int,
-) ([]entity.Notification, int, error) {
-	var total int64
-	if err := r.db.Model(&model.Notification{}).Where("user_id = ?", id).Count(&total).Error; err != nil {
-		return nil, 0, &apperror.NotificationError{
-			Code:    apperror.DatabaseError,
-			Message: "failed to count user notifications",
-			Err:     err,
+	params pagination.Params,
+) ([]entity.Notification, pagination.Page, error) {
+	column, ok := notificationSortColumns[params.Sort]
+	if !ok {
+		return nil, pagination.Page{}, &apperror.NotificationError{
+			Code:    apperror.BadRequest,
+			Message: "unsupported sort field " + params.Sort,
 		}
 	}
 
+	query := r.db.Model(&model.Notification{}).Where("user_id = ?", id)
+
+	var total *int
+	if !params.Keyset() {
+		var count int64
+		if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+			return nil, pagination.Page{}, &apperror.NotificationError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to count user notifications",
+				Err:     err,
+			}
+		}
+		n := int(count)
+		total = &n
+	}
+
 	var notifications []entity.Notification
-	if err := r.db.Where("user_id = ?", id).Offset(offset).Limit(limit).Find(&notifications).Error; err != nil {
-		return nil, 0, &apperror.NotificationError{
+	if err := paginate(query, column, "notifications.id", params).Find(&notifications).Error; err != nil {
+		return nil, pagination.Page{}, &apperror.NotificationError{
 			Code:    apperror.DatabaseError,
 			Message: "failed to fetch user not notifications",
 			Err:     err,
 		}
 	}
 
-	return notifications, int(total), nil
+	notifications, page := pagination.Finish(notifications, params, total, func(n entity.Notification) (string, uint) {
+		return cursorTime(n.SentAt), n.ID
+	})
+
+	return notifications, page, nil
 }
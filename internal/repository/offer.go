@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 
 	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
 
 	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
 	"gorm.io/gorm"
 )
 
@@ -27,34 +30,87 @@ func (r *offerRepository) InsertOffer(
 ) (uint, error) {
 	offerModel := model.ConvertOfferFromSvc(offer)
 
-	result := r.db.WithContext(ctx).Create(&offerModel)
-	if result.Error != nil {
-		if isDuplicateError(result.Error) {
-			return 0, &apperror.OfferError{
-				Code:    apperror.DuplicateError,
-				Message: "offer with this id already exists",
-				Err:     result.Error,
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// архивный товар скрыт из каталога и не принимает новых предложений,
+		// блокировка не дает удалить товар, пока предложение создается
+		var products int64
+		if err := tx.Raw(
+			"SELECT COUNT(*) FROM (SELECT 1 FROM products WHERE id = ? AND deleted_at IS NULL FOR SHARE) p",
+			offer.ProductID,
+		).Scan(&products).Error; err != nil {
+			return &apperror.OfferError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to check offer product",
+				Err:     err,
 			}
 		}
-		return 0, &apperror.OfferError{
-			Code:    apperror.DatabaseError,
-			Message: "offer to create product",
-			Err:     result.Error,
+		if products == 0 {
+			return &apperror.OfferError{
+				Code:    apperror.NotFound,
+				Message: "product not found",
+			}
+		}
+
+		variantID, err := resolveOfferVariant(tx, offer.ProductID, offer.VariantID)
+		if err != nil {
+			return err
+		}
+		offerModel.VariantID = variantID
+
+		if err := checkOfferInventory(tx, offerModel); err != nil {
+			return err
 		}
+		if offerModel.ShopPointID != nil {
+			if err := checkOfferShopPoint(tx, offerModel); err != nil {
+				return err
+			}
+		}
+		if offerModel.ListPrice, err = offerListPrice(tx, offerModel); err != nil {
+			return err
+		}
+		if offerModel.ListPrice != nil {
+			minPrice := entity.MinOfferPrice(*offerModel.ListPrice, offer.MaxDiscount)
+			if offerModel.Price < minPrice {
+				return &apperror.OfferError{
+					Code:    apperror.BadRequest,
+					Message: fmt.Sprintf("offer price must be at least %.2f", minPrice),
+				}
+			}
+		}
+
+		if err := tx.Create(&offerModel).Error; err != nil {
+			if isDuplicateError(err) {
+				return &apperror.OfferError{
+					Code:    apperror.DuplicateError,
+					Message: "offer with this id already exists",
+					Err:     err,
+				}
+			}
+			return &apperror.OfferError{
+				Code:    apperror.DatabaseError,
+				Message: "offer to create product",
+				Err:     err,
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	return offer.ID, nil
+	return offerModel.ID, nil
 }
 
 func (r *offerRepository) GetOfferByID(
 	ctx context.Context,
 	offerID uint,
 ) (entity.Offer, error) {
-	var offer entity.Offer
+	var offerModel model.Offer
 
 	result := r.db.WithContext(ctx).
 		Where("id = ?", offerID).
-		First(&offer)
+		First(&offerModel)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -67,55 +123,95 @@ func (r *offerRepository) GetOfferByID(
 		}
 	}
 
-	return offer, nil
+	return model.ConvertOfferToEntity(offerModel), nil
+}
+
+// offerSortColumns поля сортировки предложений пользователя
+var offerSortColumns = map[string]sortColumn{
+	"created_at": {expr: "offers.created_at", cast: "timestamp"},
+	"price":      {expr: "offers.price", cast: "numeric"},
+	"status":     {expr: "offers.status", cast: "text"},
 }
 
 func (r *offerRepository) SelectUserOffers(
 	ctx context.Context,
 	userID uint,
-	limit, offset int,
-) ([]entity.Offer, int64, error) {
-	var total int64
+	params pagination.Params,
+) ([]entity.Offer, pagination.Page, error) {
+	column, ok := offerSortColumns[params.Sort]
+	if !ok {
+		return nil, pagination.Page{}, &apperror.OfferError{
+			Code:    apperror.BadRequest,
+			Message: "unsupported sort field " + params.Sort,
+		}
+	}
 
-	result := r.db.WithContext(ctx).
+	query := r.db.WithContext(ctx).
 		Model(&model.Offer{}).
-		Where("user_id = ?", userID).
-		Count(&total)
+		Where("user_id = ?", userID)
 
-	if result.Error != nil {
-		return nil, 0, &apperror.OfferError{
-			Code:    apperror.DatabaseError,
-			Message: "failed to count user offers",
-			Err:     result.Error,
+	var total *int
+	if !params.Keyset() {
+		var count int64
+		if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+			return nil, pagination.Page{}, &apperror.OfferError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to count user offers",
+				Err:     err,
+			}
 		}
+		n := int(count)
+		total = &n
 	}
 
-	var offers []entity.Offer
-	result = r.db.WithContext(ctx).
-		Where("user_id = ?", userID).
-		Offset(offset).Limit(limit).
-		Find(&offers)
-
-	if result.Error != nil {
-		return nil, 0, &apperror.OfferError{
+	var offerModels []model.Offer
+	if err := paginate(query, column, "offers.id", params).Find(&offerModels).Error; err != nil {
+		return nil, pagination.Page{}, &apperror.OfferError{
 			Code:    apperror.DatabaseError,
 			Message: "failed to fetch user offers",
-			Err:     result.Error,
+			Err:     err,
 		}
 	}
 
-	return offers, total, nil
+	offers := make([]entity.Offer, 0, len(offerModels))
+	for _, offerModel := range offerModels {
+		offers = append(offers, model.ConvertOfferToEntity(offerModel))
+	}
+
+	offers, page := pagination.Finish(offers, params, total, func(o entity.Offer) (string, uint) {
+		switch params.Sort {
+		case "price":
+			return cursorFloat(o.Price), o.ID
+		case "status":
+			return o.Status, o.ID
+		default:
+			return cursorTime(o.CreatedAt), o.ID
+		}
+	})
+
+	return offers, page, nil
 }
 
+// UpdateOfferStatus меняет статус предложения. Первый уход из ожидания отмечается
+// в responded_at, по нему считается время ответа магазина, выдача товара — в completed_at.
+// Статус меняется, только если предложение все еще в статусе from.
 func (r *offerRepository) UpdateOfferStatus(
 	ctx context.Context,
 	offerID uint,
-	status string,
+	from, to string,
 ) (entity.Offer, error) {
+	updates := map[string]any{"status": to}
+	if to != entity.OfferStatusPending {
+		updates["responded_at"] = gorm.Expr("COALESCE(responded_at, now())")
+	}
+	if to == entity.OfferStatusCompleted {
+		updates["completed_at"] = gorm.Expr("now()")
+	}
+
 	tx := r.db.WithContext(ctx).
 		Model(&model.Offer{}).
-		Where("id = ?", offerID).
-		Update("status", status)
+		Where("id = ? AND status = ?", offerID, from).
+		Updates(updates)
 
 	if tx.Error != nil {
 		return entity.Offer{}, &apperror.OfferError{
@@ -125,14 +221,18 @@ func (r *offerRepository) UpdateOfferStatus(
 		}
 	}
 
+	// предложение уже сменило статус параллельным запросом
 	if tx.RowsAffected == 0 {
-		return entity.Offer{}, apperror.ErrOfferNotFound
+		return entity.Offer{}, &apperror.OfferError{
+			Code:    apperror.Conflict,
+			Message: "offer status has already changed",
+		}
 	}
 
-	var offer entity.Offer
+	var offerModel model.Offer
 	result := r.db.WithContext(ctx).
 		Where("id = ?", offerID).
-		First(&offer)
+		First(&offerModel)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -145,18 +245,63 @@ func (r *offerRepository) UpdateOfferStatus(
 		}
 	}
 
-	return offer, nil
+	return model.ConvertOfferToEntity(offerModel), nil
+}
+
+// UpdateOfferRating записывает оценку магазина покупателем
+func (r *offerRepository) UpdateOfferRating(ctx context.Context, offerID uint, rating int) (entity.Offer, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.Offer{}).
+		Where("id = ?", offerID).
+		Update("rating", rating)
+	if result.Error != nil {
+		return entity.Offer{}, &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to rate offer",
+			Err:     result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return entity.Offer{}, apperror.ErrOfferNotFound
+	}
+
+	return r.GetOfferByID(ctx, offerID)
+}
+
+// CountNegotiationRounds возвращает число предложений покупателя на товар магазина
+// после последнего принятого, в том числе еще не рассмотренных
+func (r *offerRepository) CountNegotiationRounds(ctx context.Context, userID, storeID, productID uint) (int, error) {
+	lastAccepted := r.db.
+		Model(&model.Offer{}).
+		Select("MAX(created_at)").
+		Where("user_id = ? AND store_id = ? AND product_id = ? AND status IN ?",
+			userID, storeID, productID, acceptedStatuses)
+
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&model.Offer{}).
+		Where("user_id = ? AND store_id = ? AND product_id = ?", userID, storeID, productID).
+		Where("created_at > COALESCE((?), '-infinity')", lastAccepted).
+		Count(&count).Error; err != nil {
+		return 0, &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to count offers",
+			Err:     err,
+		}
+	}
+
+	return int(count), nil
 }
 
 func (r *offerRepository) DeleteOffer(
 	ctx context.Context,
 	offerID uint,
 ) (entity.Offer, error) {
-	var offer entity.Offer
+	var offerModel model.Offer
 
 	result := r.db.WithContext(ctx).
 		Where("id = ?", offerID).
-		First(&offer)
+		First(&offerModel)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -169,7 +314,7 @@ func (r *offerRepository) DeleteOffer(
 		}
 	}
 
-	if result := r.db.WithContext(ctx).Delete(&offer); result.Error != nil {
+	if result := r.db.WithContext(ctx).Delete(&offerModel); result.Error != nil {
 		return entity.Offer{}, &apperror.OfferError{
 			Code:    apperror.DatabaseError,
 			Message: "failed to delete offer",
@@ -177,5 +322,118 @@ func (r *offerRepository) DeleteOffer(
 		}
 	}
 
-	return offer, nil
+	return model.ConvertOfferToEntity(offerModel), nil
+}
+
+// resolveOfferVariant проверяет, что вариант принадлежит товару предложения.
+// Если вариант не указан, подходит только единственный вариант товара.
+func resolveOfferVariant(tx *gorm.DB, productID uint, variantID *uint) (uint, error) {
+	var ids []uint
+	if err := tx.Table("product_variants").
+		Where("product_id = ?", productID).
+		Order("position, id").
+		Pluck("id", &ids).Error; err != nil {
+		return 0, &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to check offer variant",
+			Err:     err,
+		}
+	}
+
+	if variantID == nil {
+		if len(ids) != 1 {
+			return 0, &apperror.OfferError{
+				Code:    apperror.BadRequest,
+				Message: "variant_id is required for products with several variants",
+			}
+		}
+		return ids[0], nil
+	}
+
+	for _, id := range ids {
+		if id == *variantID {
+			return id, nil
+		}
+	}
+
+	return 0, &apperror.OfferError{
+		Code:    apperror.BadRequest,
+		Message: "variant does not belong to the product",
+	}
+}
+
+// checkOfferInventory проверяет, что товар есть в ассортименте магазина
+func checkOfferInventory(tx *gorm.DB, offer model.Offer) error {
+	var count int64
+	if err := tx.Model(&model.ShopInventory{}).
+		Where("product_id = ? AND shop_id = ?", offer.ProductID, offer.StoreID).
+		Count(&count).Error; err != nil {
+		return &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to check offer store inventory",
+			Err:     err,
+		}
+	}
+	if count == 0 {
+		return &apperror.OfferError{
+			Code:    apperror.NotFound,
+			Message: "product is not sold by the store",
+		}
+	}
+
+	return nil
+}
+
+// checkOfferShopPoint проверяет, что точка получения принадлежит магазину предложения
+func checkOfferShopPoint(tx *gorm.DB, offer model.Offer) error {
+	var count int64
+	if err := tx.Table("shop_points").
+		Where("id = ? AND shop_id = ?", *offer.ShopPointID, offer.StoreID).
+		Count(&count).Error; err != nil {
+		return &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to check offer shop point",
+			Err:     err,
+		}
+	}
+	if count == 0 {
+		return &apperror.OfferError{
+			Code:    apperror.BadRequest,
+			Message: "shop point does not belong to the store",
+		}
+	}
+
+	return nil
+}
+
+// offerListPrice цена магазина на момент предложения: цена варианта на выбранной точке,
+// без точки — самая низкая по точкам магазина, а если вариант на точках не продается — цена товара
+func offerListPrice(tx *gorm.DB, offer model.Offer) (*float64, error) {
+	points := tx.Table("shop_point_inventory spi").
+		Select("MIN(spi.price)").
+		Joins("JOIN shop_points sp ON sp.id = spi.shop_point_id").
+		Where("spi.variant_id = ?", offer.VariantID)
+	switch {
+	case offer.ShopPointID != nil:
+		points = points.Where("sp.id = ?", *offer.ShopPointID)
+	case offer.StoreID != nil:
+		points = points.Where("sp.shop_id = ?", *offer.StoreID)
+	default:
+		points = points.Where("FALSE")
+	}
+
+	var price sql.NullFloat64
+	if err := tx.Raw("SELECT COALESCE((?), p.price) FROM products p WHERE p.id = ?", points, offer.ProductID).
+		Row().Scan(&price); err != nil {
+		return nil, &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch offer list price",
+			Err:     err,
+		}
+	}
+	if !price.Valid {
+		return nil, nil
+	}
+
+	return &price.Float64, nil
 }
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
+	"gorm.io/gorm"
+)
+
+// sortColumn выражение, по которому сортируется список, и тип значения курсора в SQL
+type sortColumn struct {
+	expr string
+	cast string
+}
+
+// paginate добавляет к запросу сортировку и границы страницы. Записи с равным значением
+// сортировки упорядочиваются по айди, поэтому порядок всегда однозначный. При листании
+// курсором запрашивается на одну запись больше, чтобы узнать о следующей странице.
+func paginate(query *gorm.DB, column sortColumn, idExpr string, params pagination.Params) *gorm.DB {
+	desc := params.Desc
+	if params.Cursor != nil && params.Cursor.Before {
+		desc = !desc
+	}
+
+	direction, op := "ASC", ">"
+	if desc {
+		direction, op = "DESC", "<"
+	}
+	query = query.Order(fmt.Sprintf("%s %s, %s %s", column.expr, direction, idExpr, direction))
+
+	if params.Cursor == nil {
+		return query.Offset(params.Offset).Limit(params.Limit)
+	}
+
+	return query.
+		Where(
+			fmt.Sprintf("(%s, %s) %s (CAST(? AS %s), ?)", column.expr, idExpr, op, column.cast),
+			params.Cursor.Value, params.Cursor.ID,
+		).
+		Limit(params.Limit + 1)
+}
+
+// cursorTime переводит время в значение курсора без потери микросекунд
+func cursorTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func cursorFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
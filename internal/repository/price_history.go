@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/pricehistory"
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type priceHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceHistoryRepository(db *gorm.DB) *priceHistoryRepository {
+	return &priceHistoryRepository{db: db}
+}
+
+// ProductExists проверяет, что товар есть в каталоге и не в архиве
+func (r *priceHistoryRepository) ProductExists(ctx context.Context, productID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&model.Product{}).
+		Where("id = ?", productID).
+		Count(&count).Error; err != nil {
+		return false, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch product",
+			Err:     err,
+		}
+	}
+
+	return count > 0, nil
+}
+
+// SelectPriceStats считает минимум, максимум и среднее по ценам, действовавшим с since:
+// последней цене каждой позиции до начала окна и всем изменениям внутри окна
+func (r *priceHistoryRepository) SelectPriceStats(
+	ctx context.Context,
+	filter pricehistory.Filter,
+	since time.Time,
+) (entity.PriceStats, error) {
+	db := r.db.WithContext(ctx)
+
+	var statsModel model.PriceStats
+	if err := db.Raw(`
+		WITH scoped AS (?),
+		effective AS (
+			SELECT price FROM scoped WHERE changed_at >= ?
+			UNION ALL
+			SELECT price FROM (
+				SELECT DISTINCT ON (variant_id, shop_point_id) price
+				FROM scoped
+				WHERE changed_at < ?
+				ORDER BY variant_id, shop_point_id, changed_at DESC, id DESC
+			) opening
+		)
+		SELECT
+			MIN(price)::float8 AS min,
+			MAX(price)::float8 AS max,
+			AVG(price)::float8 AS avg,
+			(SELECT COUNT(*) FROM scoped WHERE changed_at >= ?) AS changes
+		FROM effective`,
+		priceHistoryScope(db, filter), since, since, since,
+	).Scan(&statsModel).Error; err != nil {
+		return entity.PriceStats{}, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to calculate price statistics",
+			Err:     err,
+		}
+	}
+
+	return model.ConvertPriceStatsToEntity(statsModel), nil
+}
+
+// SelectPriceHistory получает последние изменения цен с since в хронологическом порядке
+func (r *priceHistoryRepository) SelectPriceHistory(
+	ctx context.Context,
+	filter pricehistory.Filter,
+	since time.Time,
+	limit int,
+) ([]entity.PricePoint, error) {
+	db := r.db.WithContext(ctx)
+
+	var historyModels []model.PriceHistory
+	if err := db.Raw(`
+		SELECT * FROM (?) latest ORDER BY changed_at, id`,
+		priceHistoryScope(db, filter).
+			Where("changed_at >= ?", since).
+			Order("changed_at DESC, id DESC").
+			Limit(limit),
+	).Scan(&historyModels).Error; err != nil {
+		return nil, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch price history",
+			Err:     err,
+		}
+	}
+
+	points := make([]entity.PricePoint, 0, len(historyModels))
+	for _, historyModel := range historyModels {
+		points = append(points, model.ConvertPriceHistoryToEntity(historyModel))
+	}
+
+	return points, nil
+}
+
+// SelectCurrentPrice возвращает минимальную из текущих цен позиций товара.
+// Без уточнения варианта или точки продаж для товара без позиций берется цена по умолчанию.
+func (r *priceHistoryRepository) SelectCurrentPrice(
+	ctx context.Context,
+	filter pricehistory.Filter,
+) (*float64, error) {
+	query := r.db.WithContext(ctx).
+		Table("shop_point_inventory").
+		Select("MIN(price)::float8 AS price").
+		Where("product_id = ?", filter.ProductID)
+	if filter.VariantID != nil {
+		query = query.Where("variant_id = ?", *filter.VariantID)
+	}
+	if filter.ShopPointID != nil {
+		query = query.Where("shop_point_id = ?", *filter.ShopPointID)
+	}
+
+	var current struct {
+		Price *float64 `gorm:"column:price"`
+	}
+	if err := query.Scan(&current).Error; err != nil {
+		return nil, currentPriceError(err)
+	}
+
+	if current.Price == nil && filter.VariantID == nil && filter.ShopPointID == nil {
+		if err := r.db.WithContext(ctx).
+			Model(&model.Product{}).
+			Select("price::float8 AS price").
+			Where("id = ?", filter.ProductID).
+			Scan(&current).Error; err != nil {
+			return nil, currentPriceError(err)
+		}
+	}
+
+	return current.Price, nil
+}
+
+// NotifyPriceDrops забирает необработанные снижения цен, создает уведомления покупателям
+// с предложениями на товар и отмечает снижения обработанными одной транзакцией.
+// SKIP LOCKED не дает двум экземплярам приложения разослать одно снижение дважды.
+func (r *priceHistoryRepository) NotifyPriceDrops(
+	ctx context.Context,
+	limit int,
+	lowestSince time.Duration,
+	message func(entity.PriceDrop) string,
+) (int, error) {
+	var processed int
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var dropModels []model.PriceDrop
+		if err := tx.Raw(`
+			SELECT
+				ph.id,
+				ph.product_id,
+				p.name AS product_name,
+				ph.variant_id,
+				ph.shop_point_id,
+				COALESCE(sp.address, '') AS address,
+				ph.price::float8 AS price,
+				ph.previous_price::float8 AS previous_price,
+				(
+					SELECT MIN(h.price)::float8 FROM price_history h
+					WHERE h.product_id = ph.product_id
+						AND h.variant_id IS NOT DISTINCT FROM ph.variant_id
+						AND h.shop_point_id IS NOT DISTINCT FROM ph.shop_point_id
+						AND h.changed_at >= ph.changed_at - make_interval(secs => ?)
+						AND h.id < ph.id
+				) AS lowest_price
+			FROM price_history ph
+			JOIN products p ON p.id = ph.product_id
+			LEFT JOIN shop_points sp ON sp.id = ph.shop_point_id
+			WHERE ph.notified_at IS NULL AND ph.price < ph.previous_price
+			ORDER BY ph.id
+			LIMIT ?
+			FOR UPDATE OF ph SKIP LOCKED`,
+			lowestSince.Seconds(), limit,
+		).Scan(&dropModels).Error; err != nil {
+			return err
+		}
+		if len(dropModels) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(dropModels))
+		for _, dropModel := range dropModels {
+			drop := model.ConvertPriceDropToEntity(dropModel)
+
+			// покупатели архивного товара не уведомляются, предложения на другой вариант не учитываются
+			recipients := tx.Table("offers o").
+				Select("DISTINCT o.user_id, ?", message(drop)).
+				Joins("JOIN products p ON p.id = o.product_id AND p.deleted_at IS NULL").
+				Where("o.product_id = ? AND o.user_id IS NOT NULL", drop.ProductID)
+			if drop.VariantID != nil {
+				recipients = recipients.Where("o.variant_id = ?", *drop.VariantID)
+			}
+			if err := tx.Exec("INSERT INTO notifications (user_id, message) ?", recipients).Error; err != nil {
+				return err
+			}
+
+			ids = append(ids, drop.ID)
+		}
+
+		processed = len(ids)
+		return tx.Model(&model.PriceHistory{}).
+			Where("id IN ?", ids).
+			Update("notified_at", time.Now()).Error
+	})
+	if err != nil {
+		return 0, &apperror.NotificationError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to notify about price drops",
+			Err:     err,
+		}
+	}
+
+	return processed, nil
+}
+
+// priceHistoryScope история цен товара с учетом фильтра по варианту и точке продаж
+func priceHistoryScope(db *gorm.DB, filter pricehistory.Filter) *gorm.DB {
+	query := db.Model(&model.PriceHistory{}).
+		Select("id, variant_id, shop_point_id, price::float8 AS price, "+
+			"previous_price::float8 AS previous_price, changed_at").
+		Where("product_id = ?", filter.ProductID)
+	if filter.VariantID != nil {
+		query = query.Where("variant_id = ?", *filter.VariantID)
+	}
+	if filter.ShopPointID != nil {
+		query = query.Where("shop_point_id = ?", *filter.ShopPointID)
+	}
+
+	return query
+}
+
+func currentPriceError(err error) error {
+	return &apperror.ProductError{
+		Code:    apperror.DatabaseError,
+		Message: "failed to fetch current price",
+		Err:     err,
+	}
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
 
 	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type productRepository struct {
@@ -27,19 +29,51 @@ func (r *productRepository) InsertProduct(
 	product product.Product,
 ) (uint, error) {
 	productModel := model.ConvertProductFromSvc(product)
-	if err := r.db.WithContext(ctx).Create(productModel).Error; err != nil {
-		if isDuplicateError(err) {
-			return 0, &apperror.ProductError{
-				Code:    apperror.DuplicateError,
-				Message: "product with this id already exists",
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&productModel).Error; err != nil {
+			if isDuplicateError(err) {
+				return &apperror.ProductError{
+					Code:    apperror.DuplicateError,
+					Message: "product with this id already exists",
+					Err:     err,
+				}
+			}
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to create product",
 				Err:     err,
 			}
 		}
-		return 0, &apperror.ProductError{
-			Code:    apperror.DatabaseError,
-			Message: "failed to create product",
-			Err:     err,
+
+		// у товара всегда есть хотя бы один вариант, к нему привязываются цены и остатки
+		if _, err := insertProductVariants(tx, productModel.ID, product.Variants); err != nil {
+			return err
 		}
+
+		// товар попадает в ассортимент магазина, от имени которого создан
+		if product.StoreID != 0 {
+			inventoryModel := model.ShopInventory{
+				ProductID:   productModel.ID,
+				ShopID:      product.StoreID,
+				IsAvailable: product.InStock,
+			}
+			if err := tx.Create(&inventoryModel).Error; err != nil {
+				return &apperror.ProductError{
+					Code:    apperror.DatabaseError,
+					Message: "failed to add product to store",
+					Err:     err,
+				}
+			}
+		}
+
+		if product.Attributes == nil {
+			return nil
+		}
+
+		return insertProductAttributes(tx, productModel.ID, product.Attributes)
+	})
+	if err != nil {
+		return 0, err
 	}
 
 	return productModel.ID, nil
@@ -61,64 +95,141 @@ func (r *productRepository) GetProductByID(
 		}
 	}
 
-	return model.ConvertProductToEntity(productModel), nil
-}
+	productEntity := model.ConvertProductToEntity(productModel)
 
-func (r *productRepository) SelectProducts(
-	ctx context.Context,
-	offset,
-	limit int,
-) ([]entity.Product, int, error) {
-	var total int64
-	if err := r.db.WithContext(ctx).Model(&model.Product{}).Count(&total).Error; err != nil {
-		return nil, 0, &apperror.ProductError{
+	var attributesModel model.ProductAttributes
+	err := r.db.WithContext(ctx).Where("product_id = ?", productModel.ID).Limit(1).Find(&attributesModel).Error
+	if err != nil {
+		return entity.Product{}, &apperror.ProductError{
 			Code:    apperror.DatabaseError,
-			Message: "failed to count products",
+			Message: "failed to fetch product attributes",
 			Err:     err,
 		}
 	}
-
-	var products []entity.Product
-	if err := r.db.WithContext(ctx).Offset(offset).Limit(limit).Find(&products).Error; err != nil {
-		return nil, 0, &apperror.ProductError{
-			Code:    apperror.DatabaseError,
-			Message: "failed to fetch products",
-			Err:     err,
+	if len(attributesModel.Attributes) > 0 {
+		if productEntity.Attributes, err = model.ConvertProductAttributesToEntity(attributesModel); err != nil {
+			return entity.Product{}, &apperror.ProductError{
+				Code:    apperror.InternalError,
+				Message: "failed to decode product attributes",
+				Err:     err,
+			}
 		}
 	}
 
-	return products, int(total), nil
+	return productEntity, nil
+}
+
+// productPriceExpr цена товара в каталоге — минимальная цена его вариантов на точках продаж,
+// а если товара нет ни на одной точке, то цена по умолчанию
+const productPriceExpr = `COALESCE((
+	SELECT MIN(spi.price) FROM shop_point_inventory spi WHERE spi.product_id = products.id
+), products.price, 0)`
+
+// storeProductPriceExpr цена товара среди точек продаж магазина из shop_inventory si
+const storeProductPriceExpr = `COALESCE((
+	SELECT MIN(spi.price) FROM shop_point_inventory spi
+	JOIN shop_points sp ON sp.id = spi.shop_point_id
+	WHERE spi.product_id = products.id AND sp.shop_id = si.shop_id
+), products.price, 0)`
+
+func (r *productRepository) SelectProducts(
+	ctx context.Context,
+	filter product.Filter,
+	params pagination.Params,
+) ([]entity.Product, pagination.Page, error) {
+	query := availableProducts(listedProducts(r.db.WithContext(ctx).Model(&model.Product{})), filter)
+
+	return selectProductPage(query, productPriceExpr, params)
 }
 
+// listedProducts скрывает товары, которые продают только непроверенные магазины.
+// Товары вне ассортимента магазинов остаются в каталоге.
+func listedProducts(query *gorm.DB) *gorm.DB {
+	offered := query.Session(&gorm.Session{NewDB: true}).
+		Table("shop_inventory si").
+		Select("1").
+		Where("si.product_id = products.id")
+	verified := offered.Session(&gorm.Session{}).
+		Joins("JOIN shops s ON s.id = si.shop_id AND " + verifiedShop)
+
+	return query.Where("NOT EXISTS (?) OR EXISTS (?)", offered, verified)
+}
+
+// SelectStoreProducts получает товары из ассортимента проверенного магазина
 func (r *productRepository) SelectStoreProducts(
 	ctx context.Context,
-	id string, offset, limit int,
-) ([]entity.Product, int, error) {
-	var total int64
-	if err := r.db.WithContext(ctx).
+	id string,
+	params pagination.Params,
+) ([]entity.Product, pagination.Page, error) {
+	query := r.db.WithContext(ctx).
 		Model(&model.Product{}).
-		Where("store_id = ?", id).
-		Count(&total).Error; err != nil {
-		return nil, 0, &apperror.ProductError{
-			Code:    apperror.DatabaseError,
-			Message: "failed to count store products",
-			Err:     err,
+		Joins("JOIN shop_inventory si ON si.product_id = products.id AND si.shop_id = ?", id).
+		Joins("JOIN shops s ON s.id = si.shop_id AND " + verifiedShop)
+
+	return selectProductPage(query, storeProductPriceExpr, params)
+}
+
+func selectProductPage(
+	query *gorm.DB,
+	priceExpr string,
+	params pagination.Params,
+) ([]entity.Product, pagination.Page, error) {
+	columns := map[string]sortColumn{
+		"created_at": {expr: "products.created_at", cast: "timestamp"},
+		"name":       {expr: "products.name", cast: "text"},
+		"price":      {expr: priceExpr, cast: "numeric"},
+	}
+	column, ok := columns[params.Sort]
+	if !ok {
+		return nil, pagination.Page{}, &apperror.ProductError{
+			Code:    apperror.BadRequest,
+			Message: "unsupported sort field " + params.Sort,
+		}
+	}
+
+	var total *int
+	if !params.Keyset() {
+		var count int64
+		if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+			return nil, pagination.Page{}, &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to count products",
+				Err:     err,
+			}
 		}
+		n := int(count)
+		total = &n
 	}
 
-	var products []entity.Product
-	if err := r.db.WithContext(ctx).
-		Where("store_id = ?", id).
-		Offset(offset).Limit(limit).
-		Find(&products).Error; err != nil {
-		return nil, 0, &apperror.ProductError{
+	var productModels []model.Product
+	if err := paginate(query, column, "products.id", params).
+		Select("products.id, products.name, products.description, products.category_id, products.created_at, " +
+			"products.updated_at, " + priceExpr + "::float8 AS price").
+		Find(&productModels).Error; err != nil {
+		return nil, pagination.Page{}, &apperror.ProductError{
 			Code:    apperror.DatabaseError,
-			Message: "failed to fetch store products",
+			Message: "failed to fetch products",
 			Err:     err,
 		}
 	}
 
-	return products, int(total), nil
+	products := make([]entity.Product, 0, len(productModels))
+	for _, productModel := range productModels {
+		products = append(products, model.ConvertProductToEntity(productModel))
+	}
+
+	products, page := pagination.Finish(products, params, total, func(p entity.Product) (string, uint) {
+		switch params.Sort {
+		case "name":
+			return p.Name, p.ID
+		case "price":
+			return cursorFloat(p.Price), p.ID
+		default:
+			return cursorTime(p.CreatedAt), p.ID
+		}
+	})
+
+	return products, page, nil
 }
 
 func (r *productRepository) UpdateProduct(
@@ -127,29 +238,191 @@ func (r *productRepository) UpdateProduct(
 	update product.UpdateProduct,
 ) error {
 	updateModel := model.ConvertUpdateProductFromSvc(update)
-	tx := r.db.WithContext(ctx).Model(&model.Product{}).Where("id = ?", id).Updates(updateModel)
-	if tx.Error != nil {
-		if isDuplicateError(tx.Error) {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var productModel model.Product
+		if err := tx.Select("id").Where("id = ?", id).First(&productModel).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrProductNotFound
+			}
 			return &apperror.ProductError{
-				Code:    apperror.DuplicateError,
-				Message: "product with these details already exists",
-				Err:     tx.Error,
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch product",
+				Err:     err,
 			}
 		}
+
+		if updateModel != (model.UpdateProduct{}) {
+			err := tx.Model(&model.Product{}).Where("id = ?", id).Updates(updateModel).Error
+			if err != nil {
+				if isDuplicateError(err) {
+					return &apperror.ProductError{
+						Code:    apperror.DuplicateError,
+						Message: "product with these details already exists",
+						Err:     err,
+					}
+				}
+				return &apperror.ProductError{
+					Code:    apperror.DatabaseError,
+					Message: "failed to update product",
+					Err:     err,
+				}
+			}
+		}
+
+		if update.Attributes == nil {
+			return nil
+		}
+
+		if err := tx.Where("product_id = ?", productModel.ID).Delete(&model.ProductAttributes{}).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to replace product attributes",
+				Err:     err,
+			}
+		}
+
+		return insertProductAttributes(tx, productModel.ID, update.Attributes)
+	})
+}
+
+// ArchiveProduct скрывает товар из каталога, оставляя его для существующих предложений
+func (r *productRepository) ArchiveProduct(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&model.Product{}, id)
+	if result.Error != nil {
 		return &apperror.ProductError{
 			Code:    apperror.DatabaseError,
-			Message: "failed to update product",
-			Err:     tx.Error,
+			Message: "failed to archive product",
+			Err:     result.Error,
 		}
 	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrProductNotFound
+	}
 
-	if tx.RowsAffected == 0 {
+	return nil
+}
+
+// RestoreProduct возвращает архивный товар в каталог, для активного товара ничего не делает
+func (r *productRepository) RestoreProduct(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&model.Product{}).
+		Where("id = ?", id).
+		UpdateColumn("deleted_at", nil)
+	if result.Error != nil {
+		return &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to restore product",
+			Err:     result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
 		return apperror.ErrProductNotFound
 	}
 
 	return nil
 }
 
+// DeleteProduct удаляет товар вместе с изображениями, атрибутами и остатками одной транзакцией.
+// Товар, на который ссылаются предложения, удалить нельзя. Возвращает ключи удаленных
+// изображений, чтобы вызывающий убрал сами объекты из хранилища.
+func (r *productRepository) DeleteProduct(ctx context.Context, id uint) ([]string, error) {
+	var imageKeys []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// блокировка не дает параллельно создать предложение на удаляемый товар
+		var productModel model.Product
+		if err := tx.Unscoped().
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", id).
+			First(&productModel).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrProductNotFound
+			}
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch product",
+				Err:     err,
+			}
+		}
+
+		var offers int64
+		if err := tx.Table("offers").Where("product_id = ?", id).Count(&offers).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to check product offers",
+				Err:     err,
+			}
+		}
+		if offers > 0 {
+			return &apperror.ProductError{
+				Code:    apperror.Conflict,
+				Message: "product has offers and can only be archived",
+			}
+		}
+
+		if err := tx.Model(&model.ImageKey{}).
+			Where("product_id = ?", id).
+			Pluck("image_key", &imageKeys).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch product images",
+				Err:     err,
+			}
+		}
+
+		deletes := []struct {
+			table  string
+			column string
+		}{
+			{"image_keys", "product_id"},
+			{"product_attributes", "product_id"},
+			{"shop_point_inventory", "product_id"},
+			{"product_variants", "product_id"},
+			{"shop_inventory", "product_id"},
+			{"products", "id"},
+		}
+		for _, d := range deletes {
+			if err := tx.Exec("DELETE FROM "+d.table+" WHERE "+d.column+" = ?", id).Error; err != nil {
+				return &apperror.ProductError{
+					Code:    apperror.DatabaseError,
+					Message: "failed to delete product",
+					Err:     err,
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return imageKeys, nil
+}
+
+// insertProductAttributes сохраняет атрибуты товара в product_attributes
+func insertProductAttributes(tx *gorm.DB, productID uint, attributes map[string]any) error {
+	attributesModel, err := model.ConvertProductAttributesFromSvc(productID, attributes)
+	if err != nil {
+		return &apperror.ProductError{
+			Code:    apperror.BadRequest,
+			Message: "invalid product attributes",
+			Err:     err,
+		}
+	}
+
+	if err := tx.Create(&attributesModel).Error; err != nil {
+		return &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to save product attributes",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
 func isDuplicateError(err error) bool {
 	return strings.Contains(err.Error(), "duplicate") ||
 		strings.Contains(err.Error(), "unique violation")
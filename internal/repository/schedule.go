@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetPointSchedule получает расписание точки продаж с предстоящими особыми днями
+func (r *inventoryRepository) GetPointSchedule(ctx context.Context, shopPointID uint) (entity.Schedule, error) {
+	schedules, err := selectSchedules(r.db.WithContext(ctx), []uint{shopPointID})
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	schedule, ok := schedules[shopPointID]
+	if !ok {
+		return entity.Schedule{}, apperror.ErrShopPointNotFound
+	}
+
+	return schedule, nil
+}
+
+// SelectPointSchedules получает расписания точек продаж одним набором запросов.
+// Несуществующих точек в результате нет.
+func (r *inventoryRepository) SelectPointSchedules(
+	ctx context.Context,
+	shopPointIDs []uint,
+) (map[uint]entity.Schedule, error) {
+	return selectSchedules(r.db.WithContext(ctx), shopPointIDs)
+}
+
+// SelectStoreSchedules получает расписания всех точек продаж магазина
+func (r *inventoryRepository) SelectStoreSchedules(ctx context.Context, shopID uint) ([]entity.Schedule, error) {
+	db := r.db.WithContext(ctx)
+
+	var shopPointIDs []uint
+	if err := db.Model(&model.ShopPoint{}).
+		Where("shop_id = ?", shopID).
+		Order("id").
+		Pluck("id", &shopPointIDs).Error; err != nil {
+		return nil, scheduleError(err)
+	}
+
+	schedules, err := selectSchedules(db, shopPointIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	storeSchedules := make([]entity.Schedule, 0, len(shopPointIDs))
+	for _, shopPointID := range shopPointIDs {
+		storeSchedules = append(storeSchedules, schedules[shopPointID])
+	}
+
+	return storeSchedules, nil
+}
+
+// ReplaceHours задает часовой пояс точки и заменяет ее недельное расписание одной транзакцией
+func (r *inventoryRepository) ReplaceHours(
+	ctx context.Context,
+	shopPointID uint,
+	timezone string,
+	hours []entity.OpeningHours,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.ShopPoint{}).
+			Where("id = ?", shopPointID).
+			Update("timezone", timezone).Error; err != nil {
+			return scheduleError(err)
+		}
+
+		if err := tx.Where("shop_point_id = ?", shopPointID).
+			Delete(&model.ShopPointHours{}).Error; err != nil {
+			return scheduleError(err)
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+
+		hoursModels := make([]model.ShopPointHours, 0, len(hours))
+		for _, h := range hours {
+			hoursModels = append(hoursModels, model.ConvertShopPointHoursFromEntity(shopPointID, h))
+		}
+		if err := tx.Create(&hoursModels).Error; err != nil {
+			return scheduleError(err)
+		}
+
+		return nil
+	})
+}
+
+// UpsertException создает или заменяет особый день точки продаж
+func (r *inventoryRepository) UpsertException(
+	ctx context.Context,
+	shopPointID uint,
+	date time.Time,
+	exception entity.ScheduleException,
+) error {
+	exceptionModel := model.ConvertShopPointExceptionFromEntity(shopPointID, date, exception)
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "shop_point_id"}, {Name: "date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"opens_at", "closes_at", "note"}),
+		}).
+		Create(&exceptionModel).Error; err != nil {
+		return scheduleError(err)
+	}
+
+	return nil
+}
+
+func (r *inventoryRepository) DeleteException(ctx context.Context, shopPointID uint, date time.Time) error {
+	result := r.db.WithContext(ctx).
+		Where("shop_point_id = ? AND date = ?", shopPointID, date).
+		Delete(&model.ShopPointException{})
+	if result.Error != nil {
+		return scheduleError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrScheduleExceptionNotFound
+	}
+
+	return nil
+}
+
+// selectSchedules собирает расписания точек продаж: часовой пояс, недельные периоды
+// и особые дни начиная со вчерашнего, чтобы учесть работу за полночь
+func selectSchedules(db *gorm.DB, shopPointIDs []uint) (map[uint]entity.Schedule, error) {
+	schedules := make(map[uint]entity.Schedule, len(shopPointIDs))
+	if len(shopPointIDs) == 0 {
+		return schedules, nil
+	}
+
+	var pointModels []model.ShopPoint
+	if err := db.Select("id, timezone").
+		Where("id IN ?", shopPointIDs).
+		Find(&pointModels).Error; err != nil {
+		return nil, scheduleError(err)
+	}
+	for _, pointModel := range pointModels {
+		schedules[pointModel.ID] = entity.Schedule{
+			ShopPointID: pointModel.ID,
+			Timezone:    pointModel.Timezone,
+			Hours:       []entity.OpeningHours{},
+			Exceptions:  []entity.ScheduleException{},
+		}
+	}
+
+	var hoursModels []model.ShopPointHours
+	if err := db.Where("shop_point_id IN ?", shopPointIDs).
+		Order("shop_point_id, weekday, opens_at").
+		Find(&hoursModels).Error; err != nil {
+		return nil, scheduleError(err)
+	}
+	for _, hoursModel := range hoursModels {
+		schedule := schedules[hoursModel.ShopPointID]
+		schedule.Hours = append(schedule.Hours, model.ConvertShopPointHoursToEntity(hoursModel))
+		schedules[hoursModel.ShopPointID] = schedule
+	}
+
+	var exceptionModels []model.ShopPointException
+	if err := db.Where("shop_point_id IN ? AND date >= CURRENT_DATE - 1", shopPointIDs).
+		Order("shop_point_id, date").
+		Find(&exceptionModels).Error; err != nil {
+		return nil, scheduleError(err)
+	}
+	for _, exceptionModel := range exceptionModels {
+		schedule := schedules[exceptionModel.ShopPointID]
+		schedule.Exceptions = append(schedule.Exceptions, model.ConvertShopPointExceptionToEntity(exceptionModel))
+		schedules[exceptionModel.ShopPointID] = schedule
+	}
+
+	return schedules, nil
+}
+
+func scheduleError(err error) error {
+	return &apperror.InventoryError{
+		Code:    apperror.DatabaseError,
+		Message: "failed to access shop point schedule",
+		Err:     err,
+	}
+}
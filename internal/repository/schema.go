@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// schemaModel модель, поля которой читаются из таблицы и пишутся в нее.
+// table задается для моделей частичного обновления, у которых нет своей таблицы.
+type schemaModel struct {
+	model any
+	table string
+}
+
+var schemaModels = []schemaModel{
+	{model: &model.User{}},
+	{model: &model.RefreshToken{}},
+	{model: &model.EmailVerification{}},
+	{model: &model.Notification{}},
+	{model: &model.Store{}},
+	{model: &model.StoreVerification{}},
+	{model: &model.ShopDocument{}},
+	{model: &model.ShopMember{}},
+	{model: &model.ShopInvitation{}},
+	{model: &model.ShopStats{}},
+	{model: &model.ShopSettings{}},
+	{model: &model.ShopPoint{}},
+	{model: &model.ShopPointHours{}},
+	{model: &model.ShopPointException{}},
+	{model: &model.Product{}},
+	{model: &model.UpdateProduct{}, table: "products"},
+	{model: &model.ProductAttributes{}},
+	{model: &model.ProductVariant{}},
+	{model: &model.ShopInventory{}},
+	{model: &model.PointInventory{}},
+	{model: &model.InventoryMovement{}},
+	{model: &model.LowStockThreshold{}},
+	{model: &model.ImageKey{}},
+	{model: &model.CategoryAttribute{}},
+	{model: &model.Offer{}},
+	{model: &model.ImportJob{}},
+	{model: &model.ImportJobError{}},
+	{model: &model.PriceHistory{}},
+}
+
+// columnTypes типы колонок Postgres, в которые сохраняются поля модели каждого типа
+var columnTypes = map[schema.DataType][]string{
+	schema.Bool:   {"boolean"},
+	schema.Int:    {"smallint", "integer", "bigint"},
+	schema.Uint:   {"smallint", "integer", "bigint"},
+	schema.Float:  {"real", "double precision", "numeric"},
+	schema.String: {"text", "character varying", "character", "uuid", "time without time zone"},
+	schema.Time:   {"timestamp without time zone", "timestamp with time zone", "date"},
+	schema.Bytes:  {"bytea"},
+	"jsonb":       {"jsonb"},
+}
+
+type schemaColumn struct {
+	TableName  string `gorm:"column:table_name"`
+	ColumnName string `gorm:"column:column_name"`
+	DataType   string `gorm:"column:data_type"`
+}
+
+// VerifySchema сверяет поля моделей с колонками таблиц из information_schema.
+// Возвращает все найденные расхождения разом, чтобы их можно было исправить одной миграцией.
+func VerifySchema(db *gorm.DB) error {
+	var columnModels []schemaColumn
+	if err := db.Raw(`
+		SELECT table_name, column_name, data_type
+		FROM information_schema.columns
+		WHERE table_schema = current_schema()`,
+	).Scan(&columnModels).Error; err != nil {
+		return fmt.Errorf("failed to read database schema: %w", err)
+	}
+
+	tables := make(map[string]map[string]string)
+	for _, c := range columnModels {
+		if tables[c.TableName] == nil {
+			tables[c.TableName] = make(map[string]string)
+		}
+		tables[c.TableName][c.ColumnName] = c.DataType
+	}
+
+	var mismatches []error
+	for _, m := range schemaModels {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m.model); err != nil {
+			return fmt.Errorf("failed to parse model %T: %w", m.model, err)
+		}
+
+		table := m.table
+		if table == "" {
+			table = stmt.Schema.Table
+		}
+
+		columns, ok := tables[table]
+		if !ok {
+			mismatches = append(mismatches, fmt.Errorf("%s: table %s does not exist", stmt.Schema.Name, table))
+			continue
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			// связи с другими моделями колонок не имеют
+			if field.DBName == "" {
+				continue
+			}
+
+			dataType, ok := columns[field.DBName]
+			if !ok {
+				mismatches = append(mismatches, fmt.Errorf("%s.%s: column %s.%s does not exist",
+					stmt.Schema.Name, field.Name, table, field.DBName))
+				continue
+			}
+
+			allowed, known := columnTypes[field.DataType]
+			if known && !slices.Contains(allowed, dataType) {
+				mismatches = append(mismatches, fmt.Errorf("%s.%s: column %s.%s has type %s, model expects %s",
+					stmt.Schema.Name, field.Name, table, field.DBName, dataType, field.DataType))
+			}
+		}
+	}
+
+	return errors.Join(mismatches...)
+}
@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/store"
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"github.com/zuzaaa-dev/stawberry/pkg/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type storeRepository struct {
+	db *gorm.DB
+}
+
+func NewStoreRepository(db *gorm.DB) *storeRepository {
+	return &storeRepository{db: db}
+}
+
+func (r *storeRepository) InsertStore(ctx context.Context, store store.Store) (entity.Store, error) {
+	storeModel := model.ConvertStoreFromSvc(store)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&storeModel).Error; err != nil {
+			return &apperror.StoreError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to create store",
+				Err:     err,
+			}
+		}
+
+		ownerModel := model.ShopMember{
+			ShopID: storeModel.ID,
+			UserID: storeModel.UserID,
+			Role:   entity.StoreRoleOwner,
+		}
+		if err := tx.Omit("created_at").Create(&ownerModel).Error; err != nil {
+			return memberError(err)
+		}
+
+		return insertDefaultSettings(tx, storeModel.ID)
+	})
+	if err != nil {
+		return entity.Store{}, err
+	}
+
+	return r.GetStoreByID(ctx, storeModel.ID)
+}
+
+func (r *storeRepository) GetStoreByID(ctx context.Context, storeID uint) (entity.Store, error) {
+	var storeModel model.Store
+	if err := r.db.WithContext(ctx).Where("id = ?", storeID).Take(&storeModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Store{}, apperror.ErrStoreNotFound
+		}
+		return entity.Store{}, &apperror.StoreError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch store",
+			Err:     err,
+		}
+	}
+
+	return model.ConvertStoreToEntity(storeModel), nil
+}
+
+// storeSortColumns поля сортировки списка магазинов
+var storeSortColumns = map[string]sortColumn{
+	"created_at": {expr: "shops.created_at", cast: "timestamp"},
+	"name":       {expr: "shops.name", cast: "text"},
+}
+
+func (r *storeRepository) SelectStores(
+	ctx context.Context,
+	filter store.Filter,
+	params pagination.Params,
+) ([]entity.Store, pagination.Page, error) {
+	column, ok := storeSortColumns[params.Sort]
+	if !ok {
+		return nil, pagination.Page{}, &apperror.StoreError{
+			Code:    apperror.BadRequest,
+			Message: "unsupported sort field " + params.Sort,
+		}
+	}
+
+	query := r.db.WithContext(ctx).Model(&model.Store{})
+	if filter.UserID != nil {
+		query = query.Where("shops.user_id = ?", *filter.UserID)
+	}
+	if filter.MemberID != nil {
+		query = query.Where(
+			"EXISTS (SELECT 1 FROM shop_members sm WHERE sm.shop_id = shops.id AND sm.user_id = ?)",
+			*filter.MemberID,
+		)
+	}
+	if filter.Status != nil {
+		query = query.Where("shops.verification_status = ?", *filter.Status)
+	}
+
+	var total *int
+	if !params.Keyset() {
+		var count int64
+		if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+			return nil, pagination.Page{}, &apperror.StoreError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to count stores",
+				Err:     err,
+			}
+		}
+		n := int(count)
+		total = &n
+	}
+
+	var storeModels []model.Store
+	if err := paginate(query, column, "shops.id", params).Find(&storeModels).Error; err != nil {
+		return nil, pagination.Page{}, &apperror.StoreError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch stores",
+			Err:     err,
+		}
+	}
+
+	stores := make([]entity.Store, 0, len(storeModels))
+	for _, storeModel := range storeModels {
+		stores = append(stores, model.ConvertStoreToEntity(storeModel))
+	}
+
+	stores, page := pagination.Finish(stores, params, total, func(s entity.Store) (string, uint) {
+		if params.Sort == "name" {
+			return s.Name, s.ID
+		}
+		return cursorTime(s.CreatedAt), s.ID
+	})
+
+	return stores, page, nil
+}
+
+func (r *storeRepository) UpdateStore(
+	ctx context.Context,
+	storeID uint,
+	update store.UpdateStore,
+) (entity.Store, error) {
+	updates := map[string]any{"updated_at": gorm.Expr("now()")}
+	if update.Name != nil {
+		updates["name"] = *update.Name
+	}
+	if update.Description != nil {
+		updates["description"] = *update.Description
+	}
+
+	result := r.db.WithContext(ctx).Model(&model.Store{}).Where("id = ?", storeID).Updates(updates)
+	if result.Error != nil {
+		return entity.Store{}, &apperror.StoreError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to update store",
+			Err:     result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return entity.Store{}, apperror.ErrStoreNotFound
+	}
+
+	return r.GetStoreByID(ctx, storeID)
+}
+
+func (r *storeRepository) SelectShopPoints(ctx context.Context, storeID uint) ([]entity.ShopPoint, error) {
+	var pointModels []model.ShopPoint
+	if err := r.db.WithContext(ctx).
+		Where("shop_id = ?", storeID).
+		Order("id").
+		Find(&pointModels).Error; err != nil {
+		return nil, shopPointError(err)
+	}
+
+	points := make([]entity.ShopPoint, 0, len(pointModels))
+	for _, pointModel := range pointModels {
+		points = append(points, model.ConvertShopPointToEntity(pointModel))
+	}
+
+	return points, nil
+}
+
+func (r *storeRepository) GetShopPoint(ctx context.Context, shopPointID uint) (entity.ShopPoint, error) {
+	var pointModel model.ShopPoint
+	if err := r.db.WithContext(ctx).Where("id = ?", shopPointID).Take(&pointModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.ShopPoint{}, apperror.ErrStoreShopPointNotFound
+		}
+		return entity.ShopPoint{}, shopPointError(err)
+	}
+
+	return model.ConvertShopPointToEntity(pointModel), nil
+}
+
+func (r *storeRepository) InsertShopPoint(ctx context.Context, point store.ShopPoint) (entity.ShopPoint, error) {
+	pointModel := model.ConvertShopPointFromSvc(point)
+	if err := r.db.WithContext(ctx).Create(&pointModel).Error; err != nil {
+		return entity.ShopPoint{}, shopPointError(err)
+	}
+
+	return model.ConvertShopPointToEntity(pointModel), nil
+}
+
+func (r *storeRepository) UpdateShopPoint(
+	ctx context.Context,
+	shopPointID uint,
+	update store.UpdateShopPoint,
+) (entity.ShopPoint, error) {
+	updates := make(map[string]any)
+	if update.Address != nil {
+		updates["address"] = *update.Address
+	}
+	if update.Phone != nil {
+		updates["phone"] = *update.Phone
+	}
+	if update.Latitude != nil && update.Longitude != nil {
+		updates["latitude"] = *update.Latitude
+		updates["longitude"] = *update.Longitude
+	}
+
+	if len(updates) > 0 {
+		if err := r.db.WithContext(ctx).
+			Model(&model.ShopPoint{}).
+			Where("id = ?", shopPointID).
+			Updates(updates).Error; err != nil {
+			return entity.ShopPoint{}, shopPointError(err)
+		}
+	}
+
+	return r.GetShopPoint(ctx, shopPointID)
+}
+
+// DeleteShopPoint удаляет точку продаж вместе с пустыми позициями. Точку, на которой
+// еще есть товар, удалить нельзя: остаток сначала нужно списать или перенести.
+// Блокировка точки не дает завести на ней новую позицию, пока идет удаление.
+func (r *storeRepository) DeleteShopPoint(ctx context.Context, shopPointID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var pointModel model.ShopPoint
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", shopPointID).
+			Take(&pointModel).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrStoreShopPointNotFound
+			}
+			return shopPointError(err)
+		}
+
+		var stocked int64
+		if err := tx.Raw(`
+			SELECT COUNT(*) FILTER (WHERE quantity > 0)
+			FROM (SELECT quantity FROM shop_point_inventory WHERE shop_point_id = ? FOR UPDATE) spi`,
+			shopPointID,
+		).Scan(&stocked).Error; err != nil {
+			return shopPointError(err)
+		}
+		if stocked > 0 {
+			return &apperror.StoreError{
+				Code:    apperror.Conflict,
+				Message: "shop point still has stock",
+			}
+		}
+
+		if err := tx.Where("shop_point_id = ?", shopPointID).
+			Delete(&model.PointInventory{}).Error; err != nil {
+			return shopPointError(err)
+		}
+		if err := tx.Where("id = ?", shopPointID).Delete(&model.ShopPoint{}).Error; err != nil {
+			return shopPointError(err)
+		}
+
+		return nil
+	})
+}
+
+func shopPointError(err error) error {
+	return &apperror.StoreError{
+		Code:    apperror.DatabaseError,
+		Message: "failed to access shop point",
+		Err:     err,
+	}
+}
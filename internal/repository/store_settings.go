@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/store"
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+func (r *storeRepository) GetStoreSettings(ctx context.Context, storeID uint) (entity.StoreSettings, error) {
+	var settingsModel model.ShopSettings
+	if err := r.db.WithContext(ctx).Where("shop_id = ?", storeID).Take(&settingsModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.StoreSettings{}, apperror.ErrStoreNotFound
+		}
+		return entity.StoreSettings{}, settingsError(err)
+	}
+
+	return model.ConvertShopSettingsToEntity(settingsModel), nil
+}
+
+func (r *storeRepository) UpdateStoreSettings(
+	ctx context.Context,
+	storeID uint,
+	settings store.Settings,
+) (entity.StoreSettings, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.ShopSettings{}).
+		Where("shop_id = ?", storeID).
+		Updates(map[string]any{
+			"offer_ttl_minutes": settings.OfferTTLMinutes,
+			"max_rounds":        settings.MaxRounds,
+			"max_discount":      settings.MaxDiscount,
+			"accepts_offers":    settings.AcceptsOffers,
+			"currency":          settings.Currency,
+			"updated_at":        gorm.Expr("now()"),
+		})
+	if result.Error != nil {
+		return entity.StoreSettings{}, settingsError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return entity.StoreSettings{}, apperror.ErrStoreNotFound
+	}
+
+	return r.GetStoreSettings(ctx, storeID)
+}
+
+// insertDefaultSettings создает настройки нового магазина со значениями по умолчанию из схемы
+func insertDefaultSettings(tx *gorm.DB, storeID uint) error {
+	if err := tx.Exec("INSERT INTO shop_settings (shop_id) VALUES (?)", storeID).Error; err != nil {
+		return settingsError(err)
+	}
+
+	return nil
+}
+
+func settingsError(err error) error {
+	return &apperror.StoreError{
+		Code:    apperror.DatabaseError,
+		Message: "failed to access store settings",
+		Err:     err,
+	}
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+// acceptedStatuses статусы предложений, которые магазин принял, в том числе уже выданных
+var acceptedStatuses = []string{entity.OfferStatusAccepted, entity.OfferStatusCompleted}
+
+// refreshStatsQuery пересчитывает статистику всех магазинов одним запросом.
+// Закрытыми считаются предложения с ответом магазина и истекшие без ответа.
+const refreshStatsQuery = `
+INSERT INTO shop_stats (
+	shop_id, offer_count, closed_count, accepted_count,
+	avg_response_seconds, avg_rating, rating_count, refreshed_at
+)
+SELECT
+	s.id,
+	COUNT(o.id),
+	COUNT(o.id) FILTER (WHERE o.responded_at IS NOT NULL OR o.expires_at < now()),
+	COUNT(o.id) FILTER (WHERE o.status IN ?),
+	AVG(EXTRACT(EPOCH FROM o.responded_at - o.created_at)),
+	AVG(o.rating),
+	COUNT(o.rating),
+	now()
+FROM shops s
+LEFT JOIN offers o ON o.store_id = s.id
+GROUP BY s.id
+ON CONFLICT (shop_id) DO UPDATE SET
+	offer_count = EXCLUDED.offer_count,
+	closed_count = EXCLUDED.closed_count,
+	accepted_count = EXCLUDED.accepted_count,
+	avg_response_seconds = EXCLUDED.avg_response_seconds,
+	avg_rating = EXCLUDED.avg_rating,
+	rating_count = EXCLUDED.rating_count,
+	refreshed_at = EXCLUDED.refreshed_at`
+
+// RefreshStoreStats пересчитывает статистику магазинов по предложениям
+func (r *storeRepository) RefreshStoreStats(ctx context.Context) error {
+	if err := r.db.WithContext(ctx).Exec(refreshStatsQuery, acceptedStatuses).Error; err != nil {
+		return &apperror.StoreError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to refresh store statistics",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// GetStoreStats возвращает последнюю посчитанную статистику магазина.
+// У магазина, созданного после последнего пересчета, статистика пустая.
+func (r *storeRepository) GetStoreStats(ctx context.Context, storeID uint) (entity.StoreStats, error) {
+	var statsModel model.ShopStats
+	if err := r.db.WithContext(ctx).Where("shop_id = ?", storeID).Take(&statsModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.StoreStats{}, nil
+		}
+		return entity.StoreStats{}, &apperror.StoreError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch store statistics",
+			Err:     err,
+		}
+	}
+
+	return model.ConvertShopStatsToEntity(statsModel), nil
+}
+
+// CountStoreProducts возвращает число неархивных товаров в ассортименте магазина
+func (r *storeRepository) CountStoreProducts(ctx context.Context, storeID uint) (int, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Table("shop_inventory si").
+		Joins("JOIN products p ON p.id = si.product_id AND p.deleted_at IS NULL").
+		Where("si.shop_id = ?", storeID).
+		Count(&count).Error; err != nil {
+		return 0, &apperror.StoreError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to count store products",
+			Err:     err,
+		}
+	}
+
+	return int(count), nil
+}
@@ -25,7 +25,7 @@ func (r *userRepository) InsertUser(
 	user user.User,
 ) (uint, error) {
 	userModel := model.ConvertUserFromSvc(user)
-	if err := r.db.WithContext(ctx).Create(userModel).Error; err != nil {
+	if err := r.db.WithContext(ctx).Create(&userModel).Error; err != nil {
 		if isDuplicateError(err) {
 			return 0, &apperror.UserError{
 				Code:    apperror.DuplicateError,
@@ -83,6 +83,42 @@ func (r *userRepository) GetUserByID(
 	return model.ConvertUserToEntity(userModel), nil
 }
 
-func (r *userRepository) UpdateUser(ctx context.Context, user user.User) error {
-	panic("implement me")
+// UpdateUser меняет заданные поля профиля. Пароль приходит уже хешированным,
+// смена почты сбрасывает ее подтверждение.
+func (r *userRepository) UpdateUser(ctx context.Context, id uint, update user.UpdateUser) (entity.User, error) {
+	updates := make(map[string]any)
+	if update.Name != nil {
+		updates["name"] = *update.Name
+	}
+	if update.Phone != nil {
+		updates["phone_number"] = *update.Phone
+	}
+	if update.Email != nil {
+		updates["email"] = *update.Email
+		updates["email_verified_at"] = nil
+	}
+	if update.Password != nil {
+		updates["password_hash"] = *update.Password
+	}
+
+	result := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		if isDuplicateError(result.Error) {
+			return entity.User{}, &apperror.UserError{
+				Code:    apperror.DuplicateError,
+				Message: "user with this email already exists",
+				Err:     result.Error,
+			}
+		}
+		return entity.User{}, &apperror.UserError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to update user",
+			Err:     result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return entity.User{}, apperror.ErrUserNotFound
+	}
+
+	return r.GetUserByID(ctx, id)
 }
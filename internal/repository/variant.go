@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/product"
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type variantRepository struct {
+	db *gorm.DB
+}
+
+func NewVariantRepository(db *gorm.DB) *variantRepository {
+	return &variantRepository{db: db}
+}
+
+// InsertVariant добавляет вариант в конец списка вариантов товара
+func (r *variantRepository) InsertVariant(
+	ctx context.Context,
+	variant product.Variant,
+) (entity.ProductVariant, error) {
+	var created entity.ProductVariant
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		variants, err := insertProductVariants(tx, variant.ProductID, []product.Variant{variant})
+		if err != nil {
+			return err
+		}
+		created = variants[0]
+		return nil
+	})
+	if err != nil {
+		return entity.ProductVariant{}, err
+	}
+
+	return created, nil
+}
+
+// GetVariant получает вариант товара по айди
+func (r *variantRepository) GetVariant(
+	ctx context.Context,
+	productID, variantID uint,
+) (entity.ProductVariant, error) {
+	var variantModel model.ProductVariant
+	if err := r.db.WithContext(ctx).
+		Where("id = ? AND product_id = ?", variantID, productID).
+		First(&variantModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.ProductVariant{}, apperror.ErrVariantNotFound
+		}
+		return entity.ProductVariant{}, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch product variant",
+			Err:     err,
+		}
+	}
+
+	return convertVariant(variantModel)
+}
+
+// SelectProductsVariants получает варианты нескольких товаров одним запросом
+func (r *variantRepository) SelectProductsVariants(
+	ctx context.Context,
+	productIDs []uint,
+) (map[uint][]entity.ProductVariant, error) {
+	variants := make(map[uint][]entity.ProductVariant, len(productIDs))
+	if len(productIDs) == 0 {
+		return variants, nil
+	}
+
+	var variantModels []model.ProductVariant
+	if err := r.db.WithContext(ctx).
+		Where("product_id IN ?", productIDs).
+		Order("product_id, position, id").
+		Find(&variantModels).Error; err != nil {
+		return nil, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch product variants",
+			Err:     err,
+		}
+	}
+
+	for _, variantModel := range variantModels {
+		variant, err := convertVariant(variantModel)
+		if err != nil {
+			return nil, err
+		}
+		variants[variant.ProductID] = append(variants[variant.ProductID], variant)
+	}
+
+	return variants, nil
+}
+
+// UpdateVariant меняет переданные поля варианта
+func (r *variantRepository) UpdateVariant(
+	ctx context.Context,
+	productID, variantID uint,
+	update product.UpdateVariant,
+) (entity.ProductVariant, error) {
+	columns := make(map[string]any)
+	if update.SKU != nil {
+		columns["sku"] = *update.SKU
+	}
+	if update.Barcode != nil {
+		columns["barcode"] = *update.Barcode
+	}
+	if update.Options != nil {
+		raw, err := json.Marshal(update.Options)
+		if err != nil {
+			return entity.ProductVariant{}, &apperror.ProductError{
+				Code:    apperror.BadRequest,
+				Message: "invalid variant options",
+				Err:     err,
+			}
+		}
+		columns["options"] = raw
+	}
+
+	if len(columns) > 0 {
+		result := r.db.WithContext(ctx).
+			Model(&model.ProductVariant{}).
+			Where("id = ? AND product_id = ?", variantID, productID).
+			Updates(columns)
+		if result.Error != nil {
+			if isDuplicateError(result.Error) {
+				return entity.ProductVariant{}, errDuplicateSKU(result.Error)
+			}
+			return entity.ProductVariant{}, &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to update product variant",
+				Err:     result.Error,
+			}
+		}
+	}
+
+	return r.GetVariant(ctx, productID, variantID)
+}
+
+// DeleteVariant удаляет вариант вместе с его остатками на точках продаж.
+// Последний вариант товара и варианты, на которые есть предложения, удалить нельзя.
+func (r *variantRepository) DeleteVariant(ctx context.Context, productID, variantID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var variantModels []model.ProductVariant
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("product_id = ?", productID).
+			Find(&variantModels).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to fetch product variants",
+				Err:     err,
+			}
+		}
+
+		found := false
+		for _, v := range variantModels {
+			found = found || v.ID == variantID
+		}
+		if !found {
+			return apperror.ErrVariantNotFound
+		}
+		if len(variantModels) == 1 {
+			return &apperror.ProductError{
+				Code:    apperror.Conflict,
+				Message: "product must keep at least one variant",
+			}
+		}
+
+		var offers int64
+		if err := tx.Table("offers").Where("variant_id = ?", variantID).Count(&offers).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to check variant offers",
+				Err:     err,
+			}
+		}
+		if offers > 0 {
+			return &apperror.ProductError{
+				Code:    apperror.Conflict,
+				Message: "variant has offers and cannot be deleted",
+			}
+		}
+
+		if err := tx.Exec("DELETE FROM shop_point_inventory WHERE variant_id = ?", variantID).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to delete variant inventory",
+				Err:     err,
+			}
+		}
+
+		if err := tx.Delete(&model.ProductVariant{}, variantID).Error; err != nil {
+			return &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to delete product variant",
+				Err:     err,
+			}
+		}
+
+		return nil
+	})
+}
+
+// insertProductVariants сохраняет варианты товара после уже существующих,
+// при пустом списке создается вариант по умолчанию без опций
+func insertProductVariants(
+	tx *gorm.DB,
+	productID uint,
+	variants []product.Variant,
+) ([]entity.ProductVariant, error) {
+	if len(variants) == 0 {
+		variants = []product.Variant{{}}
+	}
+
+	var position int
+	if err := tx.Model(&model.ProductVariant{}).
+		Where("product_id = ?", productID).
+		Select("COALESCE(MAX(position), -1) + 1").
+		Scan(&position).Error; err != nil {
+		return nil, &apperror.ProductError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to fetch product variants",
+			Err:     err,
+		}
+	}
+
+	created := make([]entity.ProductVariant, 0, len(variants))
+	for i, variant := range variants {
+		variant.ProductID = productID
+		variantModel, err := model.ConvertVariantFromSvc(variant)
+		if err != nil {
+			return nil, &apperror.ProductError{
+				Code:    apperror.BadRequest,
+				Message: "invalid variant options",
+				Err:     err,
+			}
+		}
+		variantModel.Position = position + i
+
+		if err := tx.Create(&variantModel).Error; err != nil {
+			if isDuplicateError(err) {
+				return nil, errDuplicateSKU(err)
+			}
+			return nil, &apperror.ProductError{
+				Code:    apperror.DatabaseError,
+				Message: "failed to save product variant",
+				Err:     err,
+			}
+		}
+
+		v, err := convertVariant(variantModel)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, v)
+	}
+
+	return created, nil
+}
+
+func convertVariant(variantModel model.ProductVariant) (entity.ProductVariant, error) {
+	variant, err := model.ConvertVariantToEntity(variantModel)
+	if err != nil {
+		return entity.ProductVariant{}, &apperror.ProductError{
+			Code:    apperror.InternalError,
+			Message: "failed to decode variant options",
+			Err:     err,
+		}
+	}
+
+	return variant, nil
+}
+
+func errDuplicateSKU(err error) error {
+	return &apperror.ProductError{
+		Code:    apperror.DuplicateError,
+		Message: "variant with this sku already exists",
+		Err:     err,
+	}
+}
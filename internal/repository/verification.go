@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/internal/app/apperror"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/entity"
+	"github.com/zuzaaa-dev/stawberry/internal/domain/service/store"
+	"github.com/zuzaaa-dev/stawberry/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+// verifiedShop условие видимости магазина s в каталоге
+const verifiedShop = "s.verification_status = 'verified'"
+
+func (r *storeRepository) GetVerification(ctx context.Context, storeID uint) (entity.StoreVerification, error) {
+	return getVerification(r.db.WithContext(ctx), storeID)
+}
+
+// UpdateLegalDetails меняет юридические данные заявки в черновике или после отказа
+func (r *storeRepository) UpdateLegalDetails(ctx context.Context, storeID uint, details store.LegalDetails) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.StoreVerification{}).
+		Where("id = ? AND verification_status IN ?", storeID,
+			[]string{entity.VerificationDraft, entity.VerificationRejected}).
+		Updates(map[string]any{
+			"legal_name":    details.LegalName,
+			"tax_id":        details.TaxID,
+			"legal_address": details.LegalAddress,
+			"updated_at":    gorm.Expr("now()"),
+		})
+	if result.Error != nil {
+		return verificationError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errVerificationChanged
+	}
+
+	return nil
+}
+
+func (r *storeRepository) InsertDocument(ctx context.Context, document store.Document) (entity.StoreDocument, error) {
+	documentModel := model.ConvertShopDocumentFromSvc(document)
+	if err := r.db.WithContext(ctx).Create(&documentModel).Error; err != nil {
+		if isDuplicateError(err) {
+			return entity.StoreDocument{}, &apperror.StoreError{
+				Code:    apperror.Conflict,
+				Message: "document is already attached",
+				Err:     err,
+			}
+		}
+		return entity.StoreDocument{}, verificationError(err)
+	}
+
+	return model.ConvertShopDocumentToEntity(documentModel), nil
+}
+
+func (r *storeRepository) GetDocument(ctx context.Context, storeID, documentID uint) (entity.StoreDocument, error) {
+	var documentModel model.ShopDocument
+	if err := r.db.WithContext(ctx).
+		Where("id = ? AND shop_id = ?", documentID, storeID).
+		Take(&documentModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.StoreDocument{}, apperror.ErrStoreDocumentNotFound
+		}
+		return entity.StoreDocument{}, verificationError(err)
+	}
+
+	return model.ConvertShopDocumentToEntity(documentModel), nil
+}
+
+func (r *storeRepository) DeleteDocument(ctx context.Context, storeID, documentID uint) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND shop_id = ?", documentID, storeID).
+		Delete(&model.ShopDocument{})
+	if result.Error != nil {
+		return verificationError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrStoreDocumentNotFound
+	}
+
+	return nil
+}
+
+// ChangeVerification переводит заявку на новый этап и уведомляет владельца одной транзакцией.
+// Отправка заявки отмечает submitted_at, решение администратора — reviewed_at.
+func (r *storeRepository) ChangeVerification(
+	ctx context.Context,
+	storeID uint,
+	from []string,
+	change store.VerificationChange,
+) (entity.StoreVerification, error) {
+	updates := map[string]any{
+		"verification_status":  change.Status,
+		"verification_comment": change.Comment,
+		"updated_at":           gorm.Expr("now()"),
+	}
+	if change.Status == entity.VerificationSubmitted {
+		updates["submitted_at"] = gorm.Expr("now()")
+	} else {
+		updates["reviewed_at"] = gorm.Expr("now()")
+	}
+
+	var verification entity.StoreVerification
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.StoreVerification{}).
+			Where("id = ? AND verification_status IN ?", storeID, from).
+			Updates(updates)
+		if result.Error != nil {
+			return verificationError(result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errVerificationChanged
+		}
+
+		var err error
+		if verification, err = getVerification(tx, storeID); err != nil {
+			return err
+		}
+
+		notificationModel := model.Notification{
+			UserID:  verification.OwnerID,
+			Message: change.Message,
+			SentAt:  time.Now(),
+		}
+		if err := tx.Create(&notificationModel).Error; err != nil {
+			return verificationError(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.StoreVerification{}, err
+	}
+
+	return verification, nil
+}
+
+// IsStoreVerified проверяет, что магазин прошел проверку и может принимать предложения
+func (r *storeRepository) IsStoreVerified(ctx context.Context, shopID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Table("shops s").
+		Where("s.id = ? AND "+verifiedShop, shopID).
+		Count(&count).Error; err != nil {
+		return false, &apperror.OfferError{
+			Code:    apperror.DatabaseError,
+			Message: "failed to check store verification",
+			Err:     err,
+		}
+	}
+
+	return count > 0, nil
+}
+
+func getVerification(db *gorm.DB, storeID uint) (entity.StoreVerification, error) {
+	var verificationModel model.StoreVerification
+	if err := db.Where("id = ?", storeID).Take(&verificationModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.StoreVerification{}, apperror.ErrStoreNotFound
+		}
+		return entity.StoreVerification{}, verificationError(err)
+	}
+
+	var documentModels []model.ShopDocument
+	if err := db.Where("shop_id = ?", storeID).
+		Order("id").
+		Find(&documentModels).Error; err != nil {
+		return entity.StoreVerification{}, verificationError(err)
+	}
+
+	return model.ConvertStoreVerificationToEntity(verificationModel, documentModels), nil
+}
+
+// errVerificationChanged заявка не на том этапе, с которого возможен переход,
+// в том числе если ее успел изменить другой запрос
+var errVerificationChanged = &apperror.StoreError{
+	Code:    apperror.Conflict,
+	Message: "verification status does not allow this action",
+}
+
+func verificationError(err error) error {
+	return &apperror.StoreError{
+		Code:    apperror.DatabaseError,
+		Message: "failed to access store verification",
+		Err:     err,
+	}
+}
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE image_keys
+    ADD COLUMN id SERIAL PRIMARY KEY,
+    ADD COLUMN position INT NOT NULL DEFAULT 0,
+    ADD COLUMN content_type VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN size BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ALTER COLUMN image_key TYPE VARCHAR(255);
+
+CREATE INDEX idx_image_keys_product_id_position ON image_keys(product_id, position);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_image_keys_product_id_position;
+
+ALTER TABLE image_keys
+    DROP COLUMN IF EXISTS id,
+    DROP COLUMN IF EXISTS position,
+    DROP COLUMN IF EXISTS content_type,
+    DROP COLUMN IF EXISTS size,
+    DROP COLUMN IF EXISTS created_at,
+    ALTER COLUMN image_key TYPE VARCHAR(50);
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE image_keys
+    ADD COLUMN etag VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN variants_etag VARCHAR(100) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE image_keys
+    DROP COLUMN IF EXISTS etag,
+    DROP COLUMN IF EXISTS variants_etag;
+-- +goose StatementEnd
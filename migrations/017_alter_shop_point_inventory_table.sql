@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE shop_point_inventory
+    ALTER COLUMN price TYPE DECIMAL(10,2),
+    ADD CONSTRAINT uq_shop_point_inventory_point_product UNIQUE (shop_point_id, product_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE shop_point_inventory
+    DROP CONSTRAINT IF EXISTS uq_shop_point_inventory_point_product,
+    ALTER COLUMN price TYPE INT;
+-- +goose StatementEnd
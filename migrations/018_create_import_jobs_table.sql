@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE import_jobs (
+    id SERIAL PRIMARY KEY,
+    shop_id INT NOT NULL,
+    user_id INT NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    file_key VARCHAR(255) NOT NULL,
+    mapping JSONB NOT NULL DEFAULT '{}',
+    total_rows INT NOT NULL DEFAULT 0,
+    imported_rows INT NOT NULL DEFAULT 0,
+    failed_rows INT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    FOREIGN KEY (shop_id) REFERENCES shops(id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX idx_import_jobs_shop_id ON import_jobs(shop_id);
+CREATE INDEX idx_import_jobs_status ON import_jobs(status);
+
+CREATE TABLE import_job_errors (
+    id SERIAL PRIMARY KEY,
+    job_id INT NOT NULL,
+    row_number INT NOT NULL,
+    field VARCHAR(100) NOT NULL DEFAULT '',
+    message TEXT NOT NULL,
+    FOREIGN KEY (job_id) REFERENCES import_jobs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_import_job_errors_job_id ON import_job_errors(job_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS import_job_errors;
+DROP TABLE IF EXISTS import_jobs;
+-- +goose StatementEnd
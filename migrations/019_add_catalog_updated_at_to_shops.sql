@@ -0,0 +1,71 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE shops ADD COLUMN catalog_updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- touch_shop_catalog отмечает изменение ассортимента магазинов, которых касается строка.
+-- TG_ARGV[0] задает, через что строка связана с магазином (shop, shop_point или product),
+-- TG_ARGV[1] имя колонки с айди этой сущности.
+CREATE FUNCTION touch_shop_catalog() RETURNS TRIGGER AS $$
+DECLARE
+    ids INT[] := '{}';
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        ids := ids || (to_jsonb(OLD) ->> TG_ARGV[1])::INT;
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        ids := ids || (to_jsonb(NEW) ->> TG_ARGV[1])::INT;
+    END IF;
+
+    CASE TG_ARGV[0]
+        WHEN 'shop' THEN
+            UPDATE shops SET catalog_updated_at = now() WHERE id = ANY(ids);
+        WHEN 'shop_point' THEN
+            UPDATE shops SET catalog_updated_at = now()
+            WHERE id IN (SELECT shop_id FROM shop_points WHERE id = ANY(ids));
+        WHEN 'product' THEN
+            UPDATE shops SET catalog_updated_at = now()
+            WHERE id IN (SELECT shop_id FROM shop_inventory WHERE product_id = ANY(ids));
+    END CASE;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION touch_shop_catalog_on_rename() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.name IS DISTINCT FROM OLD.name THEN
+        NEW.catalog_updated_at := now();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER shops_catalog_rename BEFORE UPDATE ON shops
+    FOR EACH ROW EXECUTE FUNCTION touch_shop_catalog_on_rename();
+CREATE TRIGGER shop_inventory_catalog AFTER INSERT OR UPDATE OR DELETE ON shop_inventory
+    FOR EACH ROW EXECUTE FUNCTION touch_shop_catalog('shop', 'shop_id');
+CREATE TRIGGER shop_points_catalog AFTER INSERT OR UPDATE OR DELETE ON shop_points
+    FOR EACH ROW EXECUTE FUNCTION touch_shop_catalog('shop', 'shop_id');
+CREATE TRIGGER shop_point_inventory_catalog AFTER INSERT OR UPDATE OR DELETE ON shop_point_inventory
+    FOR EACH ROW EXECUTE FUNCTION touch_shop_catalog('shop_point', 'shop_point_id');
+CREATE TRIGGER products_catalog AFTER UPDATE ON products
+    FOR EACH ROW EXECUTE FUNCTION touch_shop_catalog('product', 'id');
+CREATE TRIGGER product_attributes_catalog AFTER INSERT OR UPDATE OR DELETE ON product_attributes
+    FOR EACH ROW EXECUTE FUNCTION touch_shop_catalog('product', 'product_id');
+CREATE TRIGGER image_keys_catalog AFTER INSERT OR UPDATE OR DELETE ON image_keys
+    FOR EACH ROW EXECUTE FUNCTION touch_shop_catalog('product', 'product_id');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS image_keys_catalog ON image_keys;
+DROP TRIGGER IF EXISTS product_attributes_catalog ON product_attributes;
+DROP TRIGGER IF EXISTS products_catalog ON products;
+DROP TRIGGER IF EXISTS shop_point_inventory_catalog ON shop_point_inventory;
+DROP TRIGGER IF EXISTS shop_points_catalog ON shop_points;
+DROP TRIGGER IF EXISTS shop_inventory_catalog ON shop_inventory;
+DROP TRIGGER IF EXISTS shops_catalog_rename ON shops;
+DROP FUNCTION IF EXISTS touch_shop_catalog_on_rename();
+DROP FUNCTION IF EXISTS touch_shop_catalog();
+ALTER TABLE shops DROP COLUMN IF EXISTS catalog_updated_at;
+-- +goose StatementEnd
@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE product_variants (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL,
+    sku VARCHAR(64) NOT NULL DEFAULT '',
+    barcode VARCHAR(64) NOT NULL DEFAULT '',
+    options JSONB NOT NULL DEFAULT '{}',
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE INDEX idx_product_variants_product_id ON product_variants(product_id);
+CREATE UNIQUE INDEX uq_product_variants_product_sku ON product_variants(product_id, sku) WHERE sku <> '';
+
+-- у каждого существующего товара появляется вариант по умолчанию,
+-- к которому переносятся его остатки и предложения
+INSERT INTO product_variants (product_id) SELECT id FROM products;
+
+ALTER TABLE shop_point_inventory ADD COLUMN variant_id INT REFERENCES product_variants(id);
+UPDATE shop_point_inventory spi SET variant_id = pv.id
+FROM product_variants pv WHERE pv.product_id = spi.product_id;
+ALTER TABLE shop_point_inventory
+    ALTER COLUMN variant_id SET NOT NULL,
+    DROP CONSTRAINT uq_shop_point_inventory_point_product,
+    ADD CONSTRAINT uq_shop_point_inventory_point_variant UNIQUE (shop_point_id, variant_id);
+CREATE INDEX idx_shop_point_inventory_variant_id ON shop_point_inventory(variant_id);
+
+ALTER TABLE offers ADD COLUMN variant_id INT REFERENCES product_variants(id);
+UPDATE offers o SET variant_id = pv.id
+FROM product_variants pv WHERE pv.product_id = o.product_id;
+CREATE INDEX idx_offers_variant_id ON offers(variant_id);
+
+CREATE TRIGGER product_variants_catalog AFTER INSERT OR UPDATE OR DELETE ON product_variants
+    FOR EACH ROW EXECUTE FUNCTION touch_shop_catalog('product', 'product_id');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS product_variants_catalog ON product_variants;
+
+DROP INDEX IF EXISTS idx_offers_variant_id;
+ALTER TABLE offers DROP COLUMN IF EXISTS variant_id;
+
+-- из остатков вариантов одного товара на точке остается позиция первого варианта
+DELETE FROM shop_point_inventory a USING shop_point_inventory b
+WHERE a.shop_point_id = b.shop_point_id AND a.product_id = b.product_id AND a.variant_id > b.variant_id;
+DROP INDEX IF EXISTS idx_shop_point_inventory_variant_id;
+ALTER TABLE shop_point_inventory
+    DROP CONSTRAINT IF EXISTS uq_shop_point_inventory_point_variant,
+    DROP COLUMN IF EXISTS variant_id,
+    ADD CONSTRAINT uq_shop_point_inventory_point_product UNIQUE (shop_point_id, product_id);
+
+DROP TABLE IF EXISTS product_variants;
+-- +goose StatementEnd
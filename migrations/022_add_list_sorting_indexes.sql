@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE products ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- индексы повторяют порядок сортировки списков вместе с айди для листания курсором
+CREATE INDEX idx_products_created_at_id ON products(created_at, id);
+CREATE INDEX idx_products_name_id ON products(name, id);
+CREATE INDEX idx_offers_user_id_created_at_id ON offers(user_id, created_at, id);
+CREATE INDEX idx_notifications_user_id_sent_at_id ON notifications(user_id, sent_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notifications_user_id_sent_at_id;
+DROP INDEX IF EXISTS idx_offers_user_id_created_at_id;
+DROP INDEX IF EXISTS idx_products_name_id;
+DROP INDEX IF EXISTS idx_products_created_at_id;
+
+ALTER TABLE products DROP COLUMN IF EXISTS created_at;
+-- +goose StatementEnd
@@ -0,0 +1,68 @@
+-- +goose Up
+-- +goose StatementBegin
+-- price цена товара по умолчанию, если на точках продаж она не задана
+ALTER TABLE products ADD COLUMN price DECIMAL(10,2);
+
+-- история цен: строка на каждое изменение цены варианта на точке продаж
+-- или цены товара по умолчанию (тогда variant_id и shop_point_id пустые)
+CREATE TABLE price_history (
+    id BIGSERIAL PRIMARY KEY,
+    product_id INT NOT NULL,
+    variant_id INT,
+    shop_point_id INT,
+    price DECIMAL(10,2) NOT NULL,
+    previous_price DECIMAL(10,2),
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- notified_at время обработки снижения цены, пустое у необработанных снижений
+    notified_at TIMESTAMP,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE CASCADE,
+    FOREIGN KEY (shop_point_id) REFERENCES shop_points(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_price_history_product_id_changed_at ON price_history(product_id, changed_at);
+CREATE INDEX idx_price_history_pending_drops ON price_history(id)
+    WHERE notified_at IS NULL AND price < previous_price;
+
+-- record_price_change пишет в историю новую цену строки, если она изменилась
+CREATE FUNCTION record_price_change() RETURNS TRIGGER AS $$
+DECLARE
+    old_price DECIMAL(10,2);
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        old_price := OLD.price;
+    END IF;
+    IF NEW.price IS NULL OR NEW.price IS NOT DISTINCT FROM old_price THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_TABLE_NAME = 'shop_point_inventory' THEN
+        INSERT INTO price_history (product_id, variant_id, shop_point_id, price, previous_price)
+        VALUES (NEW.product_id, NEW.variant_id, NEW.shop_point_id, NEW.price, old_price);
+    ELSE
+        INSERT INTO price_history (product_id, price, previous_price)
+        VALUES (NEW.id, NEW.price, old_price);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER shop_point_inventory_price_history AFTER INSERT OR UPDATE OF price ON shop_point_inventory
+    FOR EACH ROW EXECUTE FUNCTION record_price_change();
+CREATE TRIGGER products_price_history AFTER INSERT OR UPDATE OF price ON products
+    FOR EACH ROW EXECUTE FUNCTION record_price_change();
+
+-- текущие цены становятся началом истории
+INSERT INTO price_history (product_id, variant_id, shop_point_id, price)
+SELECT product_id, variant_id, shop_point_id, price FROM shop_point_inventory WHERE price IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS products_price_history ON products;
+DROP TRIGGER IF EXISTS shop_point_inventory_price_history ON shop_point_inventory;
+DROP FUNCTION IF EXISTS record_price_change();
+DROP TABLE IF EXISTS price_history;
+ALTER TABLE products DROP COLUMN IF EXISTS price;
+-- +goose StatementEnd
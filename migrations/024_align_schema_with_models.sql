@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE offers RENAME COLUMN offer_price TO price;
+
+-- магазин, которому адресовано предложение, и срок его действия
+ALTER TABLE offers
+    ADD COLUMN store_id INT,
+    ADD COLUMN expires_at TIMESTAMP,
+    ADD FOREIGN KEY (store_id) REFERENCES shops(id);
+
+-- старые предложения привязываются к магазину, если товар продается только в одном
+UPDATE offers o SET store_id = si.shop_id
+FROM (
+    SELECT product_id, MIN(shop_id) AS shop_id FROM shop_inventory
+    GROUP BY product_id HAVING COUNT(*) = 1
+) si
+WHERE si.product_id = o.product_id;
+
+UPDATE offers SET expires_at = created_at + INTERVAL '24 hours';
+ALTER TABLE offers ALTER COLUMN expires_at SET NOT NULL;
+
+CREATE INDEX idx_offers_store_id ON offers(store_id);
+
+ALTER TABLE products ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+UPDATE products SET updated_at = created_at;
+
+ALTER TABLE shops
+    ADD COLUMN description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE shops
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS description;
+
+ALTER TABLE products DROP COLUMN IF EXISTS updated_at;
+
+DROP INDEX IF EXISTS idx_offers_store_id;
+ALTER TABLE offers
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS store_id;
+
+ALTER TABLE offers RENAME COLUMN price TO offer_price;
+-- +goose StatementEnd
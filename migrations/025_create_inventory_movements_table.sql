@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE shop_point_inventory ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- inventory_movements журнал изменений позиций на точках продаж:
+-- delta — изменение остатка, quantity и price — остаток и цена после изменения
+CREATE TABLE inventory_movements (
+    id BIGSERIAL PRIMARY KEY,
+    shop_point_id INT NOT NULL,
+    product_id INT NOT NULL,
+    variant_id INT NOT NULL,
+    delta INT NOT NULL,
+    quantity INT NOT NULL,
+    price DECIMAL(10,2) NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    user_id INT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (shop_point_id) REFERENCES shop_points(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    CHECK (reason IN ('delivery', 'sale', 'spoilage', 'return', 'correction', 'import'))
+);
+
+CREATE INDEX idx_inventory_movements_point_created_at_id ON inventory_movements(shop_point_id, created_at, id);
+CREATE INDEX idx_inventory_movements_variant_id ON inventory_movements(variant_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS inventory_movements;
+ALTER TABLE shop_point_inventory DROP COLUMN IF EXISTS updated_at;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- low_stock_thresholds пороги остатка магазина: для товара или для категории вместе с подкатегориями.
+-- Позиция считается заканчивающейся, когда ее остаток не больше порога.
+CREATE TABLE low_stock_thresholds (
+    id SERIAL PRIMARY KEY,
+    shop_id INT NOT NULL,
+    product_id INT,
+    category_id INT,
+    threshold INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE,
+    CHECK ((product_id IS NULL) <> (category_id IS NULL)),
+    CHECK (threshold >= 0)
+);
+
+CREATE UNIQUE INDEX uq_low_stock_thresholds_shop_product
+    ON low_stock_thresholds(shop_id, product_id) WHERE product_id IS NOT NULL;
+CREATE UNIQUE INDEX uq_low_stock_thresholds_shop_category
+    ON low_stock_thresholds(shop_id, category_id) WHERE category_id IS NOT NULL;
+
+-- alert_checked_at время проверки уменьшения остатка на пересечение порога,
+-- уже записанные движения проверять не нужно
+ALTER TABLE inventory_movements ADD COLUMN alert_checked_at TIMESTAMP;
+UPDATE inventory_movements SET alert_checked_at = now();
+
+CREATE INDEX idx_inventory_movements_pending_alerts
+    ON inventory_movements(id) WHERE delta < 0 AND alert_checked_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_inventory_movements_pending_alerts;
+ALTER TABLE inventory_movements DROP COLUMN IF EXISTS alert_checked_at;
+DROP TABLE IF EXISTS low_stock_thresholds;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE shop_points
+    ADD COLUMN latitude DOUBLE PRECISION,
+    ADD COLUMN longitude DOUBLE PRECISION,
+    ADD CONSTRAINT chk_shop_points_coordinates CHECK (
+        (latitude IS NULL) = (longitude IS NULL)
+        AND latitude BETWEEN -90 AND 90
+        AND longitude BETWEEN -180 AND 180
+    );
+
+-- поиск ближайших точек сначала отбирает их по ограничивающему прямоугольнику
+CREATE INDEX idx_shop_points_latitude_longitude ON shop_points(latitude, longitude)
+    WHERE latitude IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_shop_points_latitude_longitude;
+ALTER TABLE shop_points
+    DROP CONSTRAINT IF EXISTS chk_shop_points_coordinates,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE shop_points ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
+
+-- недельное расписание: несколько периодов в день допускают перерыв,
+-- closes_at не позже opens_at означает работу за полночь
+CREATE TABLE shop_point_hours (
+    id SERIAL PRIMARY KEY,
+    shop_point_id INT NOT NULL,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    opens_at TIME NOT NULL,
+    closes_at TIME NOT NULL,
+    FOREIGN KEY (shop_point_id) REFERENCES shop_points(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_shop_point_hours_shop_point_id ON shop_point_hours(shop_point_id, weekday);
+
+-- особые дни заменяют недельное расписание: без часов точка в этот день закрыта
+CREATE TABLE shop_point_exceptions (
+    id SERIAL PRIMARY KEY,
+    shop_point_id INT NOT NULL,
+    date DATE NOT NULL,
+    opens_at TIME,
+    closes_at TIME,
+    note TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (shop_point_id) REFERENCES shop_points(id) ON DELETE CASCADE,
+    UNIQUE (shop_point_id, date),
+    CHECK ((opens_at IS NULL) = (closes_at IS NULL))
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shop_point_exceptions;
+DROP TABLE IF EXISTS shop_point_hours;
+ALTER TABLE shop_points DROP COLUMN IF EXISTS timezone;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- индексы повторяют порядок сортировки списка магазинов вместе с айди для листания курсором
+CREATE INDEX idx_shops_created_at_id ON shops(created_at, id);
+CREATE INDEX idx_shops_name_id ON shops(name, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_shops_name_id;
+DROP INDEX IF EXISTS idx_shops_created_at_id;
+-- +goose StatementEnd
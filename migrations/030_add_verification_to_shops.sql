@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+-- verification_status этап проверки магазина администратором:
+-- draft, submitted, verified, rejected или suspended
+ALTER TABLE shops
+    ADD COLUMN verification_status TEXT NOT NULL DEFAULT 'draft',
+    ADD COLUMN legal_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN tax_id VARCHAR(12) NOT NULL DEFAULT '',
+    ADD COLUMN legal_address TEXT NOT NULL DEFAULT '',
+    ADD COLUMN verification_comment TEXT NOT NULL DEFAULT '',
+    ADD COLUMN submitted_at TIMESTAMP,
+    ADD COLUMN reviewed_at TIMESTAMP,
+    ADD CONSTRAINT chk_shops_verification_status
+        CHECK (verification_status IN ('draft', 'submitted', 'verified', 'rejected', 'suspended'));
+
+-- магазины, работавшие до появления проверки, остаются в каталоге
+UPDATE shops SET verification_status = 'verified', reviewed_at = now();
+
+CREATE INDEX idx_shops_verification_status ON shops(verification_status);
+
+-- shop_documents документы, приложенные владельцем к заявке на проверку магазина
+CREATE TABLE shop_documents (
+    id SERIAL PRIMARY KEY,
+    shop_id INT NOT NULL,
+    kind TEXT NOT NULL,
+    key TEXT NOT NULL UNIQUE,
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_shop_documents_shop_id ON shop_documents(shop_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shop_documents;
+
+DROP INDEX IF EXISTS idx_shops_verification_status;
+
+ALTER TABLE shops
+    DROP CONSTRAINT IF EXISTS chk_shops_verification_status,
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS submitted_at,
+    DROP COLUMN IF EXISTS verification_comment,
+    DROP COLUMN IF EXISTS legal_address,
+    DROP COLUMN IF EXISTS tax_id,
+    DROP COLUMN IF EXISTS legal_name,
+    DROP COLUMN IF EXISTS verification_status;
+-- +goose StatementEnd
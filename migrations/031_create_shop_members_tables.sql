@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+-- shop_members сотрудники магазина с ролями owner, manager и cashier.
+-- shops.user_id остается владельцем, для него заводится строка с ролью owner.
+CREATE TABLE shop_members (
+    id SERIAL PRIMARY KEY,
+    shop_id INT NOT NULL,
+    user_id INT NOT NULL,
+    role TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE (shop_id, user_id),
+    CHECK (role IN ('owner', 'manager', 'cashier'))
+);
+
+CREATE INDEX idx_shop_members_user_id ON shop_members(user_id);
+
+INSERT INTO shop_members (shop_id, user_id, role)
+SELECT id, user_id, 'owner' FROM shops;
+
+-- shop_invitations приглашения сотрудников по почте, токен из письма хранится в виде хеша
+CREATE TABLE shop_invitations (
+    id SERIAL PRIMARY KEY,
+    shop_id INT NOT NULL,
+    email TEXT NOT NULL,
+    role TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    invited_by INT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE,
+    FOREIGN KEY (invited_by) REFERENCES users(id) ON DELETE CASCADE,
+    CHECK (role IN ('manager', 'cashier'))
+);
+
+-- на один адрес в магазине действует одно неиспользованное приглашение
+CREATE UNIQUE INDEX uq_shop_invitations_pending
+    ON shop_invitations(shop_id, lower(email)) WHERE accepted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shop_invitations;
+DROP TABLE IF EXISTS shop_members;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- responded_at когда магазин впервые ответил на предложение, rating оценка покупателя после сделки
+ALTER TABLE offers
+    ADD COLUMN responded_at TIMESTAMP,
+    ADD COLUMN rating SMALLINT,
+    ADD CONSTRAINT chk_offers_rating CHECK (rating BETWEEN 1 AND 5);
+
+UPDATE offers SET responded_at = updated_at WHERE status <> 'pending';
+
+-- shop_stats статистика магазина по предложениям, пересчитывается периодически, а не на каждый запрос.
+-- closed_count предложения, на которые магазин ответил или которые истекли без ответа.
+CREATE TABLE shop_stats (
+    shop_id INT PRIMARY KEY,
+    offer_count INT NOT NULL DEFAULT 0,
+    closed_count INT NOT NULL DEFAULT 0,
+    accepted_count INT NOT NULL DEFAULT 0,
+    avg_response_seconds DOUBLE PRECISION,
+    avg_rating DOUBLE PRECISION,
+    rating_count INT NOT NULL DEFAULT 0,
+    refreshed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shop_stats;
+ALTER TABLE offers
+    DROP CONSTRAINT IF EXISTS chk_offers_rating,
+    DROP COLUMN IF EXISTS rating,
+    DROP COLUMN IF EXISTS responded_at;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- shop_point_id точка, где покупатель хочет забрать товар, list_price цена магазина
+-- на момент предложения, от нее считается скидка. completed_at время выдачи товара.
+ALTER TABLE offers
+    ADD COLUMN shop_point_id INT REFERENCES shop_points(id) ON DELETE SET NULL,
+    ADD COLUMN list_price DECIMAL(10,2),
+    ADD COLUMN completed_at TIMESTAMP;
+
+CREATE INDEX idx_offers_store_id_created_at ON offers(store_id, created_at);
+CREATE INDEX idx_offers_store_id_completed_at ON offers(store_id, completed_at) WHERE completed_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_offers_store_id_completed_at;
+DROP INDEX IF EXISTS idx_offers_store_id_created_at;
+ALTER TABLE offers
+    DROP COLUMN IF EXISTS completed_at,
+    DROP COLUMN IF EXISTS list_price,
+    DROP COLUMN IF EXISTS shop_point_id;
+-- +goose StatementEnd
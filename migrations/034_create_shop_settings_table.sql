@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- shop_settings правила торга магазина. max_rounds и max_discount без значения не ограничивают
+-- покупателя, max_discount доля от цены магазина. Строка создается вместе с магазином.
+CREATE TABLE shop_settings (
+    shop_id INT PRIMARY KEY,
+    offer_ttl_minutes INT NOT NULL DEFAULT 1440,
+    max_rounds INT,
+    max_discount DECIMAL(5,4),
+    accepts_offers BOOLEAN NOT NULL DEFAULT TRUE,
+    currency CHAR(3) NOT NULL DEFAULT 'RUB',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE,
+    CONSTRAINT chk_shop_settings_offer_ttl CHECK (offer_ttl_minutes > 0),
+    CONSTRAINT chk_shop_settings_max_rounds CHECK (max_rounds > 0),
+    CONSTRAINT chk_shop_settings_max_discount CHECK (max_discount > 0 AND max_discount < 1)
+);
+
+INSERT INTO shop_settings (shop_id) SELECT id FROM shops;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shop_settings;
+-- +goose StatementEnd
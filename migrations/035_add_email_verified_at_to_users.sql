@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- email_verified_at когда пользователь подтвердил текущую почту, сбрасывается при ее смене
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+
+-- пользователи, зарегистрированные до проверки почты, считаются подтвердившими ее,
+-- иначе после выкладки никто из них не сможет делать предложения и открывать магазины
+UPDATE users SET email_verified_at = now();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
+-- +goose StatementEnd
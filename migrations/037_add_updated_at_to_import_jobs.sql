@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- updated_at отметка жизни задания: обработчик обновляет ее вместе с прогрессом,
+-- по ней задание без движения считается брошенным
+ALTER TABLE import_jobs ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE import_jobs DROP COLUMN IF EXISTS updated_at;
+-- +goose StatementEnd
@@ -0,0 +1,83 @@
+package geo
+
+import "math"
+
+// EarthRadiusKm средний радиус Земли в километрах
+const EarthRadiusKm = 6371.0
+
+const (
+	// DefaultSearchRadiusKm радиус поиска, если он не задан
+	DefaultSearchRadiusKm = 5
+	// MaxSearchRadiusKm ограничивает радиус, чтобы поиск не превращался в перебор всех точек
+	MaxSearchRadiusKm = 100
+)
+
+// Point координаты в градусах
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+// Valid сообщает, что широта и долгота лежат в допустимых пределах
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180
+}
+
+// Circle область поиска: все точки не дальше RadiusKm от центра
+type Circle struct {
+	Center   Point
+	RadiusKm float64
+}
+
+// Box прямоугольник в градусах широты и долготы
+type Box struct {
+	MinLat, MaxLat float64
+	MinLon, MaxLon float64
+}
+
+// WithDefaults подставляет радиус по умолчанию, если он не задан
+func (c Circle) WithDefaults() Circle {
+	if c.RadiusKm == 0 {
+		c.RadiusKm = DefaultSearchRadiusKm
+	}
+	return c
+}
+
+// Valid сообщает, что центр задан корректно, а радиус положителен и не больше MaxSearchRadiusKm
+func (c Circle) Valid() bool {
+	return c.Center.Valid() && c.RadiusKm > 0 && c.RadiusKm <= MaxSearchRadiusKm
+}
+
+// BoundingBox прямоугольник, в котором целиком лежит круг. Им удобно отсечь заведомо
+// далекие точки по индексу до точного расчета расстояния. Если круг захватывает полюс
+// или пересекает 180-й меридиан, долгота не ограничивается.
+func (c Circle) BoundingBox() Box {
+	angle := c.RadiusKm / EarthRadiusKm
+	box := Box{
+		MinLat: c.Center.Lat - degrees(angle),
+		MaxLat: c.Center.Lat + degrees(angle),
+		MinLon: -180,
+		MaxLon: 180,
+	}
+	if box.MinLat <= -90 || box.MaxLat >= 90 {
+		box.MinLat = math.Max(box.MinLat, -90)
+		box.MaxLat = math.Min(box.MaxLat, 90)
+		return box
+	}
+
+	deltaLon := degrees(math.Asin(math.Sin(angle) / math.Cos(radians(c.Center.Lat))))
+	if c.Center.Lon-deltaLon >= -180 && c.Center.Lon+deltaLon <= 180 {
+		box.MinLon = c.Center.Lon - deltaLon
+		box.MaxLon = c.Center.Lon + deltaLon
+	}
+
+	return box
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
@@ -0,0 +1,292 @@
+package objectstorage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalRoutePrefix путь, по которому приложение раздает и принимает объекты LocalStorage.
+const LocalRoutePrefix = "/storage/"
+
+var (
+	ErrInvalidKey       = errors.New("invalid object key")
+	ErrInvalidSignature = errors.New("invalid or expired signature")
+)
+
+// LocalStorage хранит объекты в каталоге на диске. Подписанные ссылки
+// указывают на само приложение, которое проверяет подпись перед чтением или записью.
+type LocalStorage struct {
+	root    string
+	baseURL string
+	secret  []byte
+}
+
+func NewLocalStorage(root, baseURL, secret string) (*LocalStorage, error) {
+	if secret == "" {
+		return nil, errors.New("local object storage requires a signing secret")
+	}
+
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	return &LocalStorage{
+		root:    root,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  []byte(secret),
+	}, nil
+}
+
+func (ls *LocalStorage) PutObject(
+	ctx context.Context,
+	objectKey string,
+	body io.Reader,
+	size int64,
+	contentType string,
+) error {
+	filePath, err := ls.path(objectKey)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return err
+	}
+
+	// пишем во временный файл, чтобы читатели не увидели объект наполовину
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, io.LimitReader(body, size+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if written != size {
+		return fmt.Errorf("object size mismatch: expected %d bytes, got %d", size, written)
+	}
+
+	return os.Rename(tmp.Name(), filePath)
+}
+
+func (ls *LocalStorage) GetObject(ctx context.Context, objectKey string) (io.ReadCloser, ObjectInfo, error) {
+	info, err := ls.HeadObject(ctx, objectKey)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	filePath, err := ls.path(objectKey)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ObjectInfo{}, ErrObjectNotFound
+		}
+		return nil, ObjectInfo{}, err
+	}
+
+	return file, info, nil
+}
+
+func (ls *LocalStorage) DeleteObject(ctx context.Context, objectKey string) error {
+	filePath, err := ls.path(objectKey)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (ls *LocalStorage) HeadObject(ctx context.Context, objectKey string) (ObjectInfo, error) {
+	filePath, err := ls.path(objectKey)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	stat, err := os.Stat(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ObjectInfo{}, ErrObjectNotFound
+		}
+		return ObjectInfo{}, err
+	}
+	if stat.IsDir() {
+		return ObjectInfo{}, ErrObjectNotFound
+	}
+
+	return fileInfo(objectKey, stat), nil
+}
+
+func (ls *LocalStorage) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	err := filepath.WalkDir(ls.root, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(ls.root, filePath)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		stat, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, fileInfo(key, stat))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+// PresignPutObject подписывает загрузку через приложение. Как и в S3,
+// Content-Type и Content-Length входят в подпись.
+func (ls *LocalStorage) PresignPutObject(
+	ctx context.Context,
+	objectKey string,
+	contentType string,
+	contentLength int64,
+	ttl time.Duration,
+) (PresignedRequest, error) {
+	if _, err := ls.path(objectKey); err != nil {
+		return PresignedRequest{}, err
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Length", strconv.FormatInt(contentLength, 10))
+
+	return PresignedRequest{
+		URL:       ls.signedURL(http.MethodPut, objectKey, contentType, contentLength, expiresAt),
+		Method:    http.MethodPut,
+		Header:    header,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (ls *LocalStorage) PresignGetObject(
+	ctx context.Context,
+	objectKey string,
+	ttl time.Duration,
+) (PresignedRequest, error) {
+	if _, err := ls.path(objectKey); err != nil {
+		return PresignedRequest{}, err
+	}
+
+	expiresAt := time.Now().Add(ttl)
+
+	return PresignedRequest{
+		URL:       ls.signedURL(http.MethodGet, objectKey, "", 0, expiresAt),
+		Method:    http.MethodGet,
+		Header:    http.Header{},
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// ObjectURL возвращает адрес объекта в приложении. Чтение, как у публичного бакета,
+// подписи не требует.
+func (ls *LocalStorage) ObjectURL(objectKey string) string {
+	return ls.baseURL + LocalRoutePrefix + objectKey
+}
+
+// VerifySignature проверяет подпись ссылки, выданной PresignPutObject или PresignGetObject.
+func (ls *LocalStorage) VerifySignature(
+	method, objectKey, contentType string,
+	contentLength int64,
+	expires, signature string,
+) error {
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresUnix {
+		return ErrInvalidSignature
+	}
+
+	expected := ls.sign(method, objectKey, contentType, contentLength, expiresUnix)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func (ls *LocalStorage) signedURL(
+	method, objectKey, contentType string,
+	contentLength int64,
+	expiresAt time.Time,
+) string {
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("signature", ls.sign(method, objectKey, contentType, contentLength, expiresAt.Unix()))
+
+	return ls.ObjectURL(objectKey) + "?" + query.Encode()
+}
+
+func (ls *LocalStorage) sign(method, objectKey, contentType string, contentLength, expires int64) string {
+	mac := hmac.New(sha256.New, ls.secret)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%d\n%d", method, objectKey, contentType, contentLength, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path переводит ключ в путь внутри корневого каталога, не давая выйти за его пределы.
+func (ls *LocalStorage) path(objectKey string) (string, error) {
+	cleaned := path.Clean("/" + objectKey)
+	if objectKey == "" || cleaned == "/" || cleaned[1:] != objectKey {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(ls.root, filepath.FromSlash(cleaned[1:])), nil
+}
+
+func fileInfo(objectKey string, stat fs.FileInfo) ObjectInfo {
+	contentType := mime.TypeByExtension(path.Ext(objectKey))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return ObjectInfo{
+		Key:          objectKey,
+		Size:         stat.Size(),
+		ContentType:  contentType,
+		ETag:         fmt.Sprintf("\"%x-%x\"", stat.ModTime().UnixNano(), stat.Size()),
+		LastModified: stat.ModTime(),
+	}
+}
@@ -0,0 +1,227 @@
+package objectstorage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/config"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	sdkConfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+type BucketBasics struct {
+	BucketName string
+	S3Client   *s3.Client
+	// PublicURL базовый адрес, по которому объекты бакета доступны на чтение
+	PublicURL string
+}
+
+func ObjectStorageConn(cfg *config.Config) (*BucketBasics, error) {
+	sdkCfg, err := sdkConfig.LoadDefaultConfig(context.TODO(),
+		sdkConfig.WithClientLogMode(aws.LogRequestWithBody|aws.LogResponseWithBody),
+		sdkConfig.WithRegion(cfg.SigningRegion),
+		sdkConfig.WithCredentialsProvider(aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{
+				AccessKeyID:     cfg.AccessKey,
+				SecretAccessKey: cfg.SecretKey,
+			}, nil
+		})),
+		sdkConfig.WithEndpointResolver(aws.EndpointResolverFunc(func(service, region string) (aws.Endpoint, error) {
+			return aws.Endpoint{
+				URL:           cfg.URL,
+				SigningRegion: cfg.SigningRegion,
+			}, nil
+		})),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &BucketBasics{
+		BucketName: cfg.BucketName,
+		S3Client:   s3.NewFromConfig(sdkCfg),
+		PublicURL:  strings.TrimRight(cfg.URL, "/") + "/" + cfg.BucketName,
+	}, nil
+}
+
+// PutObject загружает объект в бакет напрямую, не читая его целиком в память.
+func (basics BucketBasics) PutObject(
+	ctx context.Context,
+	objectKey string,
+	body io.Reader,
+	size int64,
+	contentType string,
+) error {
+	_, err := basics.S3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(basics.BucketName),
+		Key:           aws.String(objectKey),
+		Body:          body,
+		ContentLength: aws.Int64(size),
+		ContentType:   aws.String(contentType),
+	})
+	if err != nil {
+		log.Printf("Couldn't upload object %v:%v. Here's why: %v\n", basics.BucketName, objectKey, err)
+		return err
+	}
+
+	return nil
+}
+
+func (basics BucketBasics) GetObject(ctx context.Context, objectKey string) (io.ReadCloser, ObjectInfo, error) {
+	result, err := basics.S3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(basics.BucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		var noKey *types.NoSuchKey
+		if errors.As(err, &noKey) {
+			return nil, ObjectInfo{}, ErrObjectNotFound
+		}
+		log.Printf("Couldn't get object %v:%v. Here's why: %v\n", basics.BucketName, objectKey, err)
+		return nil, ObjectInfo{}, err
+	}
+
+	return result.Body, ObjectInfo{
+		Key:          objectKey,
+		Size:         aws.ToInt64(result.ContentLength),
+		ContentType:  aws.ToString(result.ContentType),
+		ETag:         aws.ToString(result.ETag),
+		LastModified: aws.ToTime(result.LastModified),
+	}, nil
+}
+
+func (basics BucketBasics) DeleteObject(ctx context.Context, objectKey string) error {
+	_, err := basics.S3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(basics.BucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		log.Printf("Couldn't delete object %v:%v. Here's why: %v\n", basics.BucketName, objectKey, err)
+		return err
+	}
+
+	return nil
+}
+
+// HeadObject возвращает метаданные объекта без загрузки его содержимого.
+func (basics BucketBasics) HeadObject(ctx context.Context, objectKey string) (ObjectInfo, error) {
+	result, err := basics.S3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(basics.BucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return ObjectInfo{}, ErrObjectNotFound
+		}
+		log.Printf("Couldn't head object %v:%v. Here's why: %v\n", basics.BucketName, objectKey, err)
+		return ObjectInfo{}, err
+	}
+
+	return ObjectInfo{
+		Key:          objectKey,
+		Size:         aws.ToInt64(result.ContentLength),
+		ContentType:  aws.ToString(result.ContentType),
+		ETag:         aws.ToString(result.ETag),
+		LastModified: aws.ToTime(result.LastModified),
+	}, nil
+}
+
+// ListObjects возвращает все объекты бакета с указанным префиксом.
+func (basics BucketBasics) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	paginator := s3.NewListObjectsV2Paginator(basics.S3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(basics.BucketName),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Couldn't list objects in %v:%v. Here's why: %v\n", basics.BucketName, prefix, err)
+			return nil, err
+		}
+
+		for _, obj := range page.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:          aws.ToString(obj.Key),
+				Size:         aws.ToInt64(obj.Size),
+				ETag:         aws.ToString(obj.ETag),
+				LastModified: aws.ToTime(obj.LastModified),
+			})
+		}
+	}
+
+	return objects, nil
+}
+
+// PresignPutObject выдает короткоживущую подписанную ссылку для загрузки объекта
+// клиентом напрямую в бакет. Content-Type и Content-Length входят в подпись,
+// поэтому хранилище отклонит загрузку с другими значениями.
+func (basics BucketBasics) PresignPutObject(
+	ctx context.Context,
+	objectKey string,
+	contentType string,
+	contentLength int64,
+	ttl time.Duration,
+) (PresignedRequest, error) {
+	presignClient := s3.NewPresignClient(basics.S3Client)
+	presignResult, err := presignClient.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(basics.BucketName),
+		Key:           aws.String(objectKey),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(contentLength),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		log.Printf("Couldn't get presigned URL for upload. Here's why: %v\n", err)
+		return PresignedRequest{}, err
+	}
+
+	header := presignResult.SignedHeader.Clone()
+	header.Del("Host")
+
+	return PresignedRequest{
+		URL:       presignResult.URL,
+		Method:    presignResult.Method,
+		Header:    header,
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// PresignGetObject выдает короткоживущую ссылку на чтение объекта.
+func (basics BucketBasics) PresignGetObject(
+	ctx context.Context,
+	objectKey string,
+	ttl time.Duration,
+) (PresignedRequest, error) {
+	presignClient := s3.NewPresignClient(basics.S3Client)
+	presignResult, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(basics.BucketName),
+		Key:    aws.String(objectKey),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		log.Printf("Couldn't get presigned URL for download. Here's why: %v\n", err)
+		return PresignedRequest{}, err
+	}
+
+	header := presignResult.SignedHeader.Clone()
+	header.Del("Host")
+
+	return PresignedRequest{
+		URL:       presignResult.URL,
+		Method:    presignResult.Method,
+		Header:    header,
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// ObjectURL возвращает публичный адрес объекта.
+func (basics BucketBasics) ObjectURL(objectKey string) string {
+	return basics.PublicURL + "/" + objectKey
+}
@@ -0,0 +1,75 @@
+package objectstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/zuzaaa-dev/stawberry/config"
+)
+
+const (
+	DriverS3    = "s3"
+	DriverLocal = "local"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// Storage общий интерфейс объектного хранилища.
+type Storage interface {
+	PutObject(ctx context.Context, objectKey string, body io.Reader, size int64, contentType string) error
+	// GetObject открывает объект на чтение, закрыть его должен вызывающий.
+	GetObject(ctx context.Context, objectKey string) (io.ReadCloser, ObjectInfo, error)
+	DeleteObject(ctx context.Context, objectKey string) error
+	HeadObject(ctx context.Context, objectKey string) (ObjectInfo, error)
+	ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	PresignPutObject(
+		ctx context.Context,
+		objectKey string,
+		contentType string,
+		contentLength int64,
+		ttl time.Duration,
+	) (PresignedRequest, error)
+	PresignGetObject(ctx context.Context, objectKey string, ttl time.Duration) (PresignedRequest, error)
+	// ObjectURL возвращает постоянный адрес объекта для публичного чтения.
+	ObjectURL(objectKey string) string
+}
+
+// PresignedRequest описывает запрос, который клиент должен выполнить сам.
+type PresignedRequest struct {
+	URL       string
+	Method    string
+	Header    http.Header
+	ExpiresAt time.Time
+}
+
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+// New создает хранилище, выбранное в конфигурации.
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.StorageDriver {
+	case DriverS3:
+		basics, err := ObjectStorageConn(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return basics, nil
+	case DriverLocal:
+		local, err := NewLocalStorage(cfg.StorageLocalDir, cfg.StorageLocalBaseURL, cfg.StorageSigningSecret)
+		if err != nil {
+			return nil, err
+		}
+		return local, nil
+	default:
+		return nil, fmt.Errorf("unknown object storage driver %q", cfg.StorageDriver)
+	}
+}
@@ -0,0 +1,106 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Params параметры выборки списка. Без курсора список листается
+// по номеру страницы через Offset, с курсором — по ключу сортировки.
+type Params struct {
+	Limit  int
+	Offset int
+	Cursor *Cursor
+	Sort   string
+	Desc   bool
+}
+
+// Keyset сообщает, что список листается курсором.
+func (p Params) Keyset() bool {
+	return p.Cursor != nil
+}
+
+// Cursor положение в списке: значение поля сортировки и айди граничной записи.
+// Сортировка сохраняется в курсоре, чтобы следующая страница шла в том же порядке.
+type Cursor struct {
+	Sort   string `json:"s"`
+	Desc   bool   `json:"d,omitempty"`
+	Value  string `json:"v"`
+	ID     uint   `json:"id"`
+	Before bool   `json:"b,omitempty"`
+}
+
+// Encode возвращает курсор в непрозрачном для клиента виде.
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Sort == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return c, nil
+}
+
+// Page сведения о полученной странице. Total известен только при листании
+// по номеру страницы, при листании курсором количество записей не считается.
+type Page struct {
+	Total *int
+	Next  *Cursor
+	Prev  *Cursor
+}
+
+// Finish обрезает лишнюю запись, которой репозиторий проверяет наличие следующей
+// страницы, восстанавливает порядок при движении назад и строит курсоры соседних страниц.
+// key возвращает значение поля сортировки и айди записи.
+func Finish[T any](items []T, params Params, total *int, key func(T) (string, uint)) ([]T, Page) {
+	page := Page{Total: total}
+
+	var hasNext, hasPrev bool
+	switch {
+	case params.Cursor == nil:
+		hasPrev = params.Offset > 0
+		hasNext = total != nil && params.Offset+len(items) < *total
+	case params.Cursor.Before:
+		hasPrev = len(items) > params.Limit
+		hasNext = true
+	default:
+		hasNext = len(items) > params.Limit
+		hasPrev = true
+	}
+
+	if len(items) > params.Limit {
+		items = items[:params.Limit]
+	}
+	if params.Cursor != nil && params.Cursor.Before {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	if len(items) == 0 {
+		return items, page
+	}
+
+	if hasNext {
+		value, id := key(items[len(items)-1])
+		page.Next = &Cursor{Sort: params.Sort, Desc: params.Desc, Value: value, ID: id}
+	}
+	if hasPrev {
+		value, id := key(items[0])
+		page.Prev = &Cursor{Sort: params.Sort, Desc: params.Desc, Value: value, ID: id, Before: true}
+	}
+
+	return items, page
+}
@@ -0,0 +1,25 @@
+package security
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// tokenSize длина токена для ссылок из писем до кодирования
+const tokenSize = 32
+
+// NewToken создает случайный токен для одноразовых ссылок из писем
+func NewToken() (string, error) {
+	b, err := generateRandomBytes(tokenSize)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// HashToken хеш токена для хранения в базе. Токен случайный и длинный, поэтому соль не нужна.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}